@@ -0,0 +1,301 @@
+/*
+ *  admin.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AdminPath is the path of the admin API relative to the handler.
+const AdminPath = "admin/spec"
+
+const (
+	// PublishAction is the action of a Publication replacing the spec.
+	PublishAction = "publish"
+	// RollbackAction is the action of a Publication restoring the previous spec.
+	RollbackAction = "rollback"
+)
+
+// ErrNoPreviousSpec is returned by Rollback if no spec was replaced yet.
+var ErrNoPreviousSpec = errors.New("no previous spec to roll back to")
+
+// Authenticator identifies the caller of the admin API. It returns false to deny access.
+type Authenticator func(r *http.Request) (principal string, ok bool)
+
+// BearerTokens returns an Authenticator accepting the given tokens in an
+// "Authorization: Bearer" header. The tokens map to the principals they identify.
+func BearerTokens(tokens map[string]string) Authenticator {
+	return func(r *http.Request) (string, bool) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			return "", false
+		}
+		for t, principal := range tokens {
+			if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+				return principal, true
+			}
+		}
+		return "", false
+	}
+}
+
+// Publication records a change of the spec made at runtime.
+type Publication struct {
+	// Action is PublishAction or RollbackAction.
+	Action string `json:"action"`
+	// Principal identifies who made the change.
+	Principal string    `json:"principal"`
+	Time      time.Time `json:"time"`
+	// Version is the info.version of the spec served afterwards.
+	Version string `json:"version,omitempty"`
+	// Digest is the SHA-256 hash of the spec served afterwards, as in "sha256:2c26b4...".
+	Digest   string   `json:"digest"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// publisher holds the state serving requests, so that it can be replaced atomically.
+type publisher struct {
+	active atomic.Pointer[SwaggerUi]
+
+	mu       sync.Mutex // serializes publications
+	previous *SwaggerUi
+	log      []Publication
+}
+
+// state returns the state currently serving requests.
+func (ui *SwaggerUi) state() *SwaggerUi {
+	if ui.publisher != nil {
+		if s := ui.publisher.active.Load(); s != nil {
+			return s
+		}
+	}
+	return ui
+}
+
+// AdminAPI serves an admin API under AdminPath, with requests authenticated by auth:
+//
+//   - GET returns the spec currently served.
+//   - PUT publishes the request body as the new spec, see Publish.
+//   - DELETE rolls back to the previous spec, see Rollback.
+//
+// PUT and DELETE respond with the resulting Publication as JSON.
+func AdminAPI(auth Authenticator) HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.adminAuth = auth
+	}
+}
+
+// AuditLog writes every Publication to w as a line of JSON.
+func AuditLog(w io.Writer) HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.auditLog = w
+	}
+}
+
+// Publish replaces the spec at runtime on behalf of principal. The spec must be
+// an OpenAPI or Swagger 2.0 document. It is checked and processed with the
+// same options as the spec passed to New, and swapped in atomically with all
+// files derived from it once that succeeded. The replaced spec is kept for
+// Rollback. With Store, the spec is put into the store before it is served.
+// Specs merged from upstream services cannot be replaced.
+func (ui *SwaggerUi) Publish(principal string, spec []byte) (Publication, error) {
+	if ui.mergeUpstreams {
+		return Publication{}, errors.New("the spec is merged from upstream services")
+	}
+	p := ui.publisher
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	next, err := ui.prepareSuccessor(spec)
+	if err != nil {
+		return Publication{}, err
+	}
+	if err := ui.storeState(next); err != nil {
+		return Publication{}, err
+	}
+	p.previous = p.active.Swap(next)
+	// The spec passed checkHistory while being prepared.
+	next.addToHistory()
+	return ui.published(PublishAction, principal, next), nil
+}
+
+// Rollback restores the spec served before the last publication on behalf of
+// principal. The replaced spec becomes the previous one, so that a second
+// rollback undoes the first. With Store, the restored spec is put into the
+// store as its latest revision.
+func (ui *SwaggerUi) Rollback(principal string) (Publication, error) {
+	p := ui.publisher
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.previous == nil {
+		return Publication{}, ErrNoPreviousSpec
+	}
+	restored := p.previous
+	if err := ui.storeState(restored); err != nil {
+		return Publication{}, err
+	}
+	p.previous = p.active.Swap(restored)
+	return ui.published(RollbackAction, principal, restored), nil
+}
+
+// Publications returns the record of all publications and rollbacks, oldest first.
+func (ui *SwaggerUi) Publications() []Publication {
+	p := ui.publisher
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Publication(nil), p.log...)
+}
+
+// successor returns a copy of the handler's setup serving spec instead.
+func (ui *SwaggerUi) successor(spec []byte) *SwaggerUi {
+	next := *ui
+	next.specContent = spec
+	next.document = nil
+	next.files = nil
+	next.parts = nil
+	next.warnings = nil
+	return &next
+}
+
+// prepareSuccessor returns the state serving spec, once it passed all checks.
+func (ui *SwaggerUi) prepareSuccessor(spec []byte) (*SwaggerUi, error) {
+	if _, err := parseSpec(spec); err != nil {
+		return nil, SetupError{Cause: errors.New("invalid spec: " + err.Error())}
+	}
+	next := ui.successor(spec)
+	if err := next.prepareSpec(); err != nil {
+		return nil, err
+	}
+	if err := next.setupFiles(); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// storeState puts the spec of the state s into the store, if any, and records its revision.
+func (ui *SwaggerUi) storeState(s *SwaggerUi) error {
+	if ui.store == nil {
+		return nil
+	}
+	stored, err := ui.store.Put(context.Background(), s.specContent)
+	if err != nil {
+		return fmt.Errorf("storing spec: %w", err)
+	}
+	s.revision = stored.Revision
+	return nil
+}
+
+// refresh updates the files that depend on the state serving requests and
+// notifies open browser tabs of the change.
+func (ui *SwaggerUi) refresh() {
+	if ui.aggregator != nil && !ui.mergeUpstreams {
+		// The initializer lists the upstream specs along with the parts of the spec.
+		ui.syncUpstreams()
+	}
+	ui.reloads.notify()
+}
+
+// published records a change to the state s. The caller must hold the publisher's lock.
+func (ui *SwaggerUi) published(action, principal string, s *SwaggerUi) Publication {
+	ui.refresh()
+	sum := sha256.Sum256(s.specContent)
+	pub := Publication{
+		Action:    action,
+		Principal: principal,
+		Time:      time.Now(),
+		Digest:    "sha256:" + hex.EncodeToString(sum[:]),
+		Warnings:  s.warnings,
+	}
+	if doc, err := parseSpec(s.specContent); err == nil && doc.Info != nil {
+		pub.Version = doc.Info.Version
+	}
+	ui.publisher.log = append(ui.publisher.log, pub)
+	if ui.auditLog != nil {
+		json.NewEncoder(ui.auditLog).Encode(pub)
+	}
+	return pub
+}
+
+func (ui *SwaggerUi) serveAdmin(w http.ResponseWriter, r *http.Request) {
+	principal, ok := ui.adminAuth(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="swagger-ui"`)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var (
+		pub Publication
+		err error
+	)
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		s := ui.state()
+		w.Header().Set("Content-Type", "application/yaml")
+		if specExt(s.specContent) == ".json" {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write(s.specContent)
+		return
+	case http.MethodPut:
+		var spec []byte
+		spec, err = io.ReadAll(io.LimitReader(r.Body, maxSpecSize+1))
+		switch {
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case len(spec) > maxSpecSize:
+			http.Error(w, "spec too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		if pub, err = ui.Publish(principal, spec); err != nil {
+			var setupErr SetupError
+			if errors.As(err, &setupErr) {
+				err = setupErr.Cause
+			}
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+	case http.MethodDelete:
+		if pub, err = ui.Rollback(principal); err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pub)
+}
@@ -0,0 +1,143 @@
+/*
+ *  admin_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type AdminSuite struct {
+	suite.Suite
+	ui    *SwaggerUi
+	audit *bytes.Buffer
+}
+
+func (suite *AdminSuite) SetupTest() {
+	suite.audit = new(bytes.Buffer)
+	ui, err := New(Spec("pets.yaml", versionedSpec("1.0", "")),
+		AdminAPI(BearerTokens(map[string]string{"secret": "alice"})), AuditLog(suite.audit))
+	require.NoError(suite.T(), err)
+	suite.ui = ui
+}
+
+// admin sends a request to the admin API, authenticated with token unless it is empty.
+func (suite *AdminSuite) admin(method, token string, body io.Reader) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/"+AdminPath, body)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	suite.ui.ServeHTTP(rec, req)
+	return rec
+}
+
+func (suite *AdminSuite) TestAuthentication() {
+	rec := suite.admin(http.MethodGet, "", nil)
+	assert.Equal(suite.T(), http.StatusUnauthorized, rec.Code)
+	assert.NotEmpty(suite.T(), rec.Header().Get("WWW-Authenticate"))
+
+	rec = suite.admin(http.MethodPut, "wrong", strings.NewReader(string(versionedSpec("2.0", ""))))
+	assert.Equal(suite.T(), http.StatusUnauthorized, rec.Code)
+
+	rec = suite.admin(http.MethodGet, "secret", nil)
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	assert.Contains(suite.T(), rec.Body.String(), `version: "1.0"`)
+}
+
+func (suite *AdminSuite) TestPublish() {
+	rec := suite.admin(http.MethodPut, "secret", bytes.NewReader(versionedSpec("2.0", "/toys")))
+	require.Equal(suite.T(), http.StatusOK, rec.Code, rec.Body.String())
+	var pub Publication
+	require.NoError(suite.T(), json.Unmarshal(rec.Body.Bytes(), &pub))
+	assert.Equal(suite.T(), PublishAction, pub.Action)
+	assert.Equal(suite.T(), "alice", pub.Principal)
+	assert.Equal(suite.T(), "2.0", pub.Version)
+	assert.True(suite.T(), strings.HasPrefix(pub.Digest, "sha256:"))
+
+	code, body := get(suite.ui, "/pets.yaml")
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Contains(suite.T(), body, "/toys")
+	doc, err := suite.ui.Document()
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "2.0", doc.Info.Version)
+
+	var logged Publication
+	require.NoError(suite.T(), json.Unmarshal(suite.audit.Bytes(), &logged))
+	assert.Equal(suite.T(), pub.Digest, logged.Digest)
+	assert.Len(suite.T(), suite.ui.Publications(), 1)
+}
+
+func (suite *AdminSuite) TestPublishInvalid() {
+	rec := suite.admin(http.MethodPut, "secret", strings.NewReader("openapi: 3.0.3\ninfo: [\n"))
+	assert.Equal(suite.T(), http.StatusUnprocessableEntity, rec.Code)
+
+	_, body := get(suite.ui, "/pets.yaml")
+	assert.Contains(suite.T(), body, `version: "1.0"`, "the spec is kept")
+	assert.Empty(suite.T(), suite.ui.Publications())
+	assert.Zero(suite.T(), suite.audit.Len())
+}
+
+func (suite *AdminSuite) TestRollback() {
+	rec := suite.admin(http.MethodDelete, "secret", nil)
+	assert.Equal(suite.T(), http.StatusConflict, rec.Code)
+
+	_, err := suite.ui.Publish("bob", versionedSpec("2.0", ""))
+	require.NoError(suite.T(), err)
+	rec = suite.admin(http.MethodDelete, "secret", nil)
+	require.Equal(suite.T(), http.StatusOK, rec.Code)
+	_, body := get(suite.ui, "/pets.yaml")
+	assert.Contains(suite.T(), body, `version: "1.0"`)
+
+	pub, err := suite.ui.Rollback("bob")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "2.0", pub.Version, "a second rollback undoes the first")
+
+	pubs := suite.ui.Publications()
+	require.Len(suite.T(), pubs, 3)
+	assert.Equal(suite.T(), RollbackAction, pubs[1].Action)
+	assert.Equal(suite.T(), "alice", pubs[1].Principal)
+	assert.Equal(suite.T(), 3, strings.Count(suite.audit.String(), "\n"))
+}
+
+func (suite *AdminSuite) TestMethodNotAllowed() {
+	rec := suite.admin(http.MethodPost, "secret", nil)
+	assert.Equal(suite.T(), http.StatusMethodNotAllowed, rec.Code)
+	assert.NotEmpty(suite.T(), rec.Header().Get("Allow"))
+}
+
+func (suite *AdminSuite) TestDisabled() {
+	ui, err := New(Spec("pets.yaml", versionedSpec("1.0", "")))
+	require.NoError(suite.T(), err)
+	code, _ := get(ui, "/"+AdminPath)
+	assert.Equal(suite.T(), http.StatusNotFound, code)
+}
+
+func TestAdmin(t *testing.T) {
+	suite.Run(t, new(AdminSuite))
+}
@@ -0,0 +1,412 @@
+/*
+ *  builder.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// Package builder declares API operations next to their handlers.
+//
+// Every call to Handle registers the handler on a ServeMux and describes the
+// operation in an OpenAPI 3.1 document at the same time, so that routes and
+// documentation cannot diverge. The document can be fed to a SwaggerUi directly.
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	swaggerui "github.com/mwmahlberg/swagger-ui"
+	"github.com/mwmahlberg/swagger-ui/openapi"
+)
+
+// Version is the OpenAPI version of the documents produced by the builder.
+const Version = "3.1.0"
+
+var pathParamRegex = regexp.MustCompile(`^\{([^{}]+)\}$`)
+
+// API collects the operations of an API together with their handlers.
+type API struct {
+	mux       *http.ServeMux
+	doc       *openapi.Document
+	reflector openapi.Reflector
+	ids       map[string]bool
+	errs      []error
+}
+
+// New returns an API registering its handlers on mux.
+func New(mux *http.ServeMux, title, version string) *API {
+	return &API{
+		mux: mux,
+		doc: &openapi.Document{
+			OpenAPI: Version,
+			Info:    &openapi.Info{Title: title, Version: version},
+			Paths:   make(map[string]*openapi.PathItem),
+		},
+		reflector: openapi.Reflector{OpenAPI31: true},
+		ids:       make(map[string]bool),
+	}
+}
+
+// Description sets the description of the API.
+func (a *API) Description(description string) *API {
+	a.doc.Info.Description = description
+	return a
+}
+
+// Server adds a server hosting the API.
+func (a *API) Server(url, description string) *API {
+	a.doc.Servers = append(a.doc.Servers, &openapi.Server{URL: url, Description: description})
+	return a
+}
+
+// Tag declares a tag used to group operations.
+func (a *API) Tag(name, description string) *API {
+	a.doc.Tags = append(a.doc.Tags, &openapi.Tag{Name: name, Description: description})
+	return a
+}
+
+// SecurityScheme declares a security scheme that operations can refer to by name.
+func (a *API) SecurityScheme(name string, scheme *openapi.SecurityScheme) *API {
+	c := a.components()
+	if c.SecuritySchemes == nil {
+		c.SecuritySchemes = make(map[string]*openapi.SecurityScheme)
+	}
+	c.SecuritySchemes[name] = scheme
+	return a
+}
+
+// Security sets the security requirement applying to all operations by default.
+func (a *API) Security(scheme string, scopes ...string) *API {
+	a.doc.Security = append(a.doc.Security, requirement(scheme, scopes))
+	return a
+}
+
+func (a *API) components() *openapi.Components {
+	if a.doc.Components == nil {
+		a.doc.Components = &openapi.Components{}
+	}
+	return a.doc.Components
+}
+
+// Handle registers h for method and path on the mux and returns the
+// operation for further description. The path uses the OpenAPI template
+// syntax, which is shared by the Go 1.22 ServeMux for whole segments, e.g.
+// "/pets/{id}". Path parameters are declared as strings unless they are
+// described with PathParam.
+//
+// Errors in the declaration are reported by Document.
+func (a *API) Handle(method, p string, h http.Handler) *Operation {
+	method = strings.ToLower(method)
+	op := &Operation{api: a, op: &openapi.Operation{}, method: method, path: p}
+
+	if !validMethod(method) {
+		a.errs = append(a.errs, fmt.Errorf("%s %s: unsupported method", strings.ToUpper(method), p))
+		return op
+	}
+	pattern, params, err := muxPattern(p)
+	if err != nil {
+		a.errs = append(a.errs, fmt.Errorf("%s %s: %w", strings.ToUpper(method), p, err))
+		return op
+	}
+
+	item := a.doc.Paths[p]
+	if item == nil {
+		item = &openapi.PathItem{}
+		a.doc.Paths[p] = item
+	}
+	if item.Operation(method) != nil {
+		a.errs = append(a.errs, fmt.Errorf("%s %s: declared twice", strings.ToUpper(method), p))
+		return op
+	}
+	item.SetOperation(method, op.op)
+	for _, name := range params {
+		op.param(&openapi.Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &openapi.Schema{Type: openapi.TypeOf("string")},
+		})
+	}
+
+	a.mux.Handle(strings.ToUpper(method)+" "+pattern, h)
+	return op
+}
+
+// HandleFunc is like Handle for handler functions.
+func (a *API) HandleFunc(method, p string, h func(http.ResponseWriter, *http.Request)) *Operation {
+	return a.Handle(method, p, http.HandlerFunc(h))
+}
+
+// Document returns the OpenAPI document describing all operations declared so far.
+// The document is shared with the API, so it must not be modified while operations
+// are still being declared.
+func (a *API) Document() (*openapi.Document, error) {
+	if schemas := a.reflector.Schemas(); len(schemas) > 0 {
+		a.components().Schemas = schemas
+	}
+
+	errs := append([]error(nil), a.errs...)
+	var schemes map[string]*openapi.SecurityScheme
+	if a.doc.Components != nil {
+		schemes = a.doc.Components.SecuritySchemes
+	}
+	check := func(where string, reqs openapi.SecurityRequirements) {
+		for _, req := range reqs {
+			for name := range req {
+				if _, ok := schemes[name]; !ok {
+					errs = append(errs, fmt.Errorf("%s: undeclared security scheme %q", where, name))
+				}
+			}
+		}
+	}
+	check("document", a.doc.Security)
+
+	paths := make([]string, 0, len(a.doc.Paths))
+	for p := range a.doc.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		for _, m := range openapi.Methods {
+			if op := a.doc.Paths[p].Operation(m); op != nil {
+				check(strings.ToUpper(m)+" "+p, op.Security)
+			}
+		}
+	}
+	return a.doc, errors.Join(errs...)
+}
+
+// Spec renders the document in the format implied by the extension of
+// filename, which is JSON for ".json" and YAML otherwise.
+func (a *API) Spec(filename string) ([]byte, error) {
+	doc, err := a.Document()
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(path.Ext(filename), ".json") {
+		return doc.JSON()
+	}
+	return doc.YAML()
+}
+
+// SwaggerUi returns a SwaggerUi serving the document as swaggerui.DefaultSpecfileName.
+// Further options are applied after the spec has been set.
+func (a *API) SwaggerUi(opts ...swaggerui.HandlerOption) (*swaggerui.SwaggerUi, error) {
+	spec, err := a.Spec(swaggerui.DefaultSpecfileName)
+	if err != nil {
+		return nil, err
+	}
+	return swaggerui.New(append([]swaggerui.HandlerOption{swaggerui.Spec(swaggerui.DefaultSpecfileName, spec)}, opts...)...)
+}
+
+// Mount registers a SwaggerUi for the document below prefix on the mux, e.g. "/api-docs/".
+// It has to be called after all operations have been declared.
+func (a *API) Mount(prefix string, opts ...swaggerui.HandlerOption) error {
+	ui, err := a.SwaggerUi(opts...)
+	if err != nil {
+		return err
+	}
+	prefix = "/" + strings.Trim(prefix, "/") + "/"
+	a.mux.Handle(prefix, http.StripPrefix(prefix, ui))
+	return nil
+}
+
+// Operation describes a single operation declared with Handle.
+type Operation struct {
+	api    *API
+	op     *openapi.Operation
+	method string
+	path   string
+}
+
+// ID sets the operationId, which must be unique within the API.
+func (o *Operation) ID(id string) *Operation {
+	if o.api.ids[id] {
+		o.api.errs = append(o.api.errs, fmt.Errorf("%s %s: duplicate operationId %q", strings.ToUpper(o.method), o.path, id))
+	}
+	o.api.ids[id] = true
+	o.op.OperationID = id
+	return o
+}
+
+// Summary sets the short summary of the operation.
+func (o *Operation) Summary(summary string) *Operation {
+	o.op.Summary = summary
+	return o
+}
+
+// Description sets the description of the operation.
+func (o *Operation) Description(description string) *Operation {
+	o.op.Description = description
+	return o
+}
+
+// Tags adds tags to the operation.
+func (o *Operation) Tags(tags ...string) *Operation {
+	o.op.Tags = append(o.op.Tags, tags...)
+	return o
+}
+
+// Deprecated marks the operation as deprecated.
+func (o *Operation) Deprecated() *Operation {
+	o.op.Deprecated = true
+	return o
+}
+
+// PathParam describes the path parameter name, whose schema is derived from the type of example.
+func (o *Operation) PathParam(name string, example interface{}, description string) *Operation {
+	return o.Param("path", name, example, true, description)
+}
+
+// Query declares a query parameter, whose schema is derived from the type of example.
+func (o *Operation) Query(name string, example interface{}, required bool, description string) *Operation {
+	return o.Param("query", name, example, required, description)
+}
+
+// Header declares a header parameter, whose schema is derived from the type of example.
+func (o *Operation) Header(name string, example interface{}, required bool, description string) *Operation {
+	return o.Param("header", name, example, required, description)
+}
+
+// Param declares a parameter in the given location ("path", "query", "header" or "cookie"),
+// whose schema is derived from the type of example.
+func (o *Operation) Param(in, name string, example interface{}, required bool, description string) *Operation {
+	if in == "path" && !o.hasPathParam(name) {
+		o.api.errs = append(o.api.errs, fmt.Errorf("%s %s: path parameter %q is not part of the path", strings.ToUpper(o.method), o.path, name))
+		return o
+	}
+	return o.param(&openapi.Parameter{
+		Name:        name,
+		In:          in,
+		Description: description,
+		Required:    required || in == "path",
+		Schema:      o.api.reflector.Reflect(example),
+	})
+}
+
+func (o *Operation) hasPathParam(name string) bool {
+	for _, p := range o.op.Parameters {
+		if p.In == "path" && p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// param adds p, replacing a parameter of the same name and location.
+func (o *Operation) param(p *openapi.Parameter) *Operation {
+	for i, existing := range o.op.Parameters {
+		if existing.In == p.In && existing.Name == p.Name {
+			o.op.Parameters[i] = p
+			return o
+		}
+	}
+	o.op.Parameters = append(o.op.Parameters, p)
+	return o
+}
+
+// Request declares a required JSON request body whose schema is derived from the type of example.
+func (o *Operation) Request(example interface{}, description string) *Operation {
+	o.op.RequestBody = &openapi.RequestBody{
+		Description: description,
+		Required:    true,
+		Content: map[string]*openapi.MediaType{
+			"application/json": {Schema: o.api.reflector.Reflect(example)},
+		},
+	}
+	return o
+}
+
+// Response declares the response for status. Its JSON schema is derived
+// from the type of example; a nil example declares a response without content.
+func (o *Operation) Response(status int, example interface{}, description string) *Operation {
+	if description == "" {
+		description = http.StatusText(status)
+	}
+	resp := &openapi.Response{Description: description}
+	if example != nil {
+		resp.Content = map[string]*openapi.MediaType{
+			"application/json": {Schema: o.api.reflector.Reflect(example)},
+		}
+	}
+	if o.op.Responses == nil {
+		o.op.Responses = make(map[string]*openapi.Response)
+	}
+	o.op.Responses[strconv.Itoa(status)] = resp
+	return o
+}
+
+// Security adds a security requirement for the operation, overriding the default of the API.
+func (o *Operation) Security(scheme string, scopes ...string) *Operation {
+	o.op.Security = append(o.op.Security, requirement(scheme, scopes))
+	return o
+}
+
+// Public removes the security requirements of the API for the operation.
+func (o *Operation) Public() *Operation {
+	o.op.Security = openapi.SecurityRequirements{}
+	return o
+}
+
+func requirement(scheme string, scopes []string) openapi.SecurityRequirement {
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return openapi.SecurityRequirement{scheme: scopes}
+}
+
+func validMethod(method string) bool {
+	for _, m := range openapi.Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// muxPattern validates the OpenAPI path template p and returns the ServeMux
+// pattern for it along with the names of its path parameters.
+func muxPattern(p string) (string, []string, error) {
+	if !strings.HasPrefix(p, "/") {
+		return "", nil, errors.New("path must start with a slash")
+	}
+	var params []string
+	for _, seg := range strings.Split(p, "/") {
+		if !strings.ContainsAny(seg, "{}") {
+			continue
+		}
+		m := pathParamRegex.FindStringSubmatch(seg)
+		if m == nil {
+			return "", nil, fmt.Errorf("path segment %q: parameters must span a whole segment", seg)
+		}
+		if !token.IsIdentifier(m[1]) {
+			return "", nil, fmt.Errorf("path parameter %q: name must be a valid Go identifier", m[1])
+		}
+		params = append(params, m[1])
+	}
+	pattern := p
+	if strings.HasSuffix(pattern, "/") {
+		// A trailing slash would turn the pattern into a prefix match.
+		pattern += "{$}"
+	}
+	return pattern, params, nil
+}
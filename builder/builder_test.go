@@ -0,0 +1,181 @@
+/*
+ *  builder_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package builder
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mwmahlberg/swagger-ui/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type pet struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name" doc:"Name of the pet"`
+}
+
+type BuilderSuite struct {
+	suite.Suite
+	mux *http.ServeMux
+	api *API
+}
+
+func (suite *BuilderSuite) SetupTest() {
+	suite.mux = http.NewServeMux()
+	suite.api = New(suite.mux, "Petstore", "1.0.0").
+		Description("A sample API").
+		Server("https://example.com/v1", "Production").
+		SecurityScheme("bearer", &openapi.SecurityScheme{Type: "http", Scheme: "bearer"}).
+		Security("bearer")
+
+	suite.api.HandleFunc(http.MethodGet, "/pets", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "list "+r.URL.Query().Get("limit"))
+	}).ID("listPets").Summary("List pets").Tags("pets").
+		Query("limit", int32(0), false, "Maximum number of results").
+		Response(http.StatusOK, []pet{}, "The pets").
+		Public()
+
+	suite.api.HandleFunc(http.MethodPost, "/pets", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}).ID("createPet").Request(pet{}, "The pet to create").Response(http.StatusCreated, pet{}, "")
+
+	suite.api.HandleFunc(http.MethodGet, "/pets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "show "+r.PathValue("id"))
+	}).ID("showPet").PathParam("id", int64(0), "ID of the pet").Response(http.StatusOK, pet{}, "")
+}
+
+func (suite *BuilderSuite) get(h http.Handler, method, target string) (int, string) {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+	return w.Code, w.Body.String()
+}
+
+func (suite *BuilderSuite) TestRoutes() {
+	code, body := suite.get(suite.mux, http.MethodGet, "/pets?limit=2")
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Equal(suite.T(), "list 2", body)
+
+	code, _ = suite.get(suite.mux, http.MethodPost, "/pets")
+	assert.Equal(suite.T(), http.StatusCreated, code)
+
+	code, body = suite.get(suite.mux, http.MethodGet, "/pets/7")
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Equal(suite.T(), "show 7", body)
+
+	code, _ = suite.get(suite.mux, http.MethodDelete, "/pets/7")
+	assert.Equal(suite.T(), http.StatusMethodNotAllowed, code)
+}
+
+func (suite *BuilderSuite) TestDocument() {
+	doc, err := suite.api.Document()
+	require.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), "3.1.0", doc.OpenAPI)
+	assert.Equal(suite.T(), "A sample API", doc.Info.Description)
+	require.Contains(suite.T(), doc.Paths, "/pets")
+	require.Contains(suite.T(), doc.Paths, "/pets/{id}")
+
+	list := doc.Paths["/pets"].Get
+	assert.Equal(suite.T(), "listPets", list.OperationID)
+	assert.Equal(suite.T(), openapi.SecurityRequirements{}, list.Security)
+	assert.Equal(suite.T(), "int32", list.Parameters[0].Schema.Format)
+	assert.Equal(suite.T(), "#/components/schemas/pet", list.Responses["200"].Content["application/json"].Schema.Items.Ref)
+
+	create := doc.Paths["/pets"].Post
+	assert.True(suite.T(), create.RequestBody.Required)
+	assert.Equal(suite.T(), "Created", create.Responses["201"].Description)
+
+	show := doc.Paths["/pets/{id}"].Get
+	require.Len(suite.T(), show.Parameters, 1)
+	assert.Equal(suite.T(), "path", show.Parameters[0].In)
+	assert.True(suite.T(), show.Parameters[0].Required)
+	assert.Equal(suite.T(), "integer", show.Parameters[0].Schema.Type.Name())
+
+	require.Contains(suite.T(), doc.Components.Schemas, "pet")
+	assert.Equal(suite.T(), "Name of the pet", doc.Components.Schemas["pet"].Properties["name"].Description)
+}
+
+func (suite *BuilderSuite) TestSpec() {
+	spec, err := suite.api.Spec("swagger.json")
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(spec), `"openapi": "3.1.0"`)
+
+	spec, err = suite.api.Spec("swagger.yaml")
+	require.NoError(suite.T(), err)
+	var doc map[string]interface{}
+	require.NoError(suite.T(), yaml.Unmarshal(spec, &doc))
+	assert.Equal(suite.T(), "3.1.0", doc["openapi"])
+}
+
+func (suite *BuilderSuite) TestMount() {
+	require.NoError(suite.T(), suite.api.Mount("/api-docs"))
+	code, body := suite.get(suite.mux, http.MethodGet, "/api-docs/swagger.yaml")
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Contains(suite.T(), body, "operationId: listPets")
+}
+
+func (suite *BuilderSuite) TestErrors() {
+	mux := http.NewServeMux()
+	api := New(mux, "Broken", "0.0.1")
+	h := func(w http.ResponseWriter, r *http.Request) {}
+
+	api.HandleFunc("GET", "/a", h).ID("dup")
+	api.HandleFunc("POST", "/a", h).ID("dup")
+	api.HandleFunc("GET", "/a", h)
+	api.HandleFunc("FETCH", "/b", h)
+	api.HandleFunc("GET", "/files/{name}.json", h)
+	api.HandleFunc("GET", "no-slash", h)
+	api.HandleFunc("GET", "/c", h).PathParam("id", "", "").Security("missing")
+
+	_, err := api.Document()
+	require.Error(suite.T(), err)
+	for _, expected := range []string{
+		`duplicate operationId "dup"`,
+		"GET /a: declared twice",
+		"FETCH /b: unsupported method",
+		"parameters must span a whole segment",
+		"path must start with a slash",
+		`path parameter "id" is not part of the path`,
+		`undeclared security scheme "missing"`,
+	} {
+		assert.ErrorContains(suite.T(), err, expected)
+	}
+	_, err = api.SwaggerUi()
+	assert.Error(suite.T(), err)
+}
+
+func (suite *BuilderSuite) TestMuxPattern() {
+	pattern, params, err := muxPattern("/a/{b}/c/")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "/a/{b}/c/{$}", pattern)
+	assert.Equal(suite.T(), []string{"b"}, params)
+
+	_, _, err = muxPattern("/a/{b-c}")
+	assert.ErrorContains(suite.T(), err, "valid Go identifier")
+}
+
+func TestBuilder(t *testing.T) {
+	suite.Run(t, new(BuilderSuite))
+}
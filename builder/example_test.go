@@ -0,0 +1,58 @@
+/*
+ *  example_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package builder_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/mwmahlberg/swagger-ui/builder"
+)
+
+type Pet struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+func ExampleAPI() {
+	// Error handling ommitted for brevity
+	mux := http.NewServeMux()
+	api := builder.New(mux, "Petstore", "1.0.0")
+
+	api.HandleFunc(http.MethodGet, "/pets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Pet{ID: 1, Name: "Rex"})
+	}).ID("showPet").
+		PathParam("id", int64(0), "ID of the pet").
+		Response(http.StatusOK, Pet{}, "The pet")
+
+	// Serve the UI and the generated spec below /api-docs/
+	api.Mount("/api-docs/")
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	resp, _ := http.Get(ts.URL + "/pets/1")
+	fmt.Println(resp.StatusCode)
+	resp, _ = http.Get(ts.URL + "/api-docs/swagger.yaml")
+	fmt.Println(resp.StatusCode)
+	// Output: 200
+	// 200
+}
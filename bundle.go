@@ -0,0 +1,85 @@
+/*
+ *  bundle.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+
+	"github.com/mwmahlberg/memfs"
+	"github.com/mwmahlberg/swagger-ui/openapi"
+)
+
+// BundledSpec sets the spec from a document split across several files, for
+// example an embed.FS holding "openapi.yaml", "paths/*.yaml" and "schemas/*.yaml".
+// The external references of root are inlined into a single document, which
+// is served under the base name of root.
+func BundledSpec(fsys fs.FS, root string) HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.specFS = fsys
+		suh.specRoot = root
+		suh.specTree = false
+	}
+}
+
+// SpecTree serves all files of fsys alongside swagger-ui and uses root as the
+// spec file, leaving it to swagger-ui to resolve the external references.
+// The references are still checked during setup.
+func SpecTree(fsys fs.FS, root string) HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.specFS = fsys
+		suh.specRoot = root
+		suh.specTree = true
+	}
+}
+
+func (ui *SwaggerUi) loadSpecFS() error {
+	doc, err := openapi.Bundle(ui.specFS, ui.specRoot)
+	if err != nil {
+		return err
+	}
+	if !ui.specTree {
+		ui.specFilename = path.Base(ui.specRoot)
+		ui.document = doc
+		return nil
+	}
+	ui.specFilename = path.Clean(ui.specRoot)
+	ui.specContent, err = fs.ReadFile(ui.specFS, ui.specFilename)
+	return err
+}
+
+// writeSpecTree copies the files of the spec tree into the overlay.
+func (ui *SwaggerUi) writeSpecTree(o *memfs.FS) error {
+	return fs.WalkDir(ui.specFS, ".", func(name string, d fs.DirEntry, err error) error {
+		switch {
+		case err != nil:
+			return err
+		case name == ".":
+			return nil
+		case d.IsDir():
+			return o.MkdirAll(name, 0755)
+		}
+		data, err := fs.ReadFile(ui.specFS, name)
+		if err != nil {
+			return errors.New("error reading " + name + ": " + err.Error())
+		}
+		return o.WriteFile(name, data, 0644)
+	})
+}
@@ -0,0 +1,100 @@
+/*
+ *  bundle_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+var multiFileSpec = fstest.MapFS{
+	"openapi.yaml": {Data: []byte(`openapi: 3.0.3
+info:
+  title: Pets
+  version: "1.0"
+paths: {}
+components:
+  schemas:
+    Pet:
+      $ref: schemas/pet.yaml
+`)},
+	"schemas/pet.yaml": {Data: []byte(`type: object
+properties:
+  name:
+    type: string
+`)},
+	"broken.yaml": {Data: []byte(`openapi: 3.0.3
+info:
+  title: Broken
+  version: "1.0"
+paths: {}
+components:
+  schemas:
+    Pet:
+      $ref: schemas/missing.yaml
+`)},
+}
+
+type BundleSuite struct {
+	suite.Suite
+}
+
+func (suite *BundleSuite) TestBundledSpec() {
+	ui, err := New(BundledSpec(multiFileSpec, "openapi.yaml"))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "openapi.yaml", ui.SpecFilename())
+
+	b, err := fs.ReadFile(ui.Merged, "openapi.yaml")
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(b), "name:")
+	assert.NotContains(suite.T(), string(b), "schemas/pet.yaml")
+}
+
+func (suite *BundleSuite) TestSpecTree() {
+	ui, err := New(SpecTree(multiFileSpec, "openapi.yaml"))
+	require.NoError(suite.T(), err)
+
+	b, err := fs.ReadFile(ui.Merged, "openapi.yaml")
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(b), "schemas/pet.yaml")
+
+	b, err = fs.ReadFile(ui.Merged, "schemas/pet.yaml")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), multiFileSpec["schemas/pet.yaml"].Data, b)
+
+	_, err = fs.ReadFile(ui.Merged, InitializerFilename)
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *BundleSuite) TestUnresolvedReference() {
+	_, err := New(BundledSpec(multiFileSpec, "broken.yaml"))
+	assert.ErrorAs(suite.T(), err, &SetupError{})
+
+	_, err = New(SpecTree(multiFileSpec, "broken.yaml"))
+	assert.ErrorAs(suite.T(), err, &SetupError{})
+}
+
+func TestBundle(t *testing.T) {
+	suite.Run(t, new(BundleSuite))
+}
@@ -0,0 +1,144 @@
+/*
+ *  main.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// swaggerui-diff reports the changes between two versions of an OpenAPI spec
+// and whether they break clients.
+//
+//	swaggerui-diff [-format text|json|markdown] [-fail-on breaking|any|none] base.yaml revision.yaml
+//
+// It exits with status 1 if there are breaking changes, so that it can be
+// used in CI, and with status 2 if the specs cannot be compared. Swagger 2.0
+// specs are converted to OpenAPI 3 before comparing.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/mwmahlberg/swagger-ui/diff"
+	"github.com/mwmahlberg/swagger-ui/openapi"
+)
+
+const usage = `usage: swaggerui-diff [flags] base revision
+
+Flags:
+`
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("swaggerui-diff: ")
+
+	var (
+		format = flag.String("format", "text", "output `format`, text, json or markdown")
+		failOn = flag.String("fail-on", "breaking", "`changes` that fail the run, breaking, any or none")
+	)
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	switch *format {
+	case "text", "json", "markdown":
+	default:
+		log.Printf("unknown -format value %q", *format)
+		flag.Usage()
+		os.Exit(2)
+	}
+	switch *failOn {
+	case "breaking", "any", "none":
+	default:
+		log.Printf("unknown -fail-on value %q", *failOn)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	base, err := load(flag.Arg(0))
+	if err != nil {
+		fatal(err)
+	}
+	revision, err := load(flag.Arg(1))
+	if err != nil {
+		fatal(err)
+	}
+	changes, err := diff.Compare(base, revision)
+	if err != nil {
+		fatal(err)
+	}
+
+	switch *format {
+	case "text":
+		for _, c := range changes {
+			fmt.Println(c)
+		}
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(struct {
+			Breaking bool          `json:"breaking"`
+			Changes  []diff.Change `json:"changes"`
+		}{diff.HasBreaking(changes), changes})
+	case "markdown":
+		_, err = os.Stdout.Write(diff.Markdown(changes))
+	}
+	if err != nil {
+		fatal(err)
+	}
+
+	switch *failOn {
+	case "breaking":
+		if diff.HasBreaking(changes) {
+			os.Exit(1)
+		}
+	case "any":
+		if len(changes) > 0 {
+			os.Exit(1)
+		}
+	}
+}
+
+// fatal logs err and exits with status 2, which tells errors apart from
+// changes failing the run.
+func fatal(err error) {
+	log.Println(err)
+	os.Exit(2)
+}
+
+// load reads an OpenAPI document, converting Swagger 2.0 if necessary.
+func load(name string) (*openapi.Document, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var doc *openapi.Document
+	if openapi.IsSwagger2(data) {
+		doc, _, err = openapi.ConvertSwagger2(data)
+	} else {
+		doc, err = openapi.Parse(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return doc, nil
+}
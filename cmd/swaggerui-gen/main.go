@@ -0,0 +1,167 @@
+/*
+ *  main.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// swaggerui-gen generates Go code from an OpenAPI 3 spec.
+//
+// It is meant to be used with go generate:
+//
+//	//go:generate go run github.com/mwmahlberg/swagger-ui/cmd/swaggerui-gen server -spec api.yaml -o server_gen.go
+//
+// The server command emits an interface with one method per operationId,
+// typed parameter structs and an http.Handler that routes Go 1.22 ServeMux
+// patterns to the interface and serves a SwaggerUi for the spec.
+//
+// The models command emits a Go type for every schema in components.schemas and
+// the client command a net/http based client with one method per operation.
+// The client uses the generated models, so both have to go into the same package,
+// while the server has to go into a different one:
+//
+//	//go:generate go run github.com/mwmahlberg/swagger-ui/cmd/swaggerui-gen models -spec api.yaml -o models_gen.go
+//	//go:generate go run github.com/mwmahlberg/swagger-ui/cmd/swaggerui-gen client -spec api.yaml -o client_gen.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/mwmahlberg/swagger-ui/internal/codegen"
+)
+
+const usage = `usage: swaggerui-gen <command> [flags]
+
+Commands:
+  server    generate a server interface and routing handler
+  models    generate types for the component schemas
+  client    generate a client with one method per operation
+
+Run "swaggerui-gen <command> -h" for the flags of a command.
+`
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("swaggerui-gen: ")
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "server":
+		err = runServer(os.Args[2:])
+	case "models":
+		err = runGenerator("models", codegen.GenerateModels, os.Args[2:])
+	case "client":
+		err = runGenerator("client", codegen.GenerateClient, os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// commonFlags are shared by all commands.
+type commonFlags struct {
+	spec   string
+	pkg    string
+	output string
+}
+
+func (c *commonFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.spec, "spec", "", "path to the spec `file` (required)")
+	// go generate sets GOPACKAGE to the package of the file containing the directive.
+	fs.StringVar(&c.pkg, "package", os.Getenv("GOPACKAGE"), "`name` of the generated package")
+	fs.StringVar(&c.output, "o", "", "output `file`, defaults to stdout")
+}
+
+func (c *commonFlags) load() (*codegen.Spec, []byte, error) {
+	if c.spec == "" {
+		return nil, nil, fmt.Errorf("-spec is required")
+	}
+	if c.pkg == "" {
+		return nil, nil, fmt.Errorf("-package is required outside of go generate")
+	}
+	data, err := os.ReadFile(c.spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	spec, err := codegen.Load(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", c.spec, err)
+	}
+	return spec, data, nil
+}
+
+func (c *commonFlags) write(src []byte) error {
+	if c.output == "" {
+		_, err := os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(c.output, src, 0644)
+}
+
+func runServer(args []string) error {
+	var c commonFlags
+	fs := flag.NewFlagSet("server", flag.ExitOnError)
+	c.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	spec, data, err := c.load()
+	if err != nil {
+		return err
+	}
+	src, err := codegen.GenerateServer(spec, codegen.ServerConfig{
+		Package:      c.pkg,
+		SpecFilename: c.spec,
+		Spec:         data,
+	})
+	if err != nil {
+		return err
+	}
+	return c.write(src)
+}
+
+func runGenerator(name string, generate func(*codegen.Spec, codegen.Config) ([]byte, error), args []string) error {
+	var c commonFlags
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	c.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	spec, _, err := c.load()
+	if err != nil {
+		return err
+	}
+	src, err := generate(spec, codegen.Config{
+		Package:      c.pkg,
+		SpecFilename: c.spec,
+	})
+	if err != nil {
+		return err
+	}
+	return c.write(src)
+}
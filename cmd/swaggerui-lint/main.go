@@ -0,0 +1,128 @@
+/*
+ *  main.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// swaggerui-lint checks OpenAPI specs against the rules of the lint package.
+//
+//	swaggerui-lint [-config lint.yaml] [-format text|json] [-fail-on error] spec.yaml...
+//
+// It prints the findings of each spec and exits with status 1 if any of them
+// is at least as severe as -fail-on, so that it can be used in CI. The
+// configuration sets the severities of built-in rules and adds custom rules,
+// see the lint package. Run "swaggerui-lint -rules" for the built-in rules.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/mwmahlberg/swagger-ui/lint"
+)
+
+const usage = `usage: swaggerui-lint [flags] spec...
+
+Flags:
+`
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("swaggerui-lint: ")
+
+	var (
+		config = flag.String("config", "", "YAML rule configuration `file`")
+		format = flag.String("format", "text", "output `format`, text or json")
+		failOn = flag.String("fail-on", "error", "lowest `severity` that fails the run, or off")
+		rules  = flag.Bool("rules", false, "list the built-in rules and exit")
+	)
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *rules {
+		for _, r := range lint.Builtins() {
+			fmt.Printf("%-24s %-6s %s\n", r.Name, r.Severity, r.Description)
+		}
+		return
+	}
+	if flag.NArg() == 0 || *format != "text" && *format != "json" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	threshold, err := lint.ParseSeverity(*failOn)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	l, err := newLinter(*config)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	failed := false
+	results := make(map[string][]lint.Finding)
+	for _, name := range flag.Args() {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		findings, err := l.Lint(data)
+		if err != nil {
+			log.Fatalf("%s: %s", name, err)
+		}
+		results[name] = findings
+		for _, f := range findings {
+			if f.Severity >= threshold && threshold != lint.Off {
+				failed = true
+			}
+		}
+		if *format == "text" {
+			for _, f := range findings {
+				fmt.Printf("%s:%s\n", name, f)
+			}
+		}
+	}
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			log.Fatalln(err)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func newLinter(config string) (*lint.Linter, error) {
+	if config == "" {
+		return lint.New(nil)
+	}
+	data, err := os.ReadFile(config)
+	if err != nil {
+		return nil, err
+	}
+	c, err := lint.ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", config, err)
+	}
+	return lint.New(c)
+}
@@ -0,0 +1,117 @@
+/*
+ *  config.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFilename is the name under which the swagger-config document is served.
+const ConfigFilename = "swagger-config.json"
+
+// UIConfig holds settings of swagger-ui, keyed like the options of
+// SwaggerUIBundle, as in {"docExpansion": "none", "filter": true}.
+type UIConfig map[string]interface{}
+
+// SwaggerConfig serves c as swagger-config document under ConfigFilename,
+// which the generated initializer loads through configUrl. Settings of the
+// document take precedence over those of the initializer.
+func SwaggerConfig(c UIConfig) HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.uiConfig = &uiConfig{config: c}
+	}
+}
+
+// SwaggerConfigFile serves the YAML or JSON file at path like SwaggerConfig.
+// The file is read again whenever it changes, so that operators can change
+// settings without restarting. If it cannot be read or parsed after a change,
+// the last good version is served.
+func SwaggerConfigFile(path string) HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.uiConfig = &uiConfig{path: path}
+	}
+}
+
+// uiConfig is the source of the swagger-config document.
+type uiConfig struct {
+	config UIConfig
+	path   string
+
+	mu      sync.Mutex
+	data    []byte
+	modTime time.Time
+	size    int64
+}
+
+// load renders the document, reading the file again if it changed since the last call.
+func (c *uiConfig) load() ([]byte, time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.path == "" {
+		if c.data == nil {
+			data, err := json.Marshal(c.config)
+			if err != nil {
+				return nil, time.Time{}, err
+			}
+			c.data, c.modTime = data, time.Now()
+		}
+		return c.data, c.modTime, nil
+	}
+
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return c.data, c.modTime, err
+	}
+	if c.data != nil && info.ModTime().Equal(c.modTime) && info.Size() == c.size {
+		return c.data, c.modTime, nil
+	}
+	raw, err := os.ReadFile(c.path)
+	if err != nil {
+		return c.data, c.modTime, err
+	}
+	var config UIConfig
+	if err := yaml.Unmarshal(raw, &config); err != nil {
+		return c.data, c.modTime, err
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return c.data, c.modTime, err
+	}
+	c.data, c.modTime, c.size = data, info.ModTime(), info.Size()
+	return c.data, c.modTime, nil
+}
+
+// serveConfig serves the swagger-config document, or the last good version if it cannot be loaded.
+func (ui *SwaggerUi) serveConfig(w http.ResponseWriter, r *http.Request) {
+	data, modTime, _ := ui.uiConfig.load()
+	if data == nil {
+		http.Error(w, "swagger-config not available", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeContent(w, r, ConfigFilename, modTime, bytes.NewReader(data))
+}
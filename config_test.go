@@ -0,0 +1,93 @@
+/*
+ *  config_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ConfigSuite struct {
+	suite.Suite
+}
+
+func (suite *ConfigSuite) TestConfig() {
+	ui, err := New(Spec("pets.yaml", versionedSpec("1.0", "")),
+		SwaggerConfig(UIConfig{"docExpansion": "none", "filter": true}))
+	require.NoError(suite.T(), err)
+
+	code, body := get(ui, "/"+ConfigFilename)
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.JSONEq(suite.T(), `{"docExpansion": "none", "filter": true}`, body)
+	_, body = get(ui, "/"+InitializerFilename)
+	assert.Contains(suite.T(), body, `"configUrl": "./swagger-config.json",`)
+
+	ui, err = New(Spec("pets.yaml", versionedSpec("1.0", "")))
+	require.NoError(suite.T(), err)
+	code, _ = get(ui, "/"+ConfigFilename)
+	assert.Equal(suite.T(), http.StatusNotFound, code)
+}
+
+func (suite *ConfigSuite) TestFile() {
+	path := filepath.Join(suite.T().TempDir(), "swagger-config.yaml")
+	require.NoError(suite.T(), os.WriteFile(path, []byte("docExpansion: none\n"), 0o644))
+	ui, err := New(Spec("pets.yaml", versionedSpec("1.0", "")), SwaggerConfigFile(path))
+	require.NoError(suite.T(), err)
+	_, body := get(ui, "/"+ConfigFilename)
+	assert.JSONEq(suite.T(), `{"docExpansion": "none"}`, body)
+
+	require.NoError(suite.T(), os.WriteFile(path, []byte("docExpansion: list\nsupportedSubmitMethods: [get]\n"), 0o644))
+	require.NoError(suite.T(), os.Chtimes(path, time.Now(), time.Now().Add(time.Second)))
+	_, body = get(ui, "/"+ConfigFilename)
+	assert.JSONEq(suite.T(), `{"docExpansion": "list", "supportedSubmitMethods": ["get"]}`, body, "changes are picked up")
+
+	require.NoError(suite.T(), os.WriteFile(path, []byte("docExpansion: [\n"), 0o644))
+	require.NoError(suite.T(), os.Chtimes(path, time.Now(), time.Now().Add(2*time.Second)))
+	_, body = get(ui, "/"+ConfigFilename)
+	assert.JSONEq(suite.T(), `{"docExpansion": "list", "supportedSubmitMethods": ["get"]}`, body, "the last good version is kept")
+
+	_, err = New(Spec("pets.yaml", versionedSpec("1.0", "")), SwaggerConfigFile(path))
+	assert.Error(suite.T(), err)
+}
+
+func (suite *ConfigSuite) TestInitializerContent() {
+	custom := []byte(`window.onload = function () {
+  window.ui = SwaggerUIBundle({url: "./pets.yaml", dom_id: "#swagger-ui", presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset], layout: "StandaloneLayout"});
+  // Custom initializers are served as they are, without configUrl.
+};
+`)
+	ui, err := New(Spec("pets.yaml", versionedSpec("1.0", "")), InitializerContent(custom),
+		SwaggerConfig(UIConfig{"docExpansion": "none"}))
+	require.NoError(suite.T(), err)
+	_, body := get(ui, "/"+InitializerFilename)
+	assert.Equal(suite.T(), string(custom), body)
+	code, _ := get(ui, "/"+ConfigFilename)
+	assert.Equal(suite.T(), http.StatusOK, code)
+}
+
+func TestConfig(t *testing.T) {
+	suite.Run(t, new(ConfigSuite))
+}
@@ -0,0 +1,60 @@
+/*
+ *  deref.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"github.com/mwmahlberg/swagger-ui/openapi"
+)
+
+// DereferencedSpec additionally serves the spec with all references inlined
+// under name, for tools that cannot follow $ref. It is rendered as JSON if
+// name ends in ".json" and as YAML otherwise. If name is empty, ".deref" is
+// inserted before the extension of the spec file name, as in "swagger.deref.yaml".
+// The variant is generated once during setup.
+func DereferencedSpec(name string) HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.derefFilename = name
+		suh.deref = true
+	}
+}
+
+// DereferencedFilename returns the name of the dereferenced spec, or an empty
+// string if it is not served.
+func (ui *SwaggerUi) DereferencedFilename() string {
+	return ui.derefFilename
+}
+
+func (ui *SwaggerUi) setupDeref() error {
+	if ui.derefFilename == "" {
+		ui.derefFilename = ui.variantFilename(".deref")
+	}
+	doc, err := openapi.Parse(ui.specContent)
+	if err != nil {
+		return err
+	}
+	if doc, err = openapi.Dereference(doc); err != nil {
+		return err
+	}
+	data, err := encodeDocument(doc, ui.derefFilename)
+	if err != nil {
+		return err
+	}
+	ui.serveFile(ui.derefFilename, data)
+	return nil
+}
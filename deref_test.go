@@ -0,0 +1,94 @@
+/*
+ *  deref_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const refSpec = `openapi: 3.0.3
+info:
+  title: Pets
+  version: "1.0"
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: A pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+`
+
+type DerefSuite struct {
+	suite.Suite
+}
+
+func (suite *DerefSuite) TestNamedVariant() {
+	ui, err := New(Spec("swagger.yaml", []byte(refSpec)), DereferencedSpec("swagger.deref.json"))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "swagger.deref.json", ui.DereferencedFilename())
+
+	b, err := fs.ReadFile(ui.Merged, "swagger.deref.json")
+	require.NoError(suite.T(), err)
+	assert.NotContains(suite.T(), string(b), "$ref")
+	assert.Contains(suite.T(), string(b), `"name": {`)
+
+	b, err = fs.ReadFile(ui.Merged, "swagger.yaml")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), refSpec, string(b))
+}
+
+func (suite *DerefSuite) TestDefaultName() {
+	ui, err := New(Spec("pets.yaml", []byte(refSpec)), DereferencedSpec(""))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "pets.deref.yaml", ui.DereferencedFilename())
+
+	_, err = fs.ReadFile(ui.Merged, "pets.deref.yaml")
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *DerefSuite) TestNotServedByDefault() {
+	ui, err := New(Spec("pets.yaml", []byte(refSpec)))
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), ui.DereferencedFilename())
+}
+
+func (suite *DerefSuite) TestInvalidSpec() {
+	_, err := New(Spec("pets.yaml", []byte("foo: bar")), DereferencedSpec(""))
+	assert.ErrorAs(suite.T(), err, &SetupError{})
+}
+
+func TestDeref(t *testing.T) {
+	suite.Run(t, new(DerefSuite))
+}
@@ -0,0 +1,315 @@
+/*
+ *  diff.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// Package diff compares two versions of an OpenAPI document semantically and
+// classifies each change as breaking or non-breaking for clients.
+//
+// Operations are matched by method and path, disregarding the names of path
+// parameters. Schemas are compared in the direction data flows: a schema of a
+// request breaks clients if it accepts less than before, for example by
+// requiring a new property or narrowing an enum, while a schema of a response
+// breaks clients if it may return more or other data, for example by removing
+// a property or adding an enum value. Webhooks and callbacks are not compared.
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mwmahlberg/swagger-ui/openapi"
+)
+
+// Change is a difference between two documents.
+type Change struct {
+	// ID identifies the kind of change, as in "parameter-became-required".
+	ID       string `json:"id"`
+	Breaking bool   `json:"breaking"`
+	// Operation is the affected operation, as in "GET /pets/{id}", if any.
+	Operation string `json:"operation,omitempty"`
+	// Location is the affected part of the operation, as in
+	// "response 200 application/json", and the property within its schema.
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message"`
+}
+
+// String formats c as in "breaking: GET /pets: parameter query limit: became required".
+func (c Change) String() string {
+	kind := "non-breaking"
+	if c.Breaking {
+		kind = "breaking"
+	}
+	parts := []string{kind}
+	if c.Operation != "" {
+		parts = append(parts, c.Operation)
+	}
+	if c.Location != "" {
+		parts = append(parts, c.Location)
+	}
+	return strings.Join(append(parts, c.Message), ": ")
+}
+
+// HasBreaking reports whether any of changes is breaking.
+func HasBreaking(changes []Change) bool {
+	return slices.ContainsFunc(changes, func(c Change) bool { return c.Breaking })
+}
+
+// Compare returns the changes from base to revision, ordered by path and method.
+func Compare(base, revision *openapi.Document) ([]Change, error) {
+	if base == nil || revision == nil {
+		return nil, fmt.Errorf("diff: nothing to compare")
+	}
+	c := &comparer{base: base, revision: revision}
+	baseOps, revOps := operations(base), operations(revision)
+
+	var keys []string
+	for k := range baseOps {
+		keys = append(keys, k)
+	}
+	for k := range revOps {
+		if _, ok := baseOps[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := baseOps[keys[i]], baseOps[keys[j]]
+		if a == nil {
+			a = revOps[keys[i]]
+		}
+		if b == nil {
+			b = revOps[keys[j]]
+		}
+		if a.path != b.path {
+			return a.path < b.path
+		}
+		return slices.Index(openapi.Methods, a.method) < slices.Index(openapi.Methods, b.method)
+	})
+
+	for _, k := range keys {
+		b, r := baseOps[k], revOps[k]
+		switch {
+		case r == nil:
+			c.add(b.String(), "", "operation-removed", true, "operation was removed")
+		case b == nil:
+			c.add(r.String(), "", "operation-added", false, "operation was added")
+		default:
+			c.operation(b, r)
+		}
+	}
+	return c.changes, nil
+}
+
+// comparer collects the changes between two documents.
+type comparer struct {
+	base, revision *openapi.Document
+	changes        []Change
+	op             string
+}
+
+func (c *comparer) add(op, location, id string, breaking bool, format string, args ...interface{}) {
+	c.changes = append(c.changes, Change{
+		ID: id, Breaking: breaking, Operation: op, Location: location, Message: fmt.Sprintf(format, args...)})
+}
+
+// operation is an operation along with the parameters of its path item.
+type operation struct {
+	method, path string
+	op           *openapi.Operation
+	item         *openapi.PathItem
+}
+
+func (o *operation) String() string {
+	return strings.ToUpper(o.method) + " " + o.path
+}
+
+var pathParam = regexp.MustCompile(`\{[^}]*\}`)
+
+// operations returns the operations of d keyed by method and normalized path.
+func operations(d *openapi.Document) map[string]*operation {
+	ops := make(map[string]*operation)
+	for path, item := range d.Paths {
+		resolved, err := d.ResolvePathItem(item)
+		if err != nil || resolved == nil {
+			continue
+		}
+		for _, method := range openapi.Methods {
+			if op := resolved.Operation(method); op != nil {
+				key := method + " " + pathParam.ReplaceAllString(path, "{}")
+				ops[key] = &operation{method: method, path: path, op: op, item: resolved}
+			}
+		}
+	}
+	return ops
+}
+
+func (c *comparer) operation(b, r *operation) {
+	c.op = r.String()
+	if !b.op.Deprecated && r.op.Deprecated {
+		c.add(c.op, "", "operation-deprecated", false, "operation was deprecated")
+	}
+	if b.op.OperationID != r.op.OperationID && b.op.OperationID != "" {
+		c.add(c.op, "", "operation-id-changed", false, "operationId changed from %q to %q", b.op.OperationID, r.op.OperationID)
+	}
+	c.parameters(c.params(c.base, b), c.params(c.revision, r))
+	c.requestBody(b.op.RequestBody, r.op.RequestBody)
+	c.responses(b.op.Responses, r.op.Responses)
+}
+
+// params returns the resolved parameters of o keyed by location and name,
+// with those of the operation overriding those of the path item. Path
+// parameters are keyed by their position in the path instead of their name.
+func (c *comparer) params(d *openapi.Document, o *operation) map[string]*openapi.Parameter {
+	var positions []string
+	for _, m := range pathParam.FindAllString(o.path, -1) {
+		positions = append(positions, strings.Trim(m, "{}"))
+	}
+	params := make(map[string]*openapi.Parameter)
+	for _, list := range [][]*openapi.Parameter{o.item.Parameters, o.op.Parameters} {
+		for _, p := range list {
+			resolved, err := d.ResolveParameter(p)
+			if err != nil || resolved == nil {
+				continue
+			}
+			name := resolved.Name
+			switch resolved.In {
+			case "header":
+				// Header names are case-insensitive.
+				name = strings.ToLower(name)
+			case "path":
+				name = fmt.Sprintf("#%d", slices.Index(positions, name))
+			}
+			params[resolved.In+" "+name] = resolved
+		}
+	}
+	return params
+}
+
+func (c *comparer) parameters(base, rev map[string]*openapi.Parameter) {
+	for _, key := range sortedKeys(base, rev) {
+		b, r := base[key], rev[key]
+		switch {
+		case r == nil:
+			c.add(c.op, paramLocation(b), "parameter-removed", true, "parameter was removed")
+		case b == nil && r.Required:
+			c.add(c.op, paramLocation(r), "required-parameter-added", true, "required parameter was added")
+		case b == nil:
+			c.add(c.op, paramLocation(r), "parameter-added", false, "optional parameter was added")
+		default:
+			loc := paramLocation(r)
+			if !b.Required && r.Required {
+				c.add(c.op, loc, "parameter-became-required", true, "parameter became required")
+			}
+			if b.Required && !r.Required {
+				c.add(c.op, loc, "parameter-became-optional", false, "parameter became optional")
+			}
+			c.schema(b.Schema, r.Schema, request, loc, "")
+			c.content(b.Content, r.Content, request, loc)
+		}
+	}
+}
+
+func paramLocation(p *openapi.Parameter) string {
+	return "parameter " + p.In + " " + p.Name
+}
+
+func (c *comparer) requestBody(b, r *openapi.RequestBody) {
+	b, _ = c.base.ResolveRequestBody(b)
+	r, _ = c.revision.ResolveRequestBody(r)
+	switch {
+	case b == nil && r == nil:
+	case r == nil:
+		c.add(c.op, "request body", "request-body-removed", true, "request body was removed")
+	case b == nil && r.Required:
+		c.add(c.op, "request body", "required-request-body-added", true, "required request body was added")
+	case b == nil:
+		c.add(c.op, "request body", "request-body-added", false, "optional request body was added")
+	default:
+		if !b.Required && r.Required {
+			c.add(c.op, "request body", "request-body-became-required", true, "request body became required")
+		}
+		c.content(b.Content, r.Content, request, "request body")
+	}
+}
+
+func (c *comparer) responses(base, rev map[string]*openapi.Response) {
+	for _, status := range sortedKeys(base, rev) {
+		loc := "response " + status
+		b, _ := c.base.ResolveResponse(base[status])
+		r, _ := c.revision.ResolveResponse(rev[status])
+		switch {
+		case b == nil && r == nil:
+		case r == nil:
+			c.add(c.op, loc, "response-removed", true, "response was removed")
+		case b == nil:
+			c.add(c.op, loc, "response-added", false, "response was added")
+		default:
+			c.content(b.Content, r.Content, response, loc)
+			c.headers(b.Headers, r.Headers, loc)
+		}
+	}
+}
+
+func (c *comparer) headers(base, rev map[string]*openapi.Header, loc string) {
+	for _, name := range sortedKeys(base, rev) {
+		b, _ := c.base.ResolveHeader(base[name])
+		r, _ := c.revision.ResolveHeader(rev[name])
+		hloc := loc + " header " + name
+		switch {
+		case b == nil && r == nil:
+		case r == nil:
+			c.add(c.op, hloc, "response-header-removed", true, "response header was removed")
+		case b == nil:
+			c.add(c.op, hloc, "response-header-added", false, "response header was added")
+		default:
+			c.schema(b.Schema, r.Schema, response, hloc, "")
+		}
+	}
+}
+
+// content compares the media types of a parameter, request body or response.
+func (c *comparer) content(base, rev map[string]*openapi.MediaType, dir direction, loc string) {
+	for _, mt := range sortedKeys(base, rev) {
+		b, r := base[mt], rev[mt]
+		mloc := loc + " " + mt
+		switch {
+		case r == nil:
+			c.add(c.op, mloc, "media-type-removed", true, "media type was removed")
+		case b == nil:
+			c.add(c.op, mloc, "media-type-added", false, "media type was added")
+		default:
+			c.schema(b.Schema, r.Schema, dir, mloc, "")
+		}
+	}
+}
+
+// sortedKeys returns the keys of both maps in order.
+func sortedKeys[T any](a, b map[string]T) []string {
+	var keys []string
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
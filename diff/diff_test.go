@@ -0,0 +1,281 @@
+/*
+ *  diff_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package diff
+
+import (
+	"testing"
+
+	"github.com/mwmahlberg/swagger-ui/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const baseSpec = `openapi: 3.0.3
+info:
+  title: Pets
+  version: "1.0"
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            maximum: 100
+        - name: X-Trace
+          in: header
+          schema:
+            type: string
+      responses:
+        "200":
+          description: The pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+    post:
+      operationId: addPet
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        "201":
+          description: Added
+  /pets/{id}:
+    get:
+      operationId: showPet
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: The pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+        "404":
+          description: Not found
+    delete:
+      operationId: deletePet
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        "204":
+          description: Deleted
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        kind:
+          type: string
+          enum: [cat, dog, bird]
+        tags:
+          type: array
+          items:
+            type: string
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Pet'
+`
+
+const revisedSpec = `openapi: 3.0.3
+info:
+  title: Pets
+  version: "2.0"
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - name: limit
+          in: query
+          required: true
+          schema:
+            type: integer
+            maximum: 50
+        - name: x-trace
+          in: header
+          schema:
+            type: string
+        - name: owner
+          in: query
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: The pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+    post:
+      operationId: addPet
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        "201":
+          description: Added
+  /pets/{petId}:
+    get:
+      operationId: showPet
+      deprecated: true
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: The pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+            application/xml:
+              schema:
+                $ref: '#/components/schemas/Pet'
+  /owners:
+    get:
+      operationId: listOwners
+      responses:
+        "200":
+          description: The owners
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name, age]
+      properties:
+        name:
+          type: string
+        kind:
+          type: string
+          enum: [cat, dog, fish]
+        age:
+          type: integer
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Pet'
+`
+
+type DiffSuite struct {
+	suite.Suite
+	changes []Change
+}
+
+func (suite *DiffSuite) SetupSuite() {
+	base, err := openapi.Parse([]byte(baseSpec))
+	suite.Require().NoError(err)
+	rev, err := openapi.Parse([]byte(revisedSpec))
+	suite.Require().NoError(err)
+	suite.changes, err = Compare(base, rev)
+	suite.Require().NoError(err)
+}
+
+func (suite *DiffSuite) strings() []string {
+	var s []string
+	for _, c := range suite.changes {
+		s = append(s, c.String())
+	}
+	return s
+}
+
+func (suite *DiffSuite) TestOperations() {
+	s := suite.strings()
+	assert.Contains(suite.T(), s, "non-breaking: GET /owners: operation was added")
+	assert.Contains(suite.T(), s, "breaking: DELETE /pets/{id}: operation was removed")
+	assert.Contains(suite.T(), s, "non-breaking: GET /pets/{petId}: operation was deprecated")
+	for _, c := range suite.changes {
+		assert.NotEqual(suite.T(), "GET /pets/{id}", c.Operation, "renamed path parameters are the same operation")
+	}
+}
+
+func (suite *DiffSuite) TestParameters() {
+	s := suite.strings()
+	assert.Contains(suite.T(), s, "breaking: GET /pets: parameter query limit: parameter became required")
+	assert.Contains(suite.T(), s, "breaking: GET /pets: parameter query limit: maximum changed from 100 to 50")
+	assert.Contains(suite.T(), s, "breaking: GET /pets: parameter query owner: required parameter was added")
+	for _, c := range suite.changes {
+		assert.NotContains(suite.T(), c.Location, "X-Trace", "header names are case-insensitive")
+	}
+}
+
+func (suite *DiffSuite) TestRequestSchemas() {
+	s := suite.strings()
+	assert.Contains(suite.T(), s, "breaking: POST /pets: request body application/json age: required property was added")
+	assert.Contains(suite.T(), s, `breaking: POST /pets: request body application/json kind: enum values "bird" were removed`)
+	assert.Contains(suite.T(), s, `non-breaking: POST /pets: request body application/json kind: enum values "fish" were added`)
+	assert.Contains(suite.T(), s, "non-breaking: POST /pets: request body application/json tags: property is no longer accepted")
+}
+
+func (suite *DiffSuite) TestResponses() {
+	s := suite.strings()
+	assert.Contains(suite.T(), s, "breaking: GET /pets: response 200 application/json [].tags: property was removed")
+	assert.Contains(suite.T(), s, `breaking: GET /pets: response 200 application/json [].kind: enum values "fish" were added`)
+	assert.Contains(suite.T(), s, "non-breaking: GET /pets/{petId}: response 200 application/json age: property was added")
+	assert.Contains(suite.T(), s, "breaking: GET /pets/{petId}: response 404: response was removed")
+	assert.Contains(suite.T(), s, "non-breaking: GET /pets/{petId}: response 200 application/xml: media type was added")
+}
+
+func (suite *DiffSuite) TestOrder() {
+	require.NotEmpty(suite.T(), suite.changes)
+	assert.Equal(suite.T(), "GET /owners", suite.changes[0].Operation)
+	assert.True(suite.T(), HasBreaking(suite.changes))
+}
+
+func (suite *DiffSuite) TestIdentical() {
+	base, err := openapi.Parse([]byte(baseSpec))
+	require.NoError(suite.T(), err)
+	changes, err := Compare(base, base)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), changes)
+	assert.False(suite.T(), HasBreaking(changes))
+}
+
+func TestDiff(t *testing.T) {
+	suite.Run(t, new(DiffSuite))
+}
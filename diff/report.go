@@ -0,0 +1,64 @@
+/*
+ *  report.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package diff
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Markdown renders changes as Markdown, with the breaking changes first.
+func Markdown(changes []Change) []byte {
+	var buf bytes.Buffer
+	if len(changes) == 0 {
+		buf.WriteString("No changes.\n")
+		return buf.Bytes()
+	}
+	for _, section := range []struct {
+		title    string
+		breaking bool
+	}{
+		{"Breaking changes", true},
+		{"Non-breaking changes", false},
+	} {
+		first := true
+		for _, c := range changes {
+			if c.Breaking != section.breaking {
+				continue
+			}
+			if first {
+				if buf.Len() > 0 {
+					buf.WriteByte('\n')
+				}
+				fmt.Fprintf(&buf, "## %s\n\n", section.title)
+				first = false
+			}
+			buf.WriteString("- ")
+			if c.Operation != "" {
+				fmt.Fprintf(&buf, "`%s` ", c.Operation)
+			}
+			if c.Location != "" {
+				fmt.Fprintf(&buf, "%s: ", c.Location)
+			}
+			buf.WriteString(c.Message)
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Bytes()
+}
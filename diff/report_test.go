@@ -0,0 +1,49 @@
+/*
+ *  report_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReportSuite struct {
+	suite.Suite
+}
+
+func (suite *ReportSuite) TestMarkdown() {
+	changes := []Change{
+		{ID: "operation-added", Operation: "GET /owners", Message: "operation was added"},
+		{ID: "parameter-became-required", Breaking: true, Operation: "GET /pets", Location: "parameter query limit", Message: "parameter became required"},
+	}
+	assert.Equal(suite.T(), "## Breaking changes\n\n"+
+		"- `GET /pets` parameter query limit: parameter became required\n"+
+		"\n## Non-breaking changes\n\n"+
+		"- `GET /owners` operation was added\n", string(Markdown(changes)))
+}
+
+func (suite *ReportSuite) TestNoChanges() {
+	assert.Equal(suite.T(), "No changes.\n", string(Markdown(nil)))
+}
+
+func TestReport(t *testing.T) {
+	suite.Run(t, new(ReportSuite))
+}
@@ -0,0 +1,260 @@
+/*
+ *  schema.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mwmahlberg/swagger-ui/openapi"
+)
+
+// direction tells whether a schema describes data sent by clients or by the server.
+type direction int
+
+const (
+	request direction = iota
+	response
+)
+
+// maxDepth limits the recursion into nested and recursive schemas.
+const maxDepth = 32
+
+// schemaPair is a pair of schemas already being compared, guarding against recursion.
+type schemaPair struct{ base, revision *openapi.Schema }
+
+// schema compares two schemas at the location loc, where prop is the path of
+// the property within the schema, as in "owner.tags[]".
+func (c *comparer) schema(base, rev *openapi.Schema, dir direction, loc, prop string) {
+	c.compareSchema(base, rev, dir, loc, prop, nil)
+}
+
+func (c *comparer) compareSchema(base, rev *openapi.Schema, dir direction, loc, prop string, seen []schemaPair) {
+	base, _ = c.base.ResolveSchema(base)
+	rev, _ = c.revision.ResolveSchema(rev)
+	if base == nil || rev == nil || len(seen) > maxDepth || slices.Contains(seen, schemaPair{base, rev}) {
+		return
+	}
+	seen = append(seen, schemaPair{base, rev})
+	at := func(prop string) string {
+		if prop == "" {
+			return loc
+		}
+		return loc + " " + prop
+	}
+	add := func(id string, breaking bool, format string, args ...interface{}) {
+		c.add(c.op, at(prop), id, breaking, format, args...)
+	}
+
+	if bt, rt := base.Type, rev.Type; len(bt) > 0 && !reflect.DeepEqual(typeSet(bt), typeSet(rt)) {
+		var breaking bool
+		switch dir {
+		case request:
+			breaking = !covers(rt, bt)
+		case response:
+			breaking = !covers(bt, rt)
+		}
+		add("type-changed", breaking, "type changed from %s to %s", typeName(bt), typeName(rt))
+	}
+	if base.Format != rev.Format && base.Format != "" {
+		add("format-changed", true, "format changed from %q to %q", base.Format, rev.Format)
+	}
+	if base.Nullable != rev.Nullable {
+		if rev.Nullable {
+			add("became-nullable", dir == response, "became nullable")
+		} else {
+			add("became-non-nullable", dir == request, "is no longer nullable")
+		}
+	}
+	c.enum(base, rev, dir, add)
+	c.bounds(base, rev, dir, add)
+
+	for _, name := range sortedKeys(base.Properties, rev.Properties) {
+		b, r := base.Properties[name], rev.Properties[name]
+		p := join(prop, name)
+		switch {
+		case r == nil && dir == response:
+			c.add(c.op, at(p), "property-removed", true, "property was removed")
+		case r == nil:
+			c.add(c.op, at(p), "property-removed", false, "property is no longer accepted")
+		case b == nil && dir == request && slices.Contains(rev.Required, name):
+			c.add(c.op, at(p), "required-property-added", true, "required property was added")
+		case b == nil:
+			c.add(c.op, at(p), "property-added", false, "property was added")
+		default:
+			if slices.Contains(base.Required, name) != slices.Contains(rev.Required, name) {
+				if slices.Contains(rev.Required, name) {
+					c.add(c.op, at(p), "property-became-required", dir == request, "property became required")
+				} else {
+					c.add(c.op, at(p), "property-became-optional", dir == response, "property became optional")
+				}
+			}
+			c.compareSchema(b, r, dir, loc, p, seen)
+		}
+	}
+	if base.Items != nil && rev.Items != nil {
+		c.compareSchema(base.Items, rev.Items, dir, loc, prop+"[]", seen)
+	}
+	for _, comp := range []struct {
+		name      string
+		base, rev []*openapi.Schema
+	}{
+		{"allOf", base.AllOf, rev.AllOf},
+		{"oneOf", base.OneOf, rev.OneOf},
+		{"anyOf", base.AnyOf, rev.AnyOf},
+	} {
+		if len(comp.base) != len(comp.rev) {
+			add(comp.name+"-changed", true, "%s changed from %d to %d schemas", comp.name, len(comp.base), len(comp.rev))
+			continue
+		}
+		for i := range comp.base {
+			c.compareSchema(comp.base[i], comp.rev[i], dir, loc, prop, seen)
+		}
+	}
+}
+
+// enum compares the enum values of two schemas.
+func (c *comparer) enum(base, rev *openapi.Schema, dir direction, add func(string, bool, string, ...interface{})) {
+	if len(base.Enum) == 0 && len(rev.Enum) > 0 {
+		add("enum-added", dir == request, "values are restricted to %s", values(rev.Enum))
+		return
+	}
+	if len(rev.Enum) == 0 {
+		if len(base.Enum) > 0 {
+			add("enum-removed", dir == response, "values are no longer restricted")
+		}
+		return
+	}
+	if removed := missing(base.Enum, rev.Enum); len(removed) > 0 {
+		add("enum-value-removed", dir == request, "enum values %s were removed", values(removed))
+	}
+	if added := missing(rev.Enum, base.Enum); len(added) > 0 {
+		add("enum-value-added", dir == response, "enum values %s were added", values(added))
+	}
+}
+
+// bounds compares the numeric, length and size bounds of two schemas.
+// Narrower bounds break requests, while wider ones break responses.
+func (c *comparer) bounds(base, rev *openapi.Schema, dir direction, add func(string, bool, string, ...interface{})) {
+	check := func(keyword string, b, r *float64, isMin bool) {
+		if b == nil && r == nil || b != nil && r != nil && *b == *r {
+			return
+		}
+		narrowed := b == nil || r != nil && (isMin && *r > *b || !isMin && *r < *b)
+		if narrowed {
+			add(keyword+"-narrowed", dir == request, "%s changed from %s to %s", keyword, bound(b), bound(r))
+		} else {
+			add(keyword+"-widened", dir == response, "%s changed from %s to %s", keyword, bound(b), bound(r))
+		}
+	}
+	check("minimum", base.Minimum, rev.Minimum, true)
+	check("maximum", base.Maximum, rev.Maximum, false)
+	check("minLength", intBound(base.MinLength), intBound(rev.MinLength), true)
+	check("maxLength", intBound(base.MaxLength), intBound(rev.MaxLength), false)
+	check("minItems", intBound(base.MinItems), intBound(rev.MinItems), true)
+	check("maxItems", intBound(base.MaxItems), intBound(rev.MaxItems), false)
+	if base.Pattern != rev.Pattern {
+		add("pattern-changed", dir == request || base.Pattern == "", "pattern changed from %q to %q", base.Pattern, rev.Pattern)
+	}
+}
+
+func intBound(i *int) *float64 {
+	if i == nil {
+		return nil
+	}
+	f := float64(*i)
+	return &f
+}
+
+func bound(f *float64) string {
+	if f == nil {
+		return "none"
+	}
+	return fmt.Sprint(*f)
+}
+
+// typeSet returns the types of t as a set.
+func typeSet(t openapi.Types) map[string]bool {
+	set := make(map[string]bool)
+	for _, name := range t {
+		set[name] = true
+	}
+	return set
+}
+
+// covers reports whether the types of a accept all values of the types of b.
+func covers(a, b openapi.Types) bool {
+	if len(a) == 0 {
+		return true
+	}
+	if len(b) == 0 {
+		return false
+	}
+	for _, t := range b {
+		if !a.Is(t) && !(t == "integer" && a.Is("number")) {
+			return false
+		}
+	}
+	return true
+}
+
+func typeName(t openapi.Types) string {
+	if len(t) == 0 {
+		return "any"
+	}
+	return strings.Join(t, " or ")
+}
+
+// missing returns the values of a that are not in b.
+func missing(a, b []interface{}) []interface{} {
+	var out []interface{}
+	for _, v := range a {
+		found := false
+		for _, w := range b {
+			if fmt.Sprint(v) == fmt.Sprint(w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func values(vs []interface{}) string {
+	var s []string
+	for _, v := range vs {
+		s = append(s, fmt.Sprintf("%q", fmt.Sprint(v)))
+	}
+	sort.Strings(s)
+	return strings.Join(s, ", ")
+}
+
+// join appends the property name to the property path prop.
+func join(prop, name string) string {
+	if prop == "" {
+		return name
+	}
+	return prop + "." + name
+}
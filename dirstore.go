@@ -0,0 +1,202 @@
+/*
+ *  dirstore.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// DefaultStoreInterval is the default interval in which a DirStore is checked for new specs.
+const DefaultStoreInterval = 2 * time.Second
+
+// DirStore is a SpecStore keeping each revision in a file of a directory named
+// after the revision, as in "000042". The names do not depend on the format of
+// the spec, so that a revision can be taken only once. Files are written
+// atomically, so that several processes can share the directory, for example
+// on a network volume.
+type DirStore struct {
+	// Interval is the time between two checks for new specs in Watch. It defaults to DefaultStoreInterval.
+	Interval time.Duration
+
+	dir string
+}
+
+// NewDirStore returns a DirStore keeping its specs in dir, which is created if necessary.
+func NewDirStore(dir string) (*DirStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DirStore{dir: dir}, nil
+}
+
+// Get implements SpecStore.
+func (s *DirStore) Get(ctx context.Context, revision int) (StoredSpec, error) {
+	files, err := s.files()
+	if err != nil {
+		return StoredSpec{}, err
+	}
+	if revision == 0 && len(files) > 0 {
+		revision = files[len(files)-1].revision
+	}
+	i, ok := slices.BinarySearchFunc(files, revision, func(f storeFile, r int) int { return f.revision - r })
+	if !ok {
+		return StoredSpec{}, ErrSpecNotFound
+	}
+	return s.read(files[i])
+}
+
+// Put implements SpecStore. The spec is written to a temporary file first,
+// which is then linked to the name of the next revision. Linking fails if
+// another process took that revision in the meantime, in which case the
+// following one is tried.
+func (s *DirStore) Put(ctx context.Context, spec []byte) (StoredSpec, error) {
+	tmp, err := os.CreateTemp(s.dir, ".spec-*")
+	if err != nil {
+		return StoredSpec{}, err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(spec)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return StoredSpec{}, err
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return StoredSpec{}, err
+		}
+		files, err := s.files()
+		if err != nil {
+			return StoredSpec{}, err
+		}
+		revision := 1
+		if len(files) > 0 {
+			revision = files[len(files)-1].revision + 1
+		}
+		f := storeFile{name: fmt.Sprintf("%06d", revision), revision: revision}
+		err = os.Link(tmp.Name(), filepath.Join(s.dir, f.name))
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return StoredSpec{}, err
+		}
+		return s.read(f)
+	}
+}
+
+// Revisions implements SpecStore.
+func (s *DirStore) Revisions(context.Context) ([]int, error) {
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]int, len(files))
+	for i, f := range files {
+		revisions[i] = f.revision
+	}
+	return revisions, nil
+}
+
+// Watch implements SpecStore. The directory is checked for new specs in the configured interval.
+func (s *DirStore) Watch(ctx context.Context) (<-chan StoredSpec, error) {
+	revisions, err := s.Revisions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	last := 0
+	if len(revisions) > 0 {
+		last = revisions[len(revisions)-1]
+	}
+	interval := s.Interval
+	if interval <= 0 {
+		interval = DefaultStoreInterval
+	}
+
+	w := make(chan StoredSpec, 1)
+	go func() {
+		defer close(w)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			latest, err := s.Get(ctx, 0)
+			if err != nil || latest.Revision <= last {
+				continue
+			}
+			last = latest.Revision
+			notify(w, latest)
+		}
+	}()
+	return w, nil
+}
+
+// storeFile is a file of a DirStore.
+type storeFile struct {
+	name     string
+	revision int
+}
+
+// files returns the files of the store ordered by revision.
+func (s *DirStore) files() ([]storeFile, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []storeFile
+	for _, e := range entries {
+		revision, err := strconv.Atoi(e.Name())
+		if err != nil || revision < 1 || !e.Type().IsRegular() {
+			continue
+		}
+		files = append(files, storeFile{name: e.Name(), revision: revision})
+	}
+	slices.SortFunc(files, func(a, b storeFile) int { return a.revision - b.revision })
+	return files, nil
+}
+
+func (s *DirStore) read(f storeFile) (StoredSpec, error) {
+	path := filepath.Join(s.dir, f.name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return StoredSpec{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return StoredSpec{}, err
+	}
+	return StoredSpec{Revision: f.revision, Spec: data, Time: info.ModTime()}, nil
+}
@@ -0,0 +1,123 @@
+/*
+ *  dirstore_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type DirStoreSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (suite *DirStoreSuite) SetupTest() {
+	suite.dir = suite.T().TempDir()
+}
+
+func (suite *DirStoreSuite) TestPutGet() {
+	s, err := NewDirStore(filepath.Join(suite.dir, "specs"))
+	require.NoError(suite.T(), err)
+	ctx := context.Background()
+	_, err = s.Get(ctx, 0)
+	assert.ErrorIs(suite.T(), err, ErrSpecNotFound)
+
+	_, err = s.Put(ctx, versionedSpec("1.0", ""))
+	require.NoError(suite.T(), err)
+	stored, err := s.Put(ctx, []byte(`{"openapi": "3.0.3"}`))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, stored.Revision)
+	assert.FileExists(suite.T(), filepath.Join(suite.dir, "specs", "000001"))
+	assert.FileExists(suite.T(), filepath.Join(suite.dir, "specs", "000002"))
+
+	// A new store on the same directory sees the specs, as after a restart.
+	s, err = NewDirStore(filepath.Join(suite.dir, "specs"))
+	require.NoError(suite.T(), err)
+	revisions, err := s.Revisions(ctx)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []int{1, 2}, revisions)
+	first, err := s.Get(ctx, 1)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), versionedSpec("1.0", ""), first.Spec)
+	latest, err := s.Get(ctx, 0)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, latest.Revision)
+
+	entries, err := os.ReadDir(filepath.Join(suite.dir, "specs"))
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), entries, 2, "no temporary files are left")
+}
+
+func (suite *DirStoreSuite) TestConcurrentPut() {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Separate stores act like separate processes.
+			s, err := NewDirStore(suite.dir)
+			if assert.NoError(suite.T(), err) {
+				spec := versionedSpec("1.0", "")
+				if i%2 == 1 {
+					spec = []byte(`{"openapi": "3.0.3"}`)
+				}
+				_, err = s.Put(context.Background(), spec)
+				assert.NoError(suite.T(), err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	s, _ := NewDirStore(suite.dir)
+	revisions, err := s.Revisions(context.Background())
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []int{1, 2, 3, 4, 5, 6, 7, 8}, revisions, "YAML and JSON specs do not share a revision")
+}
+
+func (suite *DirStoreSuite) TestWatch() {
+	s, err := NewDirStore(suite.dir)
+	require.NoError(suite.T(), err)
+	s.Interval = time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	specs, err := s.Watch(ctx)
+	require.NoError(suite.T(), err)
+
+	other, _ := NewDirStore(suite.dir)
+	_, err = other.Put(ctx, versionedSpec("1.0", ""))
+	require.NoError(suite.T(), err)
+	select {
+	case stored := <-specs:
+		assert.Equal(suite.T(), 1, stored.Revision)
+	case <-time.After(time.Second):
+		suite.T().Fatal("no spec received")
+	}
+}
+
+func TestDirStore(t *testing.T) {
+	suite.Run(t, new(DirStoreSuite))
+}
@@ -0,0 +1,53 @@
+/*
+ *  document.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"strings"
+
+	"github.com/mwmahlberg/swagger-ui/openapi"
+)
+
+// Document sets the spec from a typed OpenAPI document.
+// It is rendered as JSON if the spec file name ends in ".json" and as YAML otherwise.
+// Combine it with Spec to choose the file name; the content passed to Spec is replaced.
+func Document(doc *openapi.Document) HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.document = doc
+	}
+}
+
+// Document parses the served spec into a typed OpenAPI document.
+// Each call returns a fresh copy, so changes do not affect the handler.
+func (ui *SwaggerUi) Document() (*openapi.Document, error) {
+	return openapi.Parse(ui.state().specContent)
+}
+
+func (ui *SwaggerUi) renderDocument() (err error) {
+	ui.specContent, err = encodeDocument(ui.document, ui.specFilename)
+	return err
+}
+
+// encodeDocument renders doc as JSON if name ends in ".json" and as YAML otherwise.
+func encodeDocument(doc *openapi.Document, name string) ([]byte, error) {
+	if strings.HasSuffix(strings.ToLower(name), ".json") {
+		return doc.JSON()
+	}
+	return doc.YAML()
+}
@@ -0,0 +1,84 @@
+/*
+ *  document_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/mwmahlberg/swagger-ui/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const petSpec = `# Pets API
+openapi: 3.0.3
+info:
+  title: Pets
+  version: "1.0"
+paths: {}
+`
+
+type DocumentSuite struct {
+	suite.Suite
+}
+
+func (suite *DocumentSuite) TestDocumentOptionYAML() {
+	doc, err := openapi.Parse([]byte(petSpec))
+	require.NoError(suite.T(), err)
+	doc.Info.Title = "Animals"
+
+	ui, err := New(Document(doc))
+	require.NoError(suite.T(), err)
+
+	b, err := fs.ReadFile(ui.Merged, DefaultSpecfileName)
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(b), "# Pets API")
+	assert.Contains(suite.T(), string(b), "title: Animals")
+}
+
+func (suite *DocumentSuite) TestDocumentOptionJSON() {
+	doc := &openapi.Document{OpenAPI: "3.1.0", Info: &openapi.Info{Title: "Pets", Version: "1.0"}}
+
+	ui, err := New(Spec("openapi.json", nil), Document(doc))
+	require.NoError(suite.T(), err)
+
+	b, err := fs.ReadFile(ui.Merged, "openapi.json")
+	require.NoError(suite.T(), err)
+	assert.JSONEq(suite.T(), `{"openapi":"3.1.0","info":{"title":"Pets","version":"1.0"},"paths":{}}`, string(b))
+}
+
+func (suite *DocumentSuite) TestDocumentAccessor() {
+	ui, err := New(Spec("pets.yaml", []byte(petSpec)))
+	require.NoError(suite.T(), err)
+
+	doc, err := ui.Document()
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Pets", doc.Info.Title)
+
+	doc.Info.Title = "Changed"
+	again, err := ui.Document()
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Pets", again.Info.Title)
+}
+
+func TestDocument(t *testing.T) {
+	suite.Run(t, new(DocumentSuite))
+}
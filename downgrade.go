@@ -0,0 +1,67 @@
+/*
+ *  downgrade.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"strings"
+
+	"github.com/mwmahlberg/swagger-ui/openapi"
+)
+
+// DowngradedSpec additionally serves a best-effort OpenAPI 3.0.3 view of an
+// OpenAPI 3.1 spec under name, for tools that only read 3.0. It is rendered as
+// JSON if name ends in ".json" and as YAML otherwise. If name is empty, ".3.0"
+// is inserted before the extension of the spec file name, as in "swagger.3.0.yaml".
+// A spec that already is OpenAPI 3.0 is served unchanged. Lossy conversions
+// are reported by Warnings.
+func DowngradedSpec(name string) HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.downgradeFilename = name
+		suh.downgrade = true
+	}
+}
+
+// DowngradedFilename returns the name of the OpenAPI 3.0 variant of the spec,
+// or an empty string if it is not served.
+func (ui *SwaggerUi) DowngradedFilename() string {
+	return ui.downgradeFilename
+}
+
+func (ui *SwaggerUi) setupDowngrade() error {
+	if ui.downgradeFilename == "" {
+		ui.downgradeFilename = ui.variantFilename(".3.0")
+	}
+	doc, err := openapi.Parse(ui.specContent)
+	if err != nil {
+		return err
+	}
+	if strings.HasPrefix(doc.OpenAPI, "3.1") {
+		var warnings []string
+		if doc, warnings, err = openapi.Downgrade31(doc); err != nil {
+			return err
+		}
+		ui.warnings = append(ui.warnings, warnings...)
+	}
+	data, err := encodeDocument(doc, ui.downgradeFilename)
+	if err != nil {
+		return err
+	}
+	ui.serveFile(ui.downgradeFilename, data)
+	return nil
+}
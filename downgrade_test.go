@@ -0,0 +1,81 @@
+/*
+ *  downgrade_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.com/mwmahlberg/swagger-ui/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type DowngradeSuite struct {
+	suite.Suite
+	spec []byte
+}
+
+func (suite *DowngradeSuite) SetupSuite() {
+	var err error
+	suite.spec, err = os.ReadFile("openapi/testdata/openapi31.yaml")
+	require.NoError(suite.T(), err)
+}
+
+func (suite *DowngradeSuite) TestDowngradedVariant() {
+	ui, err := New(Spec("openapi.yaml", suite.spec), DowngradedSpec("openapi.3.0.json"))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "openapi.3.0.json", ui.DowngradedFilename())
+	assert.NotEmpty(suite.T(), ui.Warnings())
+
+	b, err := fs.ReadFile(ui.Merged, "openapi.3.0.json")
+	require.NoError(suite.T(), err)
+	doc, err := openapi.Parse(b)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "3.0.3", doc.OpenAPI)
+
+	b, err = fs.ReadFile(ui.Merged, "openapi.yaml")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), suite.spec, b)
+}
+
+func (suite *DowngradeSuite) TestDefaultName() {
+	ui, err := New(Spec("openapi.yaml", suite.spec), DowngradedSpec(""))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "openapi.3.0.yaml", ui.DowngradedFilename())
+
+	_, err = fs.ReadFile(ui.Merged, "openapi.3.0.yaml")
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *DowngradeSuite) TestOpenAPI30Unchanged() {
+	ui, err := New(Spec("pets.yaml", []byte(refSpec)), DowngradedSpec(""))
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), ui.Warnings())
+
+	b, err := fs.ReadFile(ui.Merged, "pets.3.0.yaml")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), refSpec, string(b))
+}
+
+func TestDowngrade(t *testing.T) {
+	suite.Run(t, new(DowngradeSuite))
+}
@@ -0,0 +1,218 @@
+/*
+ *  env.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// envKind determines how the value of an environment variable is rendered.
+type envKind int
+
+const (
+	envString envKind = iota
+	envBool
+	envNumber
+	envArray   // a JSON array or a comma-separated list
+	envObject  // a JSON object
+	envFilter  // a boolean or a string
+	envNullURL // a string, or null if it is "none"
+)
+
+// envVar maps an environment variable of the swagger-ui Docker image to a configuration key.
+type envVar struct {
+	name string
+	key  string
+	kind envKind
+}
+
+// envConfig lists the variables configuring SwaggerUIBundle, as in docker/configurator/variables.js.
+var envConfig = []envVar{
+	{"CONFIG_URL", "configUrl", envString},
+	{"DOM_ID", "dom_id", envString},
+	{"SPEC", "spec", envObject},
+	{"URL", "url", envString},
+	{"URLS", "urls", envArray},
+	{"URLS_PRIMARY_NAME", "urls.primaryName", envString},
+	{"QUERY_CONFIG_ENABLED", "queryConfigEnabled", envBool},
+	{"LAYOUT", "layout", envString},
+	{"DEEP_LINKING", "deepLinking", envBool},
+	{"DISPLAY_OPERATION_ID", "displayOperationId", envBool},
+	{"DEFAULT_MODELS_EXPAND_DEPTH", "defaultModelsExpandDepth", envNumber},
+	{"DEFAULT_MODEL_EXPAND_DEPTH", "defaultModelExpandDepth", envNumber},
+	{"DEFAULT_MODEL_RENDERING", "defaultModelRendering", envString},
+	{"DISPLAY_REQUEST_DURATION", "displayRequestDuration", envBool},
+	{"DOC_EXPANSION", "docExpansion", envString},
+	{"FILTER", "filter", envFilter},
+	{"MAX_DISPLAYED_TAGS", "maxDisplayedTags", envNumber},
+	{"SHOW_EXTENSIONS", "showExtensions", envBool},
+	{"SHOW_COMMON_EXTENSIONS", "showCommonExtensions", envBool},
+	{"USE_UNSAFE_MARKDOWN", "useUnsafeMarkdown", envBool},
+	{"OAUTH2_REDIRECT_URL", "oauth2RedirectUrl", envString},
+	{"PERSIST_AUTHORIZATION", "persistAuthorization", envBool},
+	{"SHOW_MUTATED_REQUEST", "showMutatedRequest", envBool},
+	{"SUPPORTED_SUBMIT_METHODS", "supportedSubmitMethods", envArray},
+	{"TRY_IT_OUT_ENABLED", "tryItOutEnabled", envBool},
+	{"VALIDATOR_URL", "validatorUrl", envNullURL},
+	{"WITH_CREDENTIALS", "withCredentials", envBool},
+}
+
+// envOAuth lists the variables passed to initOAuth, as in docker/configurator/oauth.js.
+var envOAuth = []envVar{
+	{"OAUTH_CLIENT_ID", "clientId", envString},
+	{"OAUTH_CLIENT_SECRET", "clientSecret", envString},
+	{"OAUTH_REALM", "realm", envString},
+	{"OAUTH_APP_NAME", "appName", envString},
+	{"OAUTH_SCOPE_SEPARATOR", "scopeSeparator", envString},
+	{"OAUTH_SCOPES", "scopes", envString},
+	{"OAUTH_ADDITIONAL_PARAMS", "additionalQueryStringParams", envObject},
+	{"OAUTH_USE_BASIC_AUTH", "useBasicAuthenticationWithAccessCodeGrant", envBool},
+	{"OAUTH_USE_PKCE", "usePkceWithAuthorizationCodeGrant", envBool},
+}
+
+// setting is a key of a configuration object rendered into the initializer,
+// with key and value encoded as JSON.
+type setting struct {
+	Key   template.HTML
+	Value template.HTML
+	key   string
+}
+
+// FromEnv configures swagger-ui with the environment variables supported by
+// the swagger-ui Docker image, such as URL, URLS, DEEP_LINKING, DOC_EXPANSION,
+// FILTER or OAUTH_CLIENT_ID. They are rendered into the generated initializer,
+// not into one passed to InitializerContent. URL, URLS and SPEC replace the
+// specs listed by the handler.
+//
+// SWAGGER_JSON names a local file served as the spec, while SWAGGER_JSON_URL
+// is used as URL unless that is set. BASE_URL is the path the handler is
+// mounted at, as in "/docs", which prefixes the URLs of the specs. Variables
+// concerning the web server of the image, such as PORT, are ignored.
+//
+// Malformed values, such as a DEEP_LINKING other than a boolean, make New fail.
+func FromEnv() HandlerOption {
+	return func(suh *SwaggerUi) {
+		var errs []error
+		if path, ok := os.LookupEnv("SWAGGER_JSON"); ok {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("SWAGGER_JSON: %w", err))
+			}
+			suh.specFilename = filepath.Base(path)
+			suh.specContent = data
+		}
+		if base, ok := os.LookupEnv("BASE_URL"); ok {
+			suh.basePath = strings.TrimSuffix(base, "/")
+		}
+
+		config, err := envSettings(envConfig)
+		errs = append(errs, err)
+		if u, ok := os.LookupEnv("SWAGGER_JSON_URL"); ok && !hasSetting(config, "url") {
+			config = append(config, newSetting("url", u))
+		}
+		oauth, err := envSettings(envOAuth)
+		errs = append(errs, err)
+
+		suh.settings = append(suh.settings, config...)
+		suh.oauthSettings = append(suh.oauthSettings, oauth...)
+		if err := errors.Join(errs...); err != nil {
+			suh.optionErrs = append(suh.optionErrs, err)
+		}
+	}
+}
+
+// envSettings returns the settings of the variables that are set.
+func envSettings(vars []envVar) ([]setting, error) {
+	var (
+		settings []setting
+		errs     []error
+	)
+	for _, v := range vars {
+		raw, ok := os.LookupEnv(v.name)
+		if !ok {
+			continue
+		}
+		value, err := envValue(v.kind, raw)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", v.name, err))
+			continue
+		}
+		settings = append(settings, newSetting(v.key, value))
+	}
+	return settings, errors.Join(errs...)
+}
+
+func envValue(kind envKind, raw string) (interface{}, error) {
+	switch kind {
+	case envBool:
+		return strconv.ParseBool(raw)
+	case envNumber:
+		return strconv.Atoi(raw)
+	case envArray:
+		var values []interface{}
+		if json.Unmarshal([]byte(raw), &values) == nil {
+			return values, nil
+		}
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			items = append(items, strings.TrimSpace(item))
+		}
+		return items, nil
+	case envObject:
+		var value map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	case envFilter:
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b, nil
+		}
+		return raw, nil
+	case envNullURL:
+		if raw == "none" || raw == "null" {
+			return nil, nil
+		}
+	}
+	return raw, nil
+}
+
+func newSetting(key string, value interface{}) setting {
+	k, _ := json.Marshal(key)
+	v, _ := json.Marshal(value)
+	return setting{Key: template.HTML(k), Value: template.HTML(v), key: key}
+}
+
+func hasSetting(settings []setting, keys ...string) bool {
+	for _, s := range settings {
+		for _, key := range keys {
+			if s.key == key {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+/*
+ *  env_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type EnvSuite struct {
+	suite.Suite
+}
+
+func (suite *EnvSuite) TestConfig() {
+	suite.T().Setenv("DEEP_LINKING", "false")
+	suite.T().Setenv("DOC_EXPANSION", "none")
+	suite.T().Setenv("FILTER", "pets")
+	suite.T().Setenv("SUPPORTED_SUBMIT_METHODS", "get, post")
+	suite.T().Setenv("VALIDATOR_URL", "none")
+	suite.T().Setenv("MAX_DISPLAYED_TAGS", "5")
+	suite.T().Setenv("OAUTH_CLIENT_ID", "docs")
+	suite.T().Setenv("OAUTH_ADDITIONAL_PARAMS", `{"audience": "pets"}`)
+	suite.T().Setenv("BASE_URL", "/docs/")
+
+	ui, err := New(Spec("pets.yaml", versionedSpec("1.0", "")), FromEnv())
+	require.NoError(suite.T(), err)
+	_, body := get(ui, "/"+InitializerFilename)
+	assert.Contains(suite.T(), body, `url: "/docs/pets.yaml",`)
+	assert.Contains(suite.T(), body, `"deepLinking": false,`)
+	assert.NotContains(suite.T(), body, "deepLinking: true", "settings replace the defaults")
+	assert.Contains(suite.T(), body, `"docExpansion": "none",`)
+	assert.Contains(suite.T(), body, `"filter": "pets",`)
+	assert.Contains(suite.T(), body, `"supportedSubmitMethods": ["get","post"],`)
+	assert.Contains(suite.T(), body, `"validatorUrl": null,`)
+	assert.Contains(suite.T(), body, `"maxDisplayedTags": 5,`)
+	assert.Contains(suite.T(), body, `window.ui.initOAuth({
+    "clientId": "docs",
+    "additionalQueryStringParams": {"audience":"pets"}
+  });`)
+}
+
+func (suite *EnvSuite) TestURLs() {
+	suite.T().Setenv("URLS", `[{"url": "https://example.com/pets.yaml", "name": "Pets"}]`)
+	suite.T().Setenv("URLS_PRIMARY_NAME", "Pets")
+	suite.T().Setenv("FILTER", "true")
+
+	ui, err := New(Spec("pets.yaml", versionedSpec("1.0", "")), FromEnv())
+	require.NoError(suite.T(), err)
+	_, body := get(ui, "/"+InitializerFilename)
+	assert.Contains(suite.T(), body, `"urls": [{"name":"Pets","url":"https://example.com/pets.yaml"}],`)
+	assert.Contains(suite.T(), body, `"urls.primaryName": "Pets",`)
+	assert.Contains(suite.T(), body, `"filter": true,`)
+	assert.NotContains(suite.T(), body, `url: "./pets.yaml"`, "URLS replaces the spec of the handler")
+}
+
+func (suite *EnvSuite) TestSwaggerJSON() {
+	path := filepath.Join(suite.T().TempDir(), "openapi.yaml")
+	require.NoError(suite.T(), os.WriteFile(path, versionedSpec("3.0", ""), 0o644))
+	suite.T().Setenv("SWAGGER_JSON", path)
+	suite.T().Setenv("SWAGGER_JSON_URL", "https://example.com/pets.yaml")
+	suite.T().Setenv("URL", "https://example.com/other.yaml")
+
+	ui, err := New(FromEnv())
+	require.NoError(suite.T(), err)
+	code, body := get(ui, "/openapi.yaml")
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Contains(suite.T(), body, `version: "3.0"`)
+	_, body = get(ui, "/"+InitializerFilename)
+	assert.Contains(suite.T(), body, `"url": "https://example.com/other.yaml",`, "URL takes precedence")
+	assert.NotContains(suite.T(), body, "https://example.com/pets.yaml")
+}
+
+func (suite *EnvSuite) TestInvalid() {
+	suite.T().Setenv("DEEP_LINKING", "yes please")
+	suite.T().Setenv("SPEC", "{")
+	_, err := New(Spec("pets.yaml", versionedSpec("1.0", "")), FromEnv())
+	require.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "DEEP_LINKING")
+	assert.Contains(suite.T(), err.Error(), "SPEC")
+}
+
+func TestEnv(t *testing.T) {
+	suite.Run(t, new(EnvSuite))
+}
@@ -1,6 +1,6 @@
 module github.com/mwmahlberg/swagger-ui
 
-go 1.20
+go 1.22
 
 require (
 	github.com/asaskevich/govalidator v0.0.0-20230301143203-a9d515a09cc2
@@ -0,0 +1,323 @@
+/*
+ *  history.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mwmahlberg/swagger-ui/diff"
+	"github.com/mwmahlberg/swagger-ui/openapi"
+)
+
+const (
+	// ChangelogFilename is the name of the changelog page served with SpecHistory.
+	ChangelogFilename = "changelog.html"
+	// versionDir is the directory the versions of a spec are served from.
+	versionDir = "versions/"
+)
+
+// SpecVersion is a version of a spec kept by a History.
+type SpecVersion struct {
+	// Version is the info.version of the spec.
+	Version string
+	Spec    []byte
+	// Added is the time the version was added to the history.
+	Added time.Time
+}
+
+// History keeps the versions of a spec, keyed by their info.version, in the
+// order they were added.
+type History struct {
+	mu        sync.RWMutex
+	versions  []SpecVersion
+	listeners []func()
+}
+
+// NewHistory returns a History holding the given specs, oldest first.
+func NewHistory(specs ...[]byte) (*History, error) {
+	h := &History{}
+	for _, spec := range specs {
+		if _, err := h.Add(spec); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+// Add adds spec as the latest version and returns its info.version. A spec
+// with a version already in the history replaces that version in place, since
+// a version is expected to be published only once.
+func (h *History) Add(spec []byte) (string, error) {
+	version, err := specVersion(spec)
+	if err != nil {
+		return "", err
+	}
+
+	h.mu.Lock()
+	entry := SpecVersion{Version: version, Spec: spec, Added: time.Now()}
+	changed := true
+	if i := slices.IndexFunc(h.versions, func(v SpecVersion) bool { return v.Version == version }); i >= 0 {
+		changed = !bytes.Equal(h.versions[i].Spec, spec)
+		entry.Added = h.versions[i].Added
+		h.versions[i] = entry
+	} else {
+		h.versions = append(h.versions, entry)
+	}
+	listeners := append([]func(){}, h.listeners...)
+	h.mu.Unlock()
+
+	if changed {
+		for _, l := range listeners {
+			l()
+		}
+	}
+	return version, nil
+}
+
+// specVersion returns the info.version of spec.
+func specVersion(spec []byte) (string, error) {
+	doc, err := parseSpec(spec)
+	if err != nil {
+		return "", err
+	}
+	if doc.Info == nil || doc.Info.Version == "" {
+		return "", errors.New("spec has no info.version")
+	}
+	return doc.Info.Version, nil
+}
+
+// Versions returns all versions, oldest first.
+func (h *History) Versions() []SpecVersion {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return append([]SpecVersion(nil), h.versions...)
+}
+
+// Get returns the spec of version.
+func (h *History) Get(version string) (SpecVersion, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, v := range h.versions {
+		if v.Version == version {
+			return v, true
+		}
+	}
+	return SpecVersion{}, false
+}
+
+// Release is an entry of the changelog of a History.
+type Release struct {
+	SpecVersion
+	// Previous is the version the changes are relative to, or empty for the first version.
+	Previous string
+	Changes  []diff.Change
+}
+
+// Changelog compares each version with the one before and returns the
+// releases, newest first.
+func (h *History) Changelog() ([]Release, error) {
+	versions := h.Versions()
+	releases := make([]Release, len(versions))
+	var prev *openapi.Document
+	for i, v := range versions {
+		doc, err := parseSpec(v.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("version %s: %w", v.Version, err)
+		}
+		r := Release{SpecVersion: v}
+		if prev != nil {
+			r.Previous = versions[i-1].Version
+			if r.Changes, err = diff.Compare(prev, doc); err != nil {
+				return nil, fmt.Errorf("version %s: %w", v.Version, err)
+			}
+		}
+		releases[len(versions)-1-i] = r
+		prev = doc
+	}
+	return releases, nil
+}
+
+// subscribe registers f to be called whenever a version was added or changed.
+func (h *History) subscribe(f func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.listeners = append(h.listeners, f)
+}
+
+// parseSpec parses an OpenAPI document, converting Swagger 2.0 if necessary.
+func parseSpec(data []byte) (*openapi.Document, error) {
+	if openapi.IsSwagger2(data) {
+		doc, _, err := openapi.ConvertSwagger2(data)
+		return doc, err
+	}
+	return openapi.Parse(data)
+}
+
+// SpecHistory serves every version of h at a stable URL, as returned by
+// VersionFilename, and a changelog page listing the changes between
+// consecutive versions under ChangelogFilename. The spec of the handler is
+// added to h as the latest version. Versions added to h later are served,
+// too, and the changelog is updated.
+func SpecHistory(h *History) HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.history = h
+	}
+}
+
+// VersionFilename returns the name under which version is served, as in
+// "versions/1.2.0/swagger.yaml", or an empty string if there is no such version.
+func (ui *SwaggerUi) VersionFilename(version string) string {
+	if ui.history == nil {
+		return ""
+	}
+	v, ok := ui.history.Get(version)
+	if !ok {
+		return ""
+	}
+	return ui.versionFilename(v)
+}
+
+func (ui *SwaggerUi) versionFilename(v SpecVersion) string {
+	base := strings.TrimSuffix(path.Base(ui.specFilename), path.Ext(ui.specFilename))
+	return versionDir + strings.ReplaceAll(v.Version, "/", "_") + "/" + base + specExt(v.Spec)
+}
+
+// checkHistory checks that the spec can be added to the history once it is served.
+func (ui *SwaggerUi) checkHistory() error {
+	if len(ui.specContent) == 0 {
+		return nil
+	}
+	_, err := specVersion(ui.specContent)
+	return err
+}
+
+// addToHistory adds the spec to the history. It is called once the spec is
+// served, so that the history lists published versions only.
+func (ui *SwaggerUi) addToHistory() error {
+	if ui.history == nil || len(ui.specContent) == 0 {
+		return nil
+	}
+	_, err := ui.history.Add(ui.specContent)
+	return err
+}
+
+// syncHistory publishes the versions of the history and the changelog.
+func (ui *SwaggerUi) syncHistory() {
+	for _, v := range ui.history.Versions() {
+		ui.live.set(ui.versionFilename(v), v.Spec)
+	}
+	page, err := ui.renderChangelog()
+	if err != nil {
+		page = []byte(template.HTMLEscapeString("error rendering changelog: " + err.Error()))
+	}
+	ui.live.set(ChangelogFilename, page)
+}
+
+// changelogRelease is a release as rendered by changelogTemplate.
+type changelogRelease struct {
+	Release
+	URL                string
+	Breaking, Additive []diff.Change
+}
+
+func (ui *SwaggerUi) renderChangelog() ([]byte, error) {
+	releases, err := ui.history.Changelog()
+	if err != nil {
+		return nil, err
+	}
+	data := struct {
+		Title    string
+		Releases []changelogRelease
+	}{Title: "API"}
+	for _, r := range releases {
+		cr := changelogRelease{Release: r, URL: ui.versionFilename(r.SpecVersion)}
+		for _, c := range r.Changes {
+			if c.Breaking {
+				cr.Breaking = append(cr.Breaking, c)
+			} else {
+				cr.Additive = append(cr.Additive, c)
+			}
+		}
+		data.Releases = append(data.Releases, cr)
+	}
+	if len(releases) > 0 {
+		if doc, err := parseSpec(releases[0].Spec); err == nil && doc.Info != nil && doc.Info.Title != "" {
+			data.Title = doc.Info.Title
+		}
+	}
+	var buf bytes.Buffer
+	if err := changelogTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var changelogTemplate = template.Must(template.New(ChangelogFilename).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <title>{{.Title}} Changelog</title>
+  <style>
+    body { font-family: sans-serif; max-width: 60em; margin: 2em auto; padding: 0 1em; color: #3b4151; }
+    h2 { border-bottom: 1px solid #d9d9d9; padding-bottom: .2em; }
+    .breaking { color: #b3261e; }
+    code { background: #f0f0f0; padding: 0 .2em; }
+  </style>
+</head>
+<body>
+  <h1>{{.Title}} Changelog</h1>
+  {{- range .Releases}}
+  <section id="{{.Version}}">
+    <h2>{{.Version}}</h2>
+    <p>Published {{.Added.Format "2006-01-02"}} &middot; <a href="{{.URL}}">Spec</a></p>
+    {{- if not .Previous}}
+    <p>Initial version.</p>
+    {{- else if not .Changes}}
+    <p>No changes to the operations since {{.Previous}}.</p>
+    {{- else}}
+    {{- with .Breaking}}
+    <h3 class="breaking">Breaking changes</h3>
+    <ul>
+      {{- range .}}
+      <li>{{with .Operation}}<code>{{.}}</code> {{end}}{{with .Location}}{{.}}: {{end}}{{.Message}}</li>
+      {{- end}}
+    </ul>
+    {{- end}}
+    {{- with .Additive}}
+    <h3>Other changes</h3>
+    <ul>
+      {{- range .}}
+      <li>{{with .Operation}}<code>{{.}}</code> {{end}}{{with .Location}}{{.}}: {{end}}{{.Message}}</li>
+      {{- end}}
+    </ul>
+    {{- end}}
+    {{- end}}
+  </section>
+  {{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,141 @@
+/*
+ *  history_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// versionedSpec returns refSpec with the given version and an additional path.
+func versionedSpec(version, path string) []byte {
+	spec := strings.Replace(refSpec, `version: "1.0"`, `version: "`+version+`"`, 1)
+	if path != "" {
+		spec = strings.Replace(spec, "paths:\n", "paths:\n  "+path+":\n    get:\n      responses:\n        \"204\":\n          description: Done\n", 1)
+	}
+	return []byte(spec)
+}
+
+type HistorySuite struct {
+	suite.Suite
+}
+
+func (suite *HistorySuite) TestAdd() {
+	h, err := NewHistory(versionedSpec("1.0", ""), versionedSpec("1.1", "/owners"))
+	require.NoError(suite.T(), err)
+
+	version, err := h.Add(versionedSpec("1.0", "/toys"))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "1.0", version)
+
+	versions := h.Versions()
+	require.Len(suite.T(), versions, 2, "existing versions are replaced")
+	assert.Equal(suite.T(), "1.0", versions[0].Version)
+	assert.Contains(suite.T(), string(versions[0].Spec), "/toys")
+
+	_, err = h.Add([]byte(`openapi: 3.0.3
+info: {title: Pets}
+paths: {}
+`))
+	assert.Error(suite.T(), err, "specs need a version")
+}
+
+func (suite *HistorySuite) TestChangelog() {
+	h, err := NewHistory(versionedSpec("1.0", "/owners"), versionedSpec("2.0", "/toys"))
+	require.NoError(suite.T(), err)
+	releases, err := h.Changelog()
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), releases, 2)
+
+	assert.Equal(suite.T(), "2.0", releases[0].Version)
+	assert.Equal(suite.T(), "1.0", releases[0].Previous)
+	var changes []string
+	for _, c := range releases[0].Changes {
+		changes = append(changes, c.String())
+	}
+	assert.ElementsMatch(suite.T(), []string{
+		"breaking: GET /owners: operation was removed",
+		"non-breaking: GET /toys: operation was added",
+	}, changes)
+	assert.Empty(suite.T(), releases[1].Previous)
+}
+
+func (suite *HistorySuite) TestServe() {
+	h, err := NewHistory(versionedSpec("0.9", "/owners"))
+	require.NoError(suite.T(), err)
+	ui, err := New(Spec("pets.yaml", versionedSpec("1.0", "")), SpecHistory(h))
+	require.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), "versions/0.9/pets.yaml", ui.VersionFilename("0.9"))
+	assert.Empty(suite.T(), ui.VersionFilename("0.1"))
+
+	code, body := get(ui, "/versions/0.9/pets.yaml")
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Contains(suite.T(), body, "/owners")
+
+	code, body = get(ui, "/"+ChangelogFilename)
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Contains(suite.T(), body, "<h1>Pets Changelog</h1>")
+	assert.Contains(suite.T(), body, `<a href="versions/1.0/pets.yaml">`)
+	assert.Contains(suite.T(), body, "<code>GET /owners</code> operation was removed")
+
+	_, err = h.Add(versionedSpec("1.1", "/toys"))
+	require.NoError(suite.T(), err)
+	code, _ = get(ui, "/versions/1.1/pets.yaml")
+	assert.Equal(suite.T(), http.StatusOK, code, "versions added later are served")
+	_, body = get(ui, "/"+ChangelogFilename)
+	assert.Contains(suite.T(), body, "<code>GET /toys</code> operation was added")
+}
+
+// failingStore is a MemoryStore refusing to store new specs.
+type failingStore struct {
+	*MemoryStore
+}
+
+func (failingStore) Put(context.Context, []byte) (StoredSpec, error) {
+	return StoredSpec{}, errors.New("store is read-only")
+}
+
+func (suite *HistorySuite) TestPublishFailed() {
+	h, err := NewHistory()
+	require.NoError(suite.T(), err)
+	ui, err := New(Spec("pets.yaml", versionedSpec("1.0", "")), SpecHistory(h),
+		Store(failingStore{NewMemoryStore(versionedSpec("1.0", ""))}))
+	require.NoError(suite.T(), err)
+
+	_, err = ui.Publish("admin", versionedSpec("2.0", "/toys"))
+	require.Error(suite.T(), err)
+
+	assert.Len(suite.T(), h.Versions(), 1, "specs that were not published are not added")
+	code, _ := get(ui, "/versions/2.0/pets.yaml")
+	assert.Equal(suite.T(), http.StatusNotFound, code)
+	_, body := get(ui, "/"+ChangelogFilename)
+	assert.NotContains(suite.T(), body, "<h2>2.0</h2>")
+}
+
+func TestHistory(t *testing.T) {
+	suite.Run(t, new(HistorySuite))
+}
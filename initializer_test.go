@@ -19,9 +19,12 @@
 package swaggerui
 
 import (
+	"bytes"
+	"html/template"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -68,6 +71,44 @@ func (suite *SwaggerInitializerSuite) TestInitializerTmpl() {
 	}
 }
 
+func (suite *SwaggerInitializerSuite) TestSelectorNames() {
+	s := string(executeInitializer(initializerData{
+		URLs:    []specURL{{Name: `O'Reilly & "Co"`, URL: "upstreams/o.yaml"}},
+		Primary: `O'Reilly & "Co"`,
+	}))
+	assert.Contains(suite.T(), s, `{url: "./upstreams/o.yaml", name: "O'Reilly \u0026 \"Co\""}`)
+	assert.Contains(suite.T(), s, `"urls.primaryName": "O'Reilly \u0026 \"Co\"",`)
+	assert.NotContains(suite.T(), s, "&#")
+}
+
+func (suite *SwaggerInitializerSuite) TestNoHTMLEscapes() {
+	// The initializer is JavaScript, so nothing of it may be escaped for HTML.
+	s := string(executeInitializer(initializerData{
+		Filename:       "pets.yaml",
+		LiveReload:     true,
+		ReloadPath:     "reload",
+		Settings:       []setting{{Key: "filter", Value: `"a<b"`}},
+		OAuth:          []setting{{Key: "clientId", Value: `"docs"`}},
+		Plugins:        []pluginRef{{Name: "HelloPlugin", URL: "plugins/HelloPlugin.js"}},
+		RequestHeaders: []headerRef{{Name: `"X-CSRF-Token"`, Value: `readCookie("csrftoken")`, Origins: "[]"}},
+		ResponseHook:   "function (r) { return r.status < 300 && r.ok; }",
+		Preauthorize:   []template.HTML{`preauthorizeApiKey("key", "secret")`},
+	}))
+	for _, escaped := range []string{"&lt;", "&gt;", "&amp;", "&#"} {
+		assert.NotContains(suite.T(), s, escaped)
+	}
+	assert.Contains(suite.T(), s, "for (const cookie of cookies) {")
+}
+
+func (suite *SwaggerInitializerSuite) TestExportedTemplate() {
+	// InitializerTemplate keeps working with the data it was documented with.
+	tmpl, err := template.New(InitializerFilename).Parse(InitializerTemplate)
+	require.NoError(suite.T(), err)
+	var out bytes.Buffer
+	require.NoError(suite.T(), tmpl.Execute(&out, struct{ Prefix, Filename string }{"/docs", "pets.yaml"}))
+	assert.Contains(suite.T(), out.String(), `url: "/docs/pets.yaml",`)
+}
+
 func TestInitializerSuite(t *testing.T) {
 	suite.Run(t, new(SwaggerInitializerSuite))
 }
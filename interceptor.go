@@ -0,0 +1,178 @@
+/*
+ *  interceptor.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// headerName matches valid names of HTTP headers.
+var headerName = regexp.MustCompile("^[!#$%&'*+\\-.^_`|~0-9A-Za-z]+$")
+
+// metaPrefix prefixes the names of the meta tags holding header values.
+const metaPrefix = "swagger-ui-header:"
+
+// RequestHeader is a header added to the requests swagger-ui sends, such as
+// those of "Try it out". Unless ForOrigins says otherwise, it is added to
+// requests to the origin of the page only, so that tokens do not leak to
+// other services.
+type RequestHeader struct {
+	name    string
+	value   string
+	cookie  string
+	meta    func(r *http.Request) string
+	origins []string
+}
+
+// StaticHeader returns a header with a fixed value, such as a tenant.
+func StaticHeader(name, value string) RequestHeader {
+	return RequestHeader{name: name, value: value}
+}
+
+// CookieHeader returns a header with the value of a cookie readable by
+// JavaScript, as in CookieHeader("X-CSRF-Token", "csrftoken"). The header is
+// omitted if the cookie is not set.
+func CookieHeader(name, cookie string) RequestHeader {
+	return RequestHeader{name: name, cookie: cookie}
+}
+
+// MetaHeader returns a header whose value is determined on the server for
+// each request of the page, for example a CSRF token bound to the session.
+// The value is rendered into a meta tag of index.html, from where it is read
+// by the browser. The header is omitted if value returns an empty string.
+func MetaHeader(name string, value func(r *http.Request) string) RequestHeader {
+	return RequestHeader{name: name, meta: value}
+}
+
+// ForOrigins returns a copy of h that is added to requests to the given
+// origins, as in "https://api.example.com", in addition to the origin of the
+// page. The origin "*" matches all requests.
+func (h RequestHeader) ForOrigins(origins ...string) RequestHeader {
+	h.origins = append(h.origins[:len(h.origins):len(h.origins)], origins...)
+	return h
+}
+
+// RequestHeaders adds the headers to the requests swagger-ui sends through a
+// requestInterceptor of the generated initializer.
+func RequestHeaders(headers ...RequestHeader) HandlerOption {
+	return func(suh *SwaggerUi) {
+		for _, h := range headers {
+			if !headerName.MatchString(h.name) {
+				suh.optionErrs = append(suh.optionErrs, fmt.Errorf("invalid header name %q", h.name))
+				continue
+			}
+			suh.requestHeaders = append(suh.requestHeaders, h)
+		}
+	}
+}
+
+// ResponseInterceptor calls the JavaScript function fn with every response
+// swagger-ui receives, for example to log it:
+//
+//	ResponseInterceptor(`function (response) { console.log(response.url, response.status); }`)
+//
+// The return value of fn is ignored, so that the response is passed on unchanged.
+func ResponseInterceptor(fn string) HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.responseHook = fn
+	}
+}
+
+// headerRef is the entry of a request header in the initializer, with its
+// name, value and origins rendered as JavaScript.
+type headerRef struct {
+	Name    template.HTML
+	Value   template.HTML
+	Origins template.HTML
+}
+
+// headerRefs returns the initializer entries of the request headers.
+func (ui *SwaggerUi) headerRefs() []headerRef {
+	var refs []headerRef
+	for _, h := range ui.requestHeaders {
+		var value template.HTML
+		switch {
+		case h.meta != nil:
+			value = template.HTML("readMeta(" + jsString(metaPrefix+h.name) + ")")
+		case h.cookie != "":
+			value = template.HTML("readCookie(" + jsString(h.cookie) + ")")
+		default:
+			value = template.HTML(jsString(h.value))
+		}
+		origins, _ := json.Marshal(append([]string{}, h.origins...))
+		refs = append(refs, headerRef{Name: template.HTML(jsString(h.name)), Value: value, Origins: template.HTML(origins)})
+	}
+	return refs
+}
+
+func jsString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// hasMetaHeaders reports whether index.html has to be rendered for each request.
+func (ui *SwaggerUi) hasMetaHeaders() bool {
+	for _, h := range ui.requestHeaders {
+		if h.meta != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// serveIndex serves index.html with the meta tags of the request headers.
+func (ui *SwaggerUi) serveIndex(w http.ResponseWriter, r *http.Request) {
+	page, err := fs.ReadFile(ui.Merged, "index.html")
+	if err != nil {
+		http.Error(w, "index.html not found", http.StatusInternalServerError)
+		return
+	}
+	var tags bytes.Buffer
+	for _, h := range ui.requestHeaders {
+		if h.meta == nil {
+			continue
+		}
+		if value := h.meta(r); value != "" {
+			fmt.Fprintf(&tags, "<meta name=\"%s\" content=\"%s\">\n", html.EscapeString(metaPrefix+h.name), html.EscapeString(value))
+		}
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	http.ServeContent(w, r, "index.html", time.Time{}, bytes.NewReader(injectHead(page, tags.Bytes())))
+}
+
+// injectHead inserts tags at the end of the head of page, or at its start if it has no head.
+func injectHead(page, tags []byte) []byte {
+	i := bytes.Index(bytes.ToLower(page), []byte("</head>"))
+	if i < 0 {
+		i = 0
+	}
+	out := make([]byte, 0, len(page)+len(tags))
+	out = append(out, page[:i]...)
+	out = append(out, tags...)
+	return append(out, page[i:]...)
+}
@@ -0,0 +1,97 @@
+/*
+ *  interceptor_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type InterceptorSuite struct {
+	suite.Suite
+}
+
+func (suite *InterceptorSuite) TestRequestHeaders() {
+	ui, err := New(Spec("pets.yaml", versionedSpec("1.0", "")), RequestHeaders(
+		StaticHeader("X-Tenant", "pets"),
+		CookieHeader("X-CSRF-Token", "csrftoken").ForOrigins("https://api.example.com"),
+	))
+	require.NoError(suite.T(), err)
+	_, body := get(ui, "/"+InitializerFilename)
+	assert.Contains(suite.T(), body, `    requestInterceptor: function (request) {
+      addHeader(request, "X-Tenant", "pets", []);
+      addHeader(request, "X-CSRF-Token", readCookie("csrftoken"), ["https://api.example.com"]);
+      return request;
+    },`)
+	assert.Contains(suite.T(), body, "function readCookie(name) {")
+	assert.NotContains(suite.T(), body, "responseInterceptor")
+
+	_, err = New(Spec("pets.yaml", versionedSpec("1.0", "")), RequestHeaders(StaticHeader("X Tenant", "pets")))
+	assert.Error(suite.T(), err)
+}
+
+func (suite *InterceptorSuite) TestMetaHeader() {
+	ui, err := New(Spec("pets.yaml", versionedSpec("1.0", "")), RequestHeaders(
+		MetaHeader("X-CSRF-Token", func(r *http.Request) string {
+			return r.Header.Get("X-Session") + `"&`
+		}),
+	))
+	require.NoError(suite.T(), err)
+	_, body := get(ui, "/"+InitializerFilename)
+	assert.Contains(suite.T(), body, `addHeader(request, "X-CSRF-Token", readMeta("swagger-ui-header:X-CSRF-Token"), []);`)
+
+	for _, path := range []string{"/", "/index.html"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-Session", "s1")
+		rec := httptest.NewRecorder()
+		ui.ServeHTTP(rec, req)
+		assert.Equal(suite.T(), http.StatusOK, rec.Code, path)
+		assert.Equal(suite.T(), "no-store", rec.Header().Get("Cache-Control"))
+		assert.Contains(suite.T(), rec.Body.String(),
+			`<meta name="swagger-ui-header:X-CSRF-Token" content="s1&#34;&amp;">`, "the value is rendered per request")
+	}
+}
+
+func (suite *InterceptorSuite) TestInjectHead() {
+	page := []byte("<html><head><title>Swagger UI</title></HEAD><body></body></html>")
+	assert.Equal(suite.T(),
+		"<html><head><title>Swagger UI</title><meta></HEAD><body></body></html>",
+		string(injectHead(page, []byte("<meta>"))))
+	assert.Equal(suite.T(), "<meta><html></html>", string(injectHead([]byte("<html></html>"), []byte("<meta>"))))
+}
+
+func (suite *InterceptorSuite) TestResponseInterceptor() {
+	ui, err := New(Spec("pets.yaml", versionedSpec("1.0", "")),
+		ResponseInterceptor(`function (response) { console.log(response.url, response.status); }`))
+	require.NoError(suite.T(), err)
+	_, body := get(ui, "/"+InitializerFilename)
+	assert.Contains(suite.T(), body, `    responseInterceptor: function (response) {
+      (function (response) { console.log(response.url, response.status); })(response);
+      return response;
+    },`)
+}
+
+func TestInterceptor(t *testing.T) {
+	suite.Run(t, new(InterceptorSuite))
+}
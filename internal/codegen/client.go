@@ -0,0 +1,326 @@
+/*
+ *  client.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+type clientOperation struct {
+	operation
+	Params          []param
+	PathExpr        string
+	BodyType        string
+	BodyJSON        bool
+	BodyContentType string
+	ResultType      string
+}
+
+// GenerateClient emits a Go file with a net/http based client that has one
+// method per operation. The client refers to the types generated by
+// GenerateModels, so both files have to be generated into the same package.
+// Since the client shares the parameter structs with GenerateServer, server and
+// client have to be generated into different packages.
+func GenerateClient(spec *Spec, cfg Config) ([]byte, error) {
+	ops, err := spec.operations()
+	if err != nil {
+		return nil, err
+	}
+
+	g := newTypeGen(spec)
+	g.reserve()
+	for _, op := range ops {
+		g.names[op.Name+"Params"] = true
+	}
+
+	imports := map[string]bool{
+		"context":       true,
+		"encoding/json": true,
+		"io":            true,
+		"net/http":      true,
+		"net/url":       true,
+		"strings":       true,
+	}
+
+	var cops []clientOperation
+	for _, op := range ops {
+		cop, err := newClientOperation(spec, g, op)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", op.Method, op.Path, err)
+		}
+		if len(cop.Params) > 0 {
+			imports["fmt"] = true
+		}
+		if cop.BodyJSON {
+			imports["bytes"] = true
+		}
+		cops = append(cops, cop)
+	}
+	for imp := range g.imports {
+		imports[imp] = true
+	}
+
+	var buf bytes.Buffer
+	if err := clientTemplate.Execute(&buf, cops); err != nil {
+		return nil, fmt.Errorf("executing client template: %w", err)
+	}
+	return render(cfg, imports, append(g.decls, buf.String()))
+}
+
+func newClientOperation(spec *Spec, g *typeGen, op operation) (clientOperation, error) {
+	cop := clientOperation{operation: op}
+	params, err := spec.params(op)
+	if err != nil {
+		return cop, err
+	}
+	cop.Params = params
+
+	if cop.PathExpr, err = pathExpr(op.Path, params); err != nil {
+		return cop, err
+	}
+
+	if op.Body != nil && len(op.Body.Content) > 0 {
+		mediaType := pickJSON(op.Body.Content)
+		if mediaType != "" {
+			cop.BodyJSON = true
+			cop.BodyContentType = mediaType
+			if cop.BodyType, err = g.goType(op.Body.Content[mediaType].Schema, op.Name+"Request"); err != nil {
+				return cop, fmt.Errorf("request body: %w", err)
+			}
+		} else {
+			cop.BodyType = "io.Reader"
+			cop.BodyContentType = sortedMediaTypes(op.Body.Content)[0]
+		}
+	}
+
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		resp := op.Responses[code]
+		if !strings.HasPrefix(code, "2") || resp == nil {
+			continue
+		}
+		if mediaType := pickJSON(resp.Content); mediaType != "" {
+			if cop.ResultType, err = g.goType(resp.Content[mediaType].Schema, op.Name+"Response"); err != nil {
+				return cop, fmt.Errorf("response %s: %w", code, err)
+			}
+		}
+		break
+	}
+	return cop, nil
+}
+
+// pathExpr returns a Go expression building the request path from the params variable.
+func pathExpr(p string, params []param) (string, error) {
+	fields := make(map[string]string)
+	for _, pp := range params {
+		if pp.In == "path" {
+			fields[pp.Name] = pp.Field
+		}
+	}
+	var parts []string
+	rest := p
+	for {
+		m := pathParamRegex.FindStringSubmatchIndex(rest)
+		if m == nil {
+			break
+		}
+		name := rest[m[2]:m[3]]
+		field, ok := fields[name]
+		if !ok {
+			return "", fmt.Errorf("path parameter %q is not declared", name)
+		}
+		if m[0] > 0 {
+			parts = append(parts, strconv.Quote(rest[:m[0]]))
+		}
+		parts = append(parts, "url.PathEscape(fmt.Sprint(params."+field+"))")
+		rest = rest[m[1]:]
+	}
+	if rest != "" || len(parts) == 0 {
+		parts = append(parts, strconv.Quote(rest))
+	}
+	return strings.Join(parts, " + "), nil
+}
+
+func sortedMediaTypes(content map[string]*MediaType) []string {
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// pickJSON returns the first JSON media type of content, or "" if there is none.
+func pickJSON(content map[string]*MediaType) string {
+	for _, t := range sortedMediaTypes(content) {
+		if strings.Contains(t, "json") && content[t] != nil {
+			return t
+		}
+	}
+	return ""
+}
+
+var clientTemplate = template.Must(template.Must(template.New("client").Funcs(funcs).Parse(paramsTemplate)).Parse(`
+// Client calls the operations of the API.
+type Client struct {
+	// BaseURL is the URL the paths of the operations are appended to.
+	BaseURL string
+	// HTTPClient sends the requests. If it is nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+	// RequestEditors are called with every request before it is sent,
+	// e.g. to add authentication.
+	RequestEditors []func(*http.Request) error
+}
+
+// NewClient returns a Client for the API served at baseURL.
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: baseURL}
+}
+
+// APIError is returned for responses with a status code outside of the 2xx range.
+type APIError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *APIError) Error() string {
+	return http.StatusText(e.StatusCode) + ": " + strings.TrimSpace(string(e.Body))
+}
+
+func (c *Client) url(path string, query url.Values) string {
+	u := strings.TrimSuffix(c.BaseURL, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+// do sends req and decodes a JSON response into result, unless result is nil.
+func (c *Client) do(req *http.Request, result interface{}) error {
+	for _, edit := range c.RequestEditors {
+		if err := edit(req); err != nil {
+			return err
+		}
+	}
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		return &APIError{StatusCode: resp.StatusCode, Body: body}
+	}
+	if result == nil {
+		_, err = io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+{{range .}}{{template "params" .}}
+// {{.Name}} calls {{.Method}} {{.Path}}.
+{{- with .Summary}}
+//
+{{comment .}}
+{{- end}}
+func (c *Client) {{.Name}}(ctx context.Context, params {{.Name}}Params{{if .BodyType}}, body {{.BodyType}}{{end}}) ({{with .ResultType}}{{.}}, {{end}}error) {
+{{- if .ResultType}}
+	var result {{.ResultType}}
+{{- end}}
+	query := url.Values{}
+{{- range .Params}}
+{{- if eq .In "query"}}
+{{- if .Slice}}
+	for _, v := range params.{{.Field}} {
+		query.Add({{quote .Name}}, fmt.Sprint(v))
+	}
+{{- else if .Pointer}}
+	if params.{{.Field}} != nil {
+		query.Set({{quote .Name}}, fmt.Sprint(*params.{{.Field}}))
+	}
+{{- else}}
+	query.Set({{quote .Name}}, fmt.Sprint(params.{{.Field}}))
+{{- end}}
+{{- end}}
+{{- end}}
+{{- if .BodyJSON}}
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return {{if .ResultType}}result, {{end}}err
+	}
+	req, err := http.NewRequestWithContext(ctx, {{quote .Method}}, c.url({{.PathExpr}}, query), bytes.NewReader(encoded))
+{{- else if .BodyType}}
+	req, err := http.NewRequestWithContext(ctx, {{quote .Method}}, c.url({{.PathExpr}}, query), body)
+{{- else}}
+	req, err := http.NewRequestWithContext(ctx, {{quote .Method}}, c.url({{.PathExpr}}, query), nil)
+{{- end}}
+	if err != nil {
+		return {{if .ResultType}}result, {{end}}err
+	}
+{{- with .BodyContentType}}
+	req.Header.Set("Content-Type", {{quote .}})
+{{- end}}
+{{- if .ResultType}}
+	req.Header.Set("Accept", "application/json")
+{{- end}}
+{{- range .Params}}
+{{- if eq .In "header"}}
+{{- if .Slice}}
+	for _, v := range params.{{.Field}} {
+		req.Header.Add({{quote .Name}}, fmt.Sprint(v))
+	}
+{{- else if .Pointer}}
+	if params.{{.Field}} != nil {
+		req.Header.Set({{quote .Name}}, fmt.Sprint(*params.{{.Field}}))
+	}
+{{- else}}
+	req.Header.Set({{quote .Name}}, fmt.Sprint(params.{{.Field}}))
+{{- end}}
+{{- else if eq .In "cookie"}}
+{{- if .Pointer}}
+	if params.{{.Field}} != nil {
+		req.AddCookie(&http.Cookie{Name: {{quote .Name}}, Value: fmt.Sprint(*params.{{.Field}})})
+	}
+{{- else}}
+	req.AddCookie(&http.Cookie{Name: {{quote .Name}}, Value: fmt.Sprint(params.{{.Field}})})
+{{- end}}
+{{- end}}
+{{- end}}
+{{- if .ResultType}}
+	err = c.do(req, &result)
+	return result, err
+{{- else}}
+	return c.do(req, nil)
+{{- end}}
+}
+{{end}}`))
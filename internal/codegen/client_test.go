@@ -0,0 +1,174 @@
+/*
+ *  client_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package codegen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const clientTest = `package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /pets", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery != "limit=2&tags=a&tags=b" || r.Header.Get("X-Tenant") != "t1" {
+			http.Error(w, "unexpected request "+r.URL.RawQuery, http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, ` + "`" + `[{"id":1,"name":"Rex"},{"id":2,"name":"Tom","status":"sold"}]` + "`" + `)
+	})
+	mux.HandleFunc("POST /pets", func(w http.ResponseWriter, r *http.Request) {
+		var p NewPet
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil || r.Header.Get("Content-Type") != "application/json" {
+			http.Error(w, "bad body", http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(Pet{NewPet: p, ID: 3})
+	})
+	mux.HandleFunc("GET /pets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, ` + "`" + `{"code":404,"message":"no pet ` + "`" + ` + r.PathValue("id") + ` + "`" + `"}` + "`" + `, http.StatusNotFound)
+	})
+	mux.HandleFunc("DELETE /pets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if c, err := r.Cookie("session"); err != nil || c.Value != "s1" || r.PathValue("id") != "7" {
+			http.Error(w, "bad delete", http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	c := NewClient(ts.URL + "/")
+	ctx := context.Background()
+	limit := int32(2)
+
+	pets, err := c.ListPets(ctx, ListPetsParams{Limit: &limit, Tags: []string{"a", "b"}, XTenant: "t1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pets) != 2 || pets[1].Name != "Tom" || *pets[1].Status != StatusSold {
+		t.Fatalf("unexpected pets %+v", pets)
+	}
+
+	pet, err := c.CreatePet(ctx, CreatePetParams{}, NewPet{Name: "Max"})
+	if err != nil || pet.ID != 3 || pet.Name != "Max" {
+		t.Fatalf("unexpected pet %+v: %v", pet, err)
+	}
+
+	_, err = c.ShowPetByID(ctx, ShowPetByIDParams{PetID: 5})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("unexpected error %v", err)
+	}
+	var e Error
+	if err := json.Unmarshal(apiErr.Body, &e); err != nil || e.Message != "no pet 5" {
+		t.Fatalf("unexpected error body %s", apiErr.Body)
+	}
+
+	if err := c.DeletePet(ctx, DeletePetParams{PetID: 7, Session: "s1"}); err != nil {
+		t.Fatal(err)
+	}
+}
+`
+
+type ClientSuite struct {
+	suite.Suite
+}
+
+func (suite *ClientSuite) generate() (models, client string) {
+	spec, _ := loadPetstore(suite.T())
+	cfg := Config{Package: "api", SpecFilename: "petstore.yaml"}
+	m, err := GenerateModels(spec, cfg)
+	require.NoError(suite.T(), err)
+	c, err := GenerateClient(spec, cfg)
+	require.NoError(suite.T(), err)
+	return string(m), string(c)
+}
+
+func (suite *ClientSuite) TestGenerateClient() {
+	_, src := suite.generate()
+	assert.Contains(suite.T(), src, "func (c *Client) ListPets(ctx context.Context, params ListPetsParams) ([]Pet, error)")
+	assert.Contains(suite.T(), src, "func (c *Client) CreatePet(ctx context.Context, params CreatePetParams, body NewPet) (Pet, error)")
+	assert.Contains(suite.T(), src, "func (c *Client) DeletePet(ctx context.Context, params DeletePetParams) error")
+	assert.Contains(suite.T(), src, `c.url("/pets/"+url.PathEscape(fmt.Sprint(params.PetID)), query)`)
+}
+
+func (suite *ClientSuite) TestInlineTypes() {
+	spec, err := Load([]byte(`
+paths:
+  /status:
+    get:
+      operationId: getStatus
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  healthy:
+                    type: boolean
+`))
+	require.NoError(suite.T(), err)
+	src, err := GenerateClient(spec, Config{Package: "api"})
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(src), "type GetStatusResponse struct")
+	assert.Contains(suite.T(), string(src), "(GetStatusResponse, error)")
+}
+
+func (suite *ClientSuite) TestPathExpr() {
+	params := []param{{Parameter: &Parameter{Name: "id", In: "path"}, Field: "ID"}}
+	e, err := pathExpr("/a/{id}/b", params)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), `"/a/" + url.PathEscape(fmt.Sprint(params.ID)) + "/b"`, e)
+	e, err = pathExpr("/", nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), `"/"`, e)
+	_, err = pathExpr("/{other}", params)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *ClientSuite) TestCompiles() {
+	models, client := suite.generate()
+	goTest(suite.T(), map[string][]byte{
+		"models_gen.go":  []byte(models),
+		"client_gen.go":  []byte(client),
+		"client_test.go": []byte(clientTest),
+	})
+}
+
+func TestClient(t *testing.T) {
+	suite.Run(t, new(ClientSuite))
+}
@@ -0,0 +1,103 @@
+/*
+ *  codegen_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package codegen
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func loadPetstore(t *testing.T) (*Spec, []byte) {
+	data, err := os.ReadFile(filepath.Join("testdata", "petstore.yaml"))
+	require.NoError(t, err)
+	spec, err := Load(data)
+	require.NoError(t, err)
+	return spec, data
+}
+
+// goTest writes files into a temporary module that depends on this module
+// and runs go test in it. It is skipped in short mode or without a go tool.
+func goTest(t *testing.T, files map[string][]byte) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping compilation of generated code in short mode")
+	}
+	gotool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	gomod := "module example.com/generated\n\ngo 1.22\n\n" +
+		"require github.com/mwmahlberg/swagger-ui v0.0.0\n\n" +
+		"replace github.com/mwmahlberg/swagger-ui => " + root + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0644))
+	sum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.sum"), sum, 0644))
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), content, 0644))
+	}
+
+	cmd := exec.Command(gotool, "test", "-mod=mod", "./...")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoErrorf(t, err, "%s", out)
+}
+
+type NamesSuite struct {
+	suite.Suite
+}
+
+func (suite *NamesSuite) TestExportedName() {
+	testCases := []struct {
+		in       string
+		expected string
+	}{
+		{"listPets", "ListPets"},
+		{"show_pet_by_id", "ShowPetByID"},
+		{"pet-id", "PetID"},
+		{"X-Tenant", "XTenant"},
+		{"get /pets/{id}", "GetPetsID"},
+		{"HTTPServer", "HTTPServer"},
+		{"2fa", "X2fa"},
+		{"", "X"},
+	}
+	for _, tC := range testCases {
+		assert.Equal(suite.T(), tC.expected, exportedName(tC.in), tC.in)
+	}
+}
+
+func (suite *NamesSuite) TestUnexportedName() {
+	assert.Equal(suite.T(), "petID", unexportedName("pet-id"))
+	assert.Equal(suite.T(), "idFoo", unexportedName("IDFoo"))
+	assert.Equal(suite.T(), "type_", unexportedName("type"))
+}
+
+func TestNames(t *testing.T) {
+	suite.Run(t, new(NamesSuite))
+}
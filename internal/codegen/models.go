@@ -0,0 +1,74 @@
+/*
+ *  models.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"sort"
+	"strconv"
+)
+
+// Config configures the model and client generators.
+type Config struct {
+	// Package is the name of the generated package.
+	Package string
+	// SpecFilename is the name the spec was read from. It is only used in the header comment.
+	SpecFilename string
+}
+
+// GenerateModels emits a Go file with a type for every schema in components.schemas.
+func GenerateModels(spec *Spec, cfg Config) ([]byte, error) {
+	g := newTypeGen(spec)
+	g.reserve()
+	if err := g.components(); err != nil {
+		return nil, err
+	}
+	return render(cfg, g.imports, g.decls)
+}
+
+// render assembles and formats a generated file.
+func render(cfg Config, imports map[string]bool, decls []string) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by swaggerui-gen from %s. DO NOT EDIT.\n\npackage %s\n\n", path.Base(cfg.SpecFilename), cfg.Package)
+
+	if len(imports) > 0 {
+		paths := make([]string, 0, len(imports))
+		for p := range imports {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+		buf.WriteString("import (\n")
+		for _, p := range paths {
+			buf.WriteString("\t" + strconv.Quote(p) + "\n")
+		}
+		buf.WriteString(")\n\n")
+	}
+	for _, d := range decls {
+		buf.WriteString(d + "\n")
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
@@ -0,0 +1,129 @@
+/*
+ *  models_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const modelsTest = `package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestRoundTrip(t *testing.T) {
+	var a Animal
+	if err := a.FromCat(Cat{Meows: true}); err != nil {
+		t.Fatal(err)
+	}
+	o := Owner{
+		Cat:      Cat{Meows: true},
+		Name:     "Alice",
+		Labels:   map[string]string{"a": "b"},
+		Address:  &OwnerAddress{City: new(string)},
+		Pets:     []Animal{a},
+		Priority: new(Priority),
+		Since:    new(Timestamp),
+	}
+	*o.Priority = Priority2
+	*o.Since = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	b, err := json.Marshal(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expected = ` + "`" + `{"meows":true,"name":"Alice","nickname":null,"labels":{"a":"b"},"address":{"city":""},"pets":[{"meows":true}],"priority":2,"since":"2023-01-02T03:04:05Z"}` + "`" + `
+	if string(b) != expected {
+		t.Fatalf("got %s", b)
+	}
+
+	var decoded Owner
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	cat, err := decoded.Pets[0].AsCat()
+	if err != nil || !cat.Meows {
+		t.Fatalf("got %v, %v", cat, err)
+	}
+}
+`
+
+type ModelsSuite struct {
+	suite.Suite
+}
+
+func (suite *ModelsSuite) generate() string {
+	data, err := os.ReadFile(filepath.Join("testdata", "models.yaml"))
+	require.NoError(suite.T(), err)
+	spec, err := Load(data)
+	require.NoError(suite.T(), err)
+	src, err := GenerateModels(spec, Config{Package: "models", SpecFilename: "models.yaml"})
+	require.NoError(suite.T(), err)
+	return string(src)
+}
+
+func (suite *ModelsSuite) TestGenerateModels() {
+	src := suite.generate()
+	assert.Contains(suite.T(), src, "// Animal is either a cat or a dog.")
+	assert.Contains(suite.T(), src, "func (u Animal) AsCat() (Cat, error)")
+	assert.Contains(suite.T(), src, "func (u *Animal) FromDog(v Dog) error")
+	assert.Contains(suite.T(), src, "type Priority int64")
+	assert.Contains(suite.T(), src, "Priority3 Priority = 3")
+	assert.Contains(suite.T(), src, "type Timestamp = time.Time")
+	assert.Regexp(suite.T(), `Nickname\s+\*string\s+`+"`"+`json:"nickname"`+"`", src)
+	assert.Regexp(suite.T(), `Avatar\s+\[\]byte\s+`+"`"+`json:"avatar,omitempty"`+"`", src)
+	assert.Regexp(suite.T(), `Labels\s+map\[string\]string`, src)
+	assert.Regexp(suite.T(), `Address\s+\*OwnerAddress`, src)
+	assert.Contains(suite.T(), src, "type OwnerAddress struct")
+}
+
+func (suite *ModelsSuite) TestCompiles() {
+	goTest(suite.T(), map[string][]byte{
+		"models_gen.go":  []byte(suite.generate()),
+		"models_test.go": []byte(modelsTest),
+	})
+}
+
+func (suite *ModelsSuite) TestUnresolvableReference() {
+	spec, err := Load([]byte(`
+components:
+  schemas:
+    Foo:
+      type: object
+      properties:
+        bar:
+          $ref: '#/components/schemas/Missing'
+`))
+	require.NoError(suite.T(), err)
+	_, err = GenerateModels(spec, Config{Package: "models"})
+	assert.ErrorContains(suite.T(), err, "unresolvable")
+}
+
+func TestModels(t *testing.T) {
+	suite.Run(t, new(ModelsSuite))
+}
@@ -0,0 +1,100 @@
+/*
+ *  names.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package codegen
+
+import (
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+// initialisms are rendered in all caps, following the Go naming conventions.
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "JSON": true,
+	"URI": true, "URL": true, "UUID": true, "XML": true, "YAML": true,
+}
+
+// words splits s at non alphanumeric characters and lower to upper case transitions.
+func words(s string) []string {
+	var result []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			result = append(result, string(cur))
+			cur = nil
+		}
+	}
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0 && len(cur) > 0:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		cur = append(cur, r)
+	}
+	flush()
+	return result
+}
+
+// exportedName converts s into an exported Go identifier.
+func exportedName(s string) string {
+	var b strings.Builder
+	for _, w := range words(s) {
+		upper := strings.ToUpper(w)
+		if initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		r := []rune(strings.ToLower(w))
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	name := b.String()
+	if name == "" {
+		return "X"
+	}
+	if !unicode.IsLetter([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// unexportedName converts s into an unexported Go identifier that is not a keyword.
+func unexportedName(s string) string {
+	name := exportedName(s)
+	ws := words(name)
+	if len(ws) > 0 && initialisms[ws[0]] {
+		name = strings.ToLower(ws[0]) + name[len(ws[0]):]
+	} else {
+		r := []rune(name)
+		r[0] = unicode.ToLower(r[0])
+		name = string(r)
+	}
+	if token.IsKeyword(name) {
+		name += "_"
+	}
+	return name
+}
@@ -0,0 +1,442 @@
+/*
+ *  server.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// ServerConfig configures the server generator.
+type ServerConfig struct {
+	// Package is the name of the generated package.
+	Package string
+	// SpecFilename is the name the spec was read from. It is used to serve the spec.
+	SpecFilename string
+	// Spec is the raw spec that is embedded into the generated code.
+	Spec []byte
+}
+
+// param is a parameter prepared for code generation.
+type param struct {
+	*Parameter
+	Field    string
+	Type     string
+	Elem     string // element type of slices, otherwise equal to Type
+	Slice    bool
+	Pointer  bool
+	Wildcard string // name of the ServeMux wildcard for path parameters
+}
+
+type serverOperation struct {
+	operation
+	Pattern  string
+	Params   []param
+	HasQuery bool
+}
+
+var pathParamRegex = regexp.MustCompile(`\{([^}]+)\}`)
+
+// GenerateServer emits a Go file containing an interface with one method per
+// operation, a parameter struct per operation and an http.Handler routing
+// Go 1.22 ServeMux patterns to the interface and serving a SwaggerUi for the spec.
+func GenerateServer(spec *Spec, cfg ServerConfig) ([]byte, error) {
+	ops, err := spec.operations()
+	if err != nil {
+		return nil, err
+	}
+
+	var data = struct {
+		Package      string
+		Source       string
+		SpecFilename string
+		SpecLiteral  string
+		Operations   []serverOperation
+	}{
+		Package:      cfg.Package,
+		Source:       path.Base(cfg.SpecFilename),
+		SpecFilename: servedFilename(cfg.SpecFilename),
+		SpecLiteral:  goLiteral(string(cfg.Spec)),
+	}
+
+	for _, op := range ops {
+		sop, err := newServerOperation(spec, op)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", op.Method, op.Path, err)
+		}
+		data.Operations = append(data.Operations, sop)
+	}
+
+	var buf bytes.Buffer
+	if err := serverTemplate.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("executing server template: %w", err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated server: %w", err)
+	}
+	return src, nil
+}
+
+func newServerOperation(spec *Spec, op operation) (serverOperation, error) {
+	sop := serverOperation{operation: op}
+	params, err := spec.params(op)
+	if err != nil {
+		return sop, err
+	}
+	sop.Params = params
+
+	wildcards := make(map[string]string)
+	for _, p := range params {
+		switch p.In {
+		case "path":
+			wildcards[p.Name] = p.Wildcard
+		case "query":
+			sop.HasQuery = true
+		}
+	}
+	pattern, err := muxPattern(op.Path, wildcards)
+	if err != nil {
+		return sop, err
+	}
+	sop.Pattern = op.Method + " " + pattern
+	return sop, nil
+}
+
+// params prepares the parameters of op for code generation.
+func (s *Spec) params(op operation) ([]param, error) {
+	var params []param
+	fields := make(map[string]bool)
+	for _, p := range op.Parameters {
+		pp := param{Parameter: p, Field: exportedName(p.Name)}
+		if fields[pp.Field] {
+			pp.Field += exportedName(p.In)
+		}
+		fields[pp.Field] = true
+
+		elem, slice, err := s.parameterType(p.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %q: %w", p.Name, err)
+		}
+		pp.Elem, pp.Slice = elem, slice
+		pp.Type = elem
+		if slice {
+			pp.Type = "[]" + elem
+		}
+		pp.Pointer = !slice && !p.Required && p.In != "path"
+
+		switch p.In {
+		case "path":
+			pp.Wildcard = unexportedName(p.Name)
+		case "query", "header", "cookie":
+		default:
+			return nil, fmt.Errorf("parameter %q: unsupported location %q", p.Name, p.In)
+		}
+		params = append(params, pp)
+	}
+	return params, nil
+}
+
+// muxPattern translates an OpenAPI path template into a ServeMux pattern.
+func muxPattern(p string, wildcards map[string]string) (string, error) {
+	segments := strings.Split(p, "/")
+	for i, seg := range segments {
+		if !strings.Contains(seg, "{") {
+			continue
+		}
+		m := pathParamRegex.FindStringSubmatch(seg)
+		if m == nil || m[0] != seg {
+			return "", fmt.Errorf("path segment %q: ServeMux wildcards must span a whole segment", seg)
+		}
+		w, ok := wildcards[m[1]]
+		if !ok {
+			return "", fmt.Errorf("path parameter %q is not declared", m[1])
+		}
+		segments[i] = "{" + w + "}"
+	}
+	pattern := strings.Join(segments, "/")
+	if strings.HasSuffix(pattern, "/") {
+		// A trailing slash would turn the pattern into a prefix match.
+		pattern += "{$}"
+	}
+	return pattern, nil
+}
+
+// parameterType returns the Go type of a parameter schema. Only primitive types
+// and arrays of primitive types are supported.
+func (s *Spec) parameterType(schema *Schema) (string, bool, error) {
+	schema, err := s.resolve(schema)
+	if err != nil {
+		return "", false, err
+	}
+	if schema == nil {
+		return "string", false, nil
+	}
+	if schema.Type.Name() == "array" {
+		items, err := s.resolve(schema.Items)
+		if err != nil {
+			return "", false, err
+		}
+		elem, err := primitiveType(items)
+		return elem, true, err
+	}
+	t, err := primitiveType(schema)
+	return t, false, err
+}
+
+// resolve follows references to component schemas.
+func (s *Spec) resolve(schema *Schema) (*Schema, error) {
+	for depth := 0; schema != nil && schema.Ref != ""; depth++ {
+		if depth > 32 || !strings.HasPrefix(schema.Ref, "#/components/schemas/") {
+			return nil, fmt.Errorf("unresolvable schema reference %q", schema.Ref)
+		}
+		resolved, ok := s.Components.Schemas[refName(schema.Ref)]
+		if !ok {
+			return nil, fmt.Errorf("unresolvable schema reference %q", schema.Ref)
+		}
+		schema = resolved
+	}
+	return schema, nil
+}
+
+func primitiveType(schema *Schema) (string, error) {
+	if schema == nil {
+		return "string", nil
+	}
+	switch schema.Type.Name() {
+	case "integer":
+		if schema.Format == "int32" {
+			return "int32", nil
+		}
+		return "int64", nil
+	case "number":
+		if schema.Format == "float" {
+			return "float32", nil
+		}
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "string", "":
+		return "string", nil
+	}
+	return "", fmt.Errorf("unsupported parameter type %q", schema.Type.Name())
+}
+
+// servedFilename returns a name that passes the SwaggerUi file name validation.
+func servedFilename(name string) string {
+	base := path.Base(name)
+	switch strings.ToLower(path.Ext(base)) {
+	case ".yaml", ".json":
+		return base
+	case ".yml":
+		return strings.TrimSuffix(base, path.Ext(base)) + ".yaml"
+	}
+	return "swagger.yaml"
+}
+
+// goLiteral returns s as a raw string literal if possible and as an interpreted one otherwise.
+func goLiteral(s string) string {
+	if strings.ContainsAny(s, "`\r") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
+
+// parseFunc returns the name of the generated helper converting a string to t.
+func parseFunc(t string) string {
+	return "parse" + exportedName(t)
+}
+
+func comment(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+	return "// " + strings.ReplaceAll(s, "\n", "\n// ")
+}
+
+var funcs = template.FuncMap{
+	"parseFunc": parseFunc,
+	"comment":   comment,
+	"quote":     strconv.Quote,
+}
+
+// paramsTemplate declares the parameter struct of an operation. It is shared
+// by the server and the client, which therefore use the same structs.
+const paramsTemplate = `{{define "params"}}
+// {{.Name}}Params holds the parameters of {{.Name}}.
+type {{.Name}}Params struct {
+{{- range .Params}}
+	// {{.Field}} is the {{.In}} parameter {{quote .Name}}.
+{{- with .Description}}
+	{{comment .}}
+{{- end}}
+	{{.Field}} {{if .Pointer}}*{{end}}{{.Type}}
+{{- end}}
+}
+{{end}}`
+
+var serverTemplate = template.Must(template.Must(template.New("server").Funcs(funcs).Parse(paramsTemplate)).Parse(`// Code generated by swaggerui-gen from {{.Source}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	swaggerui "github.com/mwmahlberg/swagger-ui"
+)
+
+// SpecFilename is the name under which the handler serves the spec.
+const SpecFilename = {{quote .SpecFilename}}
+
+// specContent is the spec the code was generated from.
+var specContent = []byte({{.SpecLiteral}})
+
+// ServerInterface is implemented by servers of the API.
+type ServerInterface interface {
+{{- range .Operations}}
+	// {{.Name}} handles {{.Method}} {{.Path}}.
+{{- with .Summary}}
+	//
+	{{comment .}}
+{{- end}}
+	{{.Name}}(w http.ResponseWriter, r *http.Request, params {{.Name}}Params)
+{{- end}}
+}
+{{range .Operations}}{{template "params" .}}{{end}}
+// NewHandler returns an http.Handler that routes the operations of the spec to
+// si and serves a SwaggerUi for the spec below docsPrefix, e.g. "/api-docs/".
+func NewHandler(si ServerInterface, docsPrefix string) (http.Handler, error) {
+	ui, err := swaggerui.New(swaggerui.Spec(SpecFilename, specContent))
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	docsPrefix = "/" + strings.Trim(docsPrefix, "/") + "/"
+	mux.Handle(docsPrefix, http.StripPrefix(docsPrefix, ui))
+{{range .Operations}}
+	mux.HandleFunc({{quote .Pattern}}, func(w http.ResponseWriter, r *http.Request) {
+		var params {{.Name}}Params
+{{- if .HasQuery}}
+		query := r.URL.Query()
+{{- end}}
+{{- range .Params}}
+		{
+{{- if eq .In "path"}}
+			values := []string{r.PathValue({{quote .Wildcard}})}
+{{- else if eq .In "query"}}
+			values := query[{{quote .Name}}]
+{{- else if eq .In "header"}}
+			values := r.Header.Values({{quote .Name}})
+{{- else}}
+			var values []string
+			if c, err := r.Cookie({{quote .Name}}); err == nil {
+				values = []string{c.Value}
+			}
+{{- end}}
+{{- if and .Required (ne .In "path")}}
+			if len(values) == 0 {
+				http.Error(w, {{quote (print "missing required " .In " parameter " .Name)}}, http.StatusBadRequest)
+				return
+			}
+{{- end}}
+{{- if .Slice}}
+			if len(values) == 1 {
+				values = strings.Split(values[0], ",")
+			}
+			for _, v := range values {
+				parsed, err := {{parseFunc .Elem}}(v)
+				if err != nil {
+					http.Error(w, "invalid {{.In}} parameter {{.Name}}: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+				params.{{.Field}} = append(params.{{.Field}}, parsed)
+			}
+{{- else}}
+			if len(values) > 0 {
+				parsed, err := {{parseFunc .Elem}}(values[0])
+				if err != nil {
+					http.Error(w, "invalid {{.In}} parameter {{.Name}}: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+				params.{{.Field}} = {{if .Pointer}}&{{end}}parsed
+			}
+{{- end}}
+		}
+{{- end}}
+		si.{{.Name}}(w, r, params)
+	})
+{{end}}
+	return mux, nil
+}
+
+func parseString(s string) (string, error) {
+	return s, nil
+}
+
+func parseBool(s string) (bool, error) {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, fmt.Errorf("%q is not a boolean", s)
+	}
+	return v, nil
+}
+
+func parseInt32(s string) (int32, error) {
+	v, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a 32 bit integer", s)
+	}
+	return int32(v), nil
+}
+
+func parseInt64(s string) (int64, error) {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a 64 bit integer", s)
+	}
+	return v, nil
+}
+
+func parseFloat32(s string) (float32, error) {
+	v, err := strconv.ParseFloat(s, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	return float32(v), nil
+}
+
+func parseFloat64(s string) (float64, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	return v, nil
+}
+`))
@@ -0,0 +1,181 @@
+/*
+ *  server_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package codegen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const serverTest = `package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type impl struct{}
+
+func (impl) ListPets(w http.ResponseWriter, r *http.Request, p ListPetsParams) {
+	fmt.Fprintf(w, "list %d %v %s", *p.Limit, p.Tags, p.XTenant)
+}
+
+func (impl) CreatePet(w http.ResponseWriter, r *http.Request, p CreatePetParams) {
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (impl) ShowPetByID(w http.ResponseWriter, r *http.Request, p ShowPetByIDParams) {
+	fmt.Fprintf(w, "show %d", p.PetID)
+}
+
+func (impl) DeletePet(w http.ResponseWriter, r *http.Request, p DeletePetParams) {
+	fmt.Fprintf(w, "delete %d %s", p.PetID, p.Session)
+}
+
+func TestHandler(t *testing.T) {
+	h, err := NewHandler(impl{}, "api-docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	testCases := []struct {
+		method, path, header, cookie string
+		status                       int
+		body                         string
+	}{
+		{"GET", "/pets?limit=3&tags=a,b", "t1", "", 200, "list 3 [a b] t1"},
+		{"GET", "/pets?limit=x", "t1", "", 400, "invalid query parameter limit"},
+		{"GET", "/pets?limit=1", "", "", 400, "missing required header parameter X-Tenant"},
+		{"POST", "/pets", "", "", 201, ""},
+		{"GET", "/pets/42", "", "", 200, "show 42"},
+		{"GET", "/pets/abc", "", "", 400, "invalid path parameter pet-id"},
+		{"DELETE", "/pets/42", "", "s1", 200, "delete 42 s1"},
+		{"DELETE", "/pets/42", "", "", 400, "missing required cookie parameter session"},
+		{"GET", "/api-docs/petstore.yaml", "", "", 200, "openapi: 3.0.3"},
+	}
+	for _, tC := range testCases {
+		req, _ := http.NewRequest(tC.method, ts.URL+tC.path, nil)
+		if tC.header != "" {
+			req.Header.Set("X-Tenant", tC.header)
+		}
+		if tC.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "session", Value: tC.cookie})
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != tC.status || !strings.Contains(string(body), tC.body) {
+			t.Errorf("%s %s: got %d %q, expected %d %q", tC.method, tC.path, resp.StatusCode, body, tC.status, tC.body)
+		}
+	}
+}
+`
+
+type ServerSuite struct {
+	suite.Suite
+}
+
+func (suite *ServerSuite) generate() string {
+	spec, data := loadPetstore(suite.T())
+	src, err := GenerateServer(spec, ServerConfig{Package: "api", SpecFilename: "testdata/petstore.yaml", Spec: data})
+	require.NoError(suite.T(), err)
+	return string(src)
+}
+
+func (suite *ServerSuite) TestGenerateServer() {
+	src := suite.generate()
+	assert.Contains(suite.T(), src, "// Code generated by swaggerui-gen from petstore.yaml. DO NOT EDIT.")
+	assert.Contains(suite.T(), src, "package api")
+	assert.Contains(suite.T(), src, "ListPets(w http.ResponseWriter, r *http.Request, params ListPetsParams)")
+	assert.Contains(suite.T(), src, "Limit *int32")
+	assert.Contains(suite.T(), src, "Tags []string")
+	assert.Contains(suite.T(), src, "XTenant string")
+	assert.Contains(suite.T(), src, "PetID int64")
+	assert.Contains(suite.T(), src, `mux.HandleFunc("GET /pets/{petID}"`)
+	assert.Contains(suite.T(), src, `mux.HandleFunc("DELETE /pets/{petID}"`)
+	assert.Contains(suite.T(), src, `const SpecFilename = "petstore.yaml"`)
+}
+
+func (suite *ServerSuite) TestCompiles() {
+	goTest(suite.T(), map[string][]byte{
+		"server_gen.go":  []byte(suite.generate()),
+		"server_test.go": []byte(serverTest),
+	})
+}
+
+func (suite *ServerSuite) TestMuxPattern() {
+	testCases := []struct {
+		path     string
+		expected string
+		fails    bool
+	}{
+		{path: "/", expected: "/{$}"},
+		{path: "/pets/", expected: "/pets/{$}"},
+		{path: "/pets/{id}", expected: "/pets/{id}"},
+		{path: "/files/{id}.json", fails: true},
+		{path: "/pets/{unknown}", fails: true},
+	}
+	for _, tC := range testCases {
+		p, err := muxPattern(tC.path, map[string]string{"id": "id"})
+		if tC.fails {
+			assert.Error(suite.T(), err, tC.path)
+			continue
+		}
+		assert.NoError(suite.T(), err, tC.path)
+		assert.Equal(suite.T(), tC.expected, p)
+	}
+}
+
+func (suite *ServerSuite) TestServedFilename() {
+	assert.Equal(suite.T(), "api.yaml", servedFilename("specs/api.yaml"))
+	assert.Equal(suite.T(), "api.yaml", servedFilename("api.yml"))
+	assert.Equal(suite.T(), "api.json", servedFilename("api.json"))
+	assert.Equal(suite.T(), "swagger.yaml", servedFilename("api"))
+}
+
+func (suite *ServerSuite) TestDuplicateOperationNames() {
+	spec, err := Load([]byte(`
+paths:
+  /a:
+    get:
+      operationId: foo
+  /b:
+    get:
+      operationId: Foo
+`))
+	require.NoError(suite.T(), err)
+	_, err = GenerateServer(spec, ServerConfig{Package: "api"})
+	assert.ErrorContains(suite.T(), err, "already used")
+}
+
+func TestServer(t *testing.T) {
+	suite.Run(t, new(ServerSuite))
+}
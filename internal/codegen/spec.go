@@ -0,0 +1,327 @@
+/*
+ *  spec.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// Package codegen generates Go source code from an OpenAPI 3 spec.
+package codegen
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// methods lists the HTTP methods of a path item in the order operations are generated.
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Spec is the subset of an OpenAPI 3 document the generators work with.
+type Spec struct {
+	Paths      map[string]*PathItem `yaml:"paths"`
+	Components struct {
+		Schemas       map[string]*Schema      `yaml:"schemas"`
+		Parameters    map[string]*Parameter   `yaml:"parameters"`
+		RequestBodies map[string]*RequestBody `yaml:"requestBodies"`
+	} `yaml:"components"`
+}
+
+// PathItem describes the operations available on a single path.
+type PathItem struct {
+	Parameters []*Parameter `yaml:"parameters"`
+	Get        *Operation   `yaml:"get"`
+	Put        *Operation   `yaml:"put"`
+	Post       *Operation   `yaml:"post"`
+	Delete     *Operation   `yaml:"delete"`
+	Options    *Operation   `yaml:"options"`
+	Head       *Operation   `yaml:"head"`
+	Patch      *Operation   `yaml:"patch"`
+	Trace      *Operation   `yaml:"trace"`
+}
+
+func (p *PathItem) operation(method string) *Operation {
+	switch method {
+	case "get":
+		return p.Get
+	case "put":
+		return p.Put
+	case "post":
+		return p.Post
+	case "delete":
+		return p.Delete
+	case "options":
+		return p.Options
+	case "head":
+		return p.Head
+	case "patch":
+		return p.Patch
+	case "trace":
+		return p.Trace
+	}
+	return nil
+}
+
+// Operation describes a single API operation on a path.
+type Operation struct {
+	OperationID string               `yaml:"operationId"`
+	Summary     string               `yaml:"summary"`
+	Description string               `yaml:"description"`
+	Parameters  []*Parameter         `yaml:"parameters"`
+	RequestBody *RequestBody         `yaml:"requestBody"`
+	Responses   map[string]*Response `yaml:"responses"`
+}
+
+// Parameter describes a single operation parameter.
+type Parameter struct {
+	Ref         string  `yaml:"$ref"`
+	Name        string  `yaml:"name"`
+	In          string  `yaml:"in"`
+	Description string  `yaml:"description"`
+	Required    bool    `yaml:"required"`
+	Schema      *Schema `yaml:"schema"`
+}
+
+// RequestBody describes a single request body.
+type RequestBody struct {
+	Ref         string                `yaml:"$ref"`
+	Description string                `yaml:"description"`
+	Required    bool                  `yaml:"required"`
+	Content     map[string]*MediaType `yaml:"content"`
+}
+
+// Response describes a single response of an operation.
+type Response struct {
+	Description string                `yaml:"description"`
+	Content     map[string]*MediaType `yaml:"content"`
+}
+
+// MediaType provides the schema for a media type.
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+// Schema is the subset of a JSON schema relevant for generating Go types.
+type Schema struct {
+	Ref                  string        `yaml:"$ref"`
+	Type                 SchemaType    `yaml:"type"`
+	Format               string        `yaml:"format"`
+	Description          string        `yaml:"description"`
+	Nullable             bool          `yaml:"nullable"`
+	Enum                 []interface{} `yaml:"enum"`
+	Properties           Properties    `yaml:"properties"`
+	Required             []string      `yaml:"required"`
+	Items                *Schema       `yaml:"items"`
+	AdditionalProperties *Schema       `yaml:"additionalProperties"`
+	AllOf                []*Schema     `yaml:"allOf"`
+	OneOf                []*Schema     `yaml:"oneOf"`
+	AnyOf                []*Schema     `yaml:"anyOf"`
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler. In addition to schema objects it
+// accepts the boolean schemas of JSON Schema, which are treated as "any value".
+func (s *Schema) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode && value.Tag == "!!bool" {
+		*s = Schema{}
+		return nil
+	}
+	type plain Schema
+	return value.Decode((*plain)(s))
+}
+
+// SchemaType holds the type of a schema, which is a single string in OpenAPI 3.0
+// and may be a list of strings in OpenAPI 3.1.
+type SchemaType []string
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (t *SchemaType) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*t = SchemaType{value.Value}
+		return nil
+	}
+	var list []string
+	if err := value.Decode(&list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+// Name returns the first type that is not "null".
+func (t SchemaType) Name() string {
+	for _, name := range t {
+		if name != "null" {
+			return name
+		}
+	}
+	return ""
+}
+
+// IsNullable reports whether "null" is one of the types.
+func (t SchemaType) IsNullable() bool {
+	for _, name := range t {
+		if name == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// Property is a named property of an object schema.
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Properties holds the properties of an object schema in the order of the spec.
+type Properties []Property
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (p *Properties) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: properties must be a mapping", value.Line)
+	}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		var s Schema
+		if err := value.Content[i+1].Decode(&s); err != nil {
+			return err
+		}
+		*p = append(*p, Property{Name: value.Content[i].Value, Schema: &s})
+	}
+	return nil
+}
+
+// Load parses an OpenAPI 3 spec in YAML or JSON format.
+func Load(data []byte) (*Spec, error) {
+	var s Spec
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing spec: %w", err)
+	}
+	if len(s.Paths) == 0 && len(s.Components.Schemas) == 0 {
+		return nil, errors.New("spec contains neither paths nor schemas")
+	}
+	return &s, nil
+}
+
+// refName returns the last path element of a local reference.
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+func (s *Spec) parameter(p *Parameter) (*Parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	resolved, ok := s.Components.Parameters[refName(p.Ref)]
+	if !ok || !strings.HasPrefix(p.Ref, "#/components/parameters/") {
+		return nil, fmt.Errorf("unresolvable parameter reference %q", p.Ref)
+	}
+	return resolved, nil
+}
+
+func (s *Spec) requestBody(b *RequestBody) (*RequestBody, error) {
+	if b == nil || b.Ref == "" {
+		return b, nil
+	}
+	resolved, ok := s.Components.RequestBodies[refName(b.Ref)]
+	if !ok || !strings.HasPrefix(b.Ref, "#/components/requestBodies/") {
+		return nil, fmt.Errorf("unresolvable request body reference %q", b.Ref)
+	}
+	return resolved, nil
+}
+
+// operation is an operation together with its location and resolved parameters.
+type operation struct {
+	*Operation
+	Name       string
+	Method     string
+	Path       string
+	Parameters []*Parameter
+	Body       *RequestBody
+}
+
+// operations returns all operations of the spec ordered by path and method.
+func (s *Spec) operations() ([]operation, error) {
+	paths := make([]string, 0, len(s.Paths))
+	for p := range s.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var ops []operation
+	names := make(map[string]string)
+	for _, path := range paths {
+		item := s.Paths[path]
+		if item == nil {
+			continue
+		}
+		for _, method := range methods {
+			op := item.operation(method)
+			if op == nil {
+				continue
+			}
+			o := operation{
+				Operation: op,
+				Method:    strings.ToUpper(method),
+				Path:      path,
+			}
+			if op.OperationID != "" {
+				o.Name = exportedName(op.OperationID)
+			} else {
+				o.Name = exportedName(method + " " + path)
+			}
+			if prev, dup := names[o.Name]; dup {
+				return nil, fmt.Errorf("%s %s: operation name %s already used by %s", o.Method, path, o.Name, prev)
+			}
+			names[o.Name] = o.Method + " " + path
+
+			params, err := s.mergeParameters(item.Parameters, op.Parameters)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", o.Method, path, err)
+			}
+			o.Parameters = params
+			if o.Body, err = s.requestBody(op.RequestBody); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", o.Method, path, err)
+			}
+			ops = append(ops, o)
+		}
+	}
+	return ops, nil
+}
+
+// mergeParameters resolves the path level and operation level parameters,
+// where operation level parameters override path level ones.
+func (s *Spec) mergeParameters(shared, own []*Parameter) ([]*Parameter, error) {
+	var result []*Parameter
+	index := make(map[string]int)
+	for _, list := range [][]*Parameter{shared, own} {
+		for _, raw := range list {
+			p, err := s.parameter(raw)
+			if err != nil {
+				return nil, err
+			}
+			key := p.In + ":" + p.Name
+			if i, exists := index[key]; exists {
+				result[i] = p
+				continue
+			}
+			index[key] = len(result)
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,370 @@
+/*
+ *  types.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package codegen
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// typeGen translates schemas into Go type declarations.
+type typeGen struct {
+	spec    *Spec
+	decls   []string
+	names   map[string]bool
+	imports map[string]bool
+}
+
+func newTypeGen(spec *Spec) *typeGen {
+	return &typeGen{
+		spec:    spec,
+		names:   make(map[string]bool),
+		imports: make(map[string]bool),
+	}
+}
+
+// reserve marks the names of all component schemas as taken, so that inline
+// types never collide with them regardless of the order of generation.
+func (g *typeGen) reserve() {
+	for name := range g.spec.Components.Schemas {
+		g.names[exportedName(name)] = true
+	}
+}
+
+// components declares a type for every component schema, ordered by name.
+func (g *typeGen) components() error {
+	names := make([]string, 0, len(g.spec.Components.Schemas))
+	for name := range g.spec.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := g.declare(exportedName(name), g.spec.Components.Schemas[name]); err != nil {
+			return fmt.Errorf("schema %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// uniqueName returns hint or, if hint is already taken, hint with a numeric suffix.
+func (g *typeGen) uniqueName(hint string) string {
+	name := hint
+	for i := 2; g.names[name]; i++ {
+		name = hint + strconv.Itoa(i)
+	}
+	g.names[name] = true
+	return name
+}
+
+// isNullable reports whether s accepts null, either by the OpenAPI 3.0
+// nullable keyword or by an OpenAPI 3.1 type list containing "null".
+func isNullable(s *Schema) bool {
+	return s != nil && (s.Nullable || s.Type.IsNullable())
+}
+
+// needsDecl reports whether s has to be represented by a named type.
+func needsDecl(s *Schema) bool {
+	switch {
+	case len(s.Enum) > 0, len(s.AllOf) > 0, len(s.OneOf) > 0, len(s.AnyOf) > 0:
+		return true
+	case len(s.Properties) > 0:
+		return true
+	}
+	return false
+}
+
+// goType returns the Go type expression for s. Inline schemas that need a
+// named type are declared using hint as the name.
+func (g *typeGen) goType(s *Schema, hint string) (string, error) {
+	if s == nil {
+		return "interface{}", nil
+	}
+	if s.Ref != "" {
+		if !strings.HasPrefix(s.Ref, "#/components/schemas/") {
+			return "", fmt.Errorf("unsupported schema reference %q", s.Ref)
+		}
+		if _, ok := g.spec.Components.Schemas[refName(s.Ref)]; !ok {
+			return "", fmt.Errorf("unresolvable schema reference %q", s.Ref)
+		}
+		return exportedName(refName(s.Ref)), nil
+	}
+	if needsDecl(s) {
+		name := g.uniqueName(hint)
+		return name, g.declare(name, s)
+	}
+	return g.plainType(s, hint)
+}
+
+// plainType returns the type expression of schemas that do not need a declaration.
+func (g *typeGen) plainType(s *Schema, hint string) (string, error) {
+	switch s.Type.Name() {
+	case "integer":
+		if s.Format == "int32" {
+			return "int32", nil
+		}
+		return "int64", nil
+	case "number":
+		if s.Format == "float" {
+			return "float32", nil
+		}
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "string":
+		switch s.Format {
+		case "date-time":
+			g.imports["time"] = true
+			return "time.Time", nil
+		case "byte":
+			// encoding/json represents []byte as base64, which is what the format demands.
+			return "[]byte", nil
+		}
+		return "string", nil
+	case "array":
+		elem, err := g.goType(s.Items, hint+"Item")
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	case "object", "":
+		if s.AdditionalProperties != nil {
+			elem, err := g.goType(s.AdditionalProperties, hint+"Value")
+			if err != nil {
+				return "", err
+			}
+			return "map[string]" + elem, nil
+		}
+		if s.Type.Name() == "object" {
+			return "map[string]interface{}", nil
+		}
+		return "interface{}", nil
+	}
+	return "", fmt.Errorf("unsupported type %q", s.Type.Name())
+}
+
+// declare emits a named type for s.
+func (g *typeGen) declare(name string, s *Schema) error {
+	g.names[name] = true
+	var b strings.Builder
+	if doc := comment(s.Description); doc != "" {
+		b.WriteString(doc + "\n")
+	} else {
+		fmt.Fprintf(&b, "// %s is generated from the spec.\n", name)
+	}
+
+	switch {
+	case s.Ref != "":
+		target, err := g.goType(s, name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "type %s = %s\n", name, target)
+	case len(s.Enum) > 0:
+		if err := g.enum(&b, name, s); err != nil {
+			return err
+		}
+	case len(s.OneOf) > 0 || len(s.AnyOf) > 0:
+		variants := s.OneOf
+		if len(variants) == 0 {
+			variants = s.AnyOf
+		}
+		if err := g.union(&b, name, variants); err != nil {
+			return err
+		}
+	case len(s.AllOf) > 0 || len(s.Properties) > 0:
+		if err := g.object(&b, name, s); err != nil {
+			return err
+		}
+	default:
+		target, err := g.plainType(s, name)
+		if err != nil {
+			return err
+		}
+		if target == "time.Time" {
+			// A defined type would drop the JSON methods of time.Time.
+			fmt.Fprintf(&b, "type %s = %s\n", name, target)
+		} else {
+			fmt.Fprintf(&b, "type %s %s\n", name, target)
+		}
+	}
+	g.decls = append(g.decls, b.String())
+	return nil
+}
+
+func (g *typeGen) enum(b *strings.Builder, name string, s *Schema) error {
+	base := "string"
+	switch s.Type.Name() {
+	case "integer":
+		base = "int64"
+	case "number":
+		base = "float64"
+	case "string", "":
+	default:
+		return fmt.Errorf("unsupported enum type %q", s.Type.Name())
+	}
+	fmt.Fprintf(b, "type %s %s\n\n", name, base)
+	fmt.Fprintf(b, "// Values of %s.\nconst (\n", name)
+	for _, v := range s.Enum {
+		if v == nil {
+			// null is expressed by the nullability of the field using the enum.
+			continue
+		}
+		suffix, literal := exportedName(fmt.Sprint(v)), strconv.Quote(fmt.Sprint(v))
+		if base != "string" {
+			literal = fmt.Sprint(v)
+			suffix = strings.NewReplacer("-", "Minus", ".", "_").Replace(literal)
+		}
+		constName := g.uniqueName(name + suffix)
+		fmt.Fprintf(b, "\t%s %s = %s\n", constName, name, literal)
+	}
+	b.WriteString(")\n")
+	return nil
+}
+
+func (g *typeGen) union(b *strings.Builder, name string, variants []*Schema) error {
+	g.imports["encoding/json"] = true
+	types := make([]string, len(variants))
+	for i, v := range variants {
+		t, err := g.goType(v, fmt.Sprintf("%sVariant%d", name, i+1))
+		if err != nil {
+			return err
+		}
+		types[i] = t
+	}
+	fmt.Fprintf(b, "//\n// It holds one of: %s.\n", strings.Join(types, ", "))
+	fmt.Fprintf(b, "type %s struct {\n\traw json.RawMessage\n}\n\n", name)
+	fmt.Fprintf(b, `// MarshalJSON implements json.Marshaler.
+func (u %[1]s) MarshalJSON() ([]byte, error) {
+	if u.raw == nil {
+		return []byte("null"), nil
+	}
+	return u.raw, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (u *%[1]s) UnmarshalJSON(data []byte) error {
+	u.raw = append(u.raw[:0], data...)
+	return nil
+}
+`, name)
+	for i, t := range types {
+		method := t
+		if !token.IsIdentifier(t) {
+			method = fmt.Sprintf("Variant%d", i+1)
+		}
+		method = exportedName(method)
+		fmt.Fprintf(b, `
+// As%[2]s decodes the value as %[3]s.
+func (u %[1]s) As%[2]s() (%[3]s, error) {
+	var v %[3]s
+	err := json.Unmarshal(u.raw, &v)
+	return v, err
+}
+
+// From%[2]s sets the value to v.
+func (u *%[1]s) From%[2]s(v %[3]s) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	u.raw = raw
+	return nil
+}
+`, name, method, t)
+	}
+	return nil
+}
+
+func (g *typeGen) object(b *strings.Builder, name string, s *Schema) error {
+	fmt.Fprintf(b, "type %s struct {\n", name)
+	fields := make(map[string]bool)
+
+	parts := append([]*Schema{}, s.AllOf...)
+	if len(s.Properties) > 0 {
+		parts = append(parts, &Schema{Properties: s.Properties, Required: s.Required})
+	}
+	var required []string
+	for _, part := range parts {
+		required = append(required, part.Required...)
+	}
+	for _, part := range parts {
+		if part.Ref != "" {
+			// Referenced parts are embedded, so that their fields are promoted
+			// and encoding/json flattens them into the object.
+			t, err := g.goType(part, name)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(b, "\t%s\n", t)
+			fields[t] = true
+			continue
+		}
+		if err := g.fields(b, name, part, required, fields); err != nil {
+			return err
+		}
+	}
+	b.WriteString("}\n")
+	return nil
+}
+
+func (g *typeGen) fields(b *strings.Builder, name string, s *Schema, required []string, fields map[string]bool) error {
+	for _, p := range s.Properties {
+		field := exportedName(p.Name)
+		for fields[field] {
+			field += "_"
+		}
+		fields[field] = true
+
+		t, err := g.goType(p.Schema, name+field)
+		if err != nil {
+			return fmt.Errorf("property %q: %w", p.Name, err)
+		}
+		isRequired := contains(required, p.Name)
+		ptr := (!isRequired || isNullable(p.Schema)) && !isReferenceType(t)
+		if ptr {
+			t = "*" + t
+		}
+		tag := p.Name
+		if !isRequired {
+			tag += ",omitempty"
+		}
+		if doc := comment(p.Schema.Description); doc != "" {
+			b.WriteString("\t" + strings.ReplaceAll(doc, "\n", "\n\t") + "\n")
+		}
+		fmt.Fprintf(b, "\t%s %s `json:%s`\n", field, t, strconv.Quote(tag))
+	}
+	return nil
+}
+
+// isReferenceType reports whether the zero value of t already represents absence.
+func isReferenceType(t string) bool {
+	return strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") || t == "interface{}"
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
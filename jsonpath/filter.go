@@ -0,0 +1,257 @@
+/*
+ *  filter.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package jsonpath
+
+import (
+	"reflect"
+	"regexp"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// logical is a filter expression evaluating to true or false.
+type logical interface {
+	test(current, root *yaml.Node) bool
+}
+
+type orExpr []logical
+
+func (e orExpr) test(current, root *yaml.Node) bool {
+	for _, x := range e {
+		if x.test(current, root) {
+			return true
+		}
+	}
+	return false
+}
+
+type andExpr []logical
+
+func (e andExpr) test(current, root *yaml.Node) bool {
+	for _, x := range e {
+		if !x.test(current, root) {
+			return false
+		}
+	}
+	return true
+}
+
+type notExpr struct {
+	expr logical
+}
+
+func (e notExpr) test(current, root *yaml.Node) bool {
+	return !e.expr.test(current, root)
+}
+
+// existsExpr tests whether a query selects any node.
+type existsExpr struct {
+	query *query
+}
+
+func (e existsExpr) test(current, root *yaml.Node) bool {
+	return len(e.query.nodes(current, root)) > 0
+}
+
+// funcTest tests the result of a function.
+type funcTest struct {
+	call *funcCall
+}
+
+func (e funcTest) test(current, root *yaml.Node) bool {
+	v, ok := e.call.value(current, root)
+	b, isBool := v.(bool)
+	return ok && isBool && b
+}
+
+type compareExpr struct {
+	op          string
+	left, right operand
+}
+
+func (e compareExpr) test(current, root *yaml.Node) bool {
+	l, lok := e.left.value(current, root)
+	r, rok := e.right.value(current, root)
+	switch e.op {
+	case "==":
+		return equal(l, lok, r, rok)
+	case "!=":
+		return !equal(l, lok, r, rok)
+	case "<":
+		return less(l, lok, r, rok)
+	case ">":
+		return less(r, rok, l, lok)
+	case "<=":
+		return less(l, lok, r, rok) || equal(l, lok, r, rok)
+	case ">=":
+		return less(r, rok, l, lok) || equal(l, lok, r, rok)
+	}
+	return false
+}
+
+func equal(l interface{}, lok bool, r interface{}, rok bool) bool {
+	if !lok || !rok {
+		return lok == rok
+	}
+	return reflect.DeepEqual(normalize(l), normalize(r))
+}
+
+func less(l interface{}, lok bool, r interface{}, rok bool) bool {
+	if !lok || !rok {
+		return false
+	}
+	switch lv := normalize(l).(type) {
+	case float64:
+		rv, ok := normalize(r).(float64)
+		return ok && lv < rv
+	case string:
+		rv, ok := r.(string)
+		return ok && lv < rv
+	}
+	return false
+}
+
+// normalize converts all numbers to float64, so that 1 equals 1.0.
+func normalize(v interface{}) interface{} {
+	switch x := v.(type) {
+	case int:
+		return float64(x)
+	case int64:
+		return float64(x)
+	case uint64:
+		return float64(x)
+	case []interface{}:
+		out := make([]interface{}, len(x))
+		for i, item := range x {
+			out[i] = normalize(item)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(x))
+		for k, item := range x {
+			out[k] = normalize(item)
+		}
+		return out
+	}
+	return v
+}
+
+// operand is a value within a comparison. Its value may be absent.
+type operand interface {
+	value(current, root *yaml.Node) (interface{}, bool)
+}
+
+type literal struct {
+	v interface{}
+}
+
+func (l literal) value(_, _ *yaml.Node) (interface{}, bool) {
+	return l.v, true
+}
+
+// query is an embedded query relative to the current node (@) or the root ($).
+type query struct {
+	relative bool
+	path     *Path
+}
+
+func (q *query) nodes(current, root *yaml.Node) []Match {
+	if q.relative {
+		return q.path.selectFrom(current, root)
+	}
+	return q.path.selectFrom(root, root)
+}
+
+func (q *query) value(current, root *yaml.Node) (interface{}, bool) {
+	nodes := q.nodes(current, root)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+	return decode(nodes[0].Node)
+}
+
+func decode(n *yaml.Node) (interface{}, bool) {
+	var v interface{}
+	if err := n.Decode(&v); err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// funcCall calls one of the functions of RFC 9535.
+type funcCall struct {
+	name string
+	args []operand
+}
+
+// functions maps the supported functions to their number of arguments.
+var functions = map[string]int{
+	"length": 1,
+	"count":  1,
+	"match":  2,
+	"search": 2,
+	"value":  1,
+}
+
+func (f *funcCall) value(current, root *yaml.Node) (interface{}, bool) {
+	switch f.name {
+	case "length":
+		v, ok := f.args[0].value(current, root)
+		if !ok {
+			return nil, false
+		}
+		switch x := v.(type) {
+		case string:
+			return utf8.RuneCountInString(x), true
+		case []interface{}:
+			return len(x), true
+		case map[string]interface{}:
+			return len(x), true
+		}
+		return nil, false
+	case "count":
+		if q, ok := f.args[0].(*query); ok {
+			return len(q.nodes(current, root)), true
+		}
+		return nil, false
+	case "value":
+		if q, ok := f.args[0].(*query); ok {
+			return q.value(current, root)
+		}
+		return f.args[0].value(current, root)
+	case "match", "search":
+		s, sok := f.args[0].value(current, root)
+		pattern, pok := f.args[1].value(current, root)
+		str, isStr := s.(string)
+		expr, isExpr := pattern.(string)
+		if !sok || !pok || !isStr || !isExpr {
+			return false, true
+		}
+		if f.name == "match" {
+			expr = "^(?:" + expr + ")$"
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return false, true
+		}
+		return re.MatchString(str), true
+	}
+	return nil, false
+}
@@ -0,0 +1,275 @@
+/*
+ *  jsonpath.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// Package jsonpath evaluates JSONPath expressions as defined by RFC 9535 on
+// YAML and JSON documents parsed into yaml.Node trees.
+//
+// Supported are name, wildcard, index, slice and filter selectors, the
+// descendant segment and the functions length, count, match, search and value.
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Path is a compiled JSONPath expression.
+type Path struct {
+	expr     string
+	segments []segment
+}
+
+// Match is a node selected by a path, along with its location.
+type Match struct {
+	Node *yaml.Node
+	// Parent is the mapping or sequence holding Node, or nil for the root.
+	Parent *yaml.Node
+	// Key is the mapping key of Node, if Parent is a mapping.
+	Key string
+	// Index is the position of Node, if Parent is a sequence, and -1 otherwise.
+	Index int
+	// Location is the normalized path of Node, as in "$['paths']['/pets']".
+	Location string
+}
+
+// MustParse is like Parse but panics if the expression cannot be parsed.
+func MustParse(expr string) *Path {
+	p, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Parse compiles a JSONPath expression, such as "$.paths[*][?@.deprecated == true]".
+func Parse(expr string) (*Path, error) {
+	p := &parser{s: expr}
+	path, err := p.query('$')
+	if err == nil && p.pos < len(p.s) {
+		err = p.errorf("unexpected %q", p.s[p.pos:])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jsonpath: %s: %w", expr, err)
+	}
+	return path, nil
+}
+
+// String returns the expression the path was parsed from.
+func (p *Path) String() string {
+	return p.expr
+}
+
+// Select returns the nodes selected within root, in document order.
+// Documents are unwrapped to their content, and aliases are followed.
+func (p *Path) Select(root *yaml.Node) []Match {
+	root = resolve(root)
+	return p.selectFrom(root, root)
+}
+
+func (p *Path) selectFrom(start, root *yaml.Node) []Match {
+	matches := []Match{{Node: start, Index: -1, Location: "$"}}
+	for _, seg := range p.segments {
+		var next []Match
+		for _, m := range matches {
+			if seg.descendant {
+				descend(m, func(d Match) {
+					next = append(next, seg.apply(d, root)...)
+				})
+			} else {
+				next = append(next, seg.apply(m, root)...)
+			}
+		}
+		matches = next
+	}
+	return matches
+}
+
+// resolve unwraps documents and follows aliases.
+func resolve(n *yaml.Node) *yaml.Node {
+	for n != nil {
+		switch {
+		case n.Kind == yaml.DocumentNode && len(n.Content) > 0:
+			n = n.Content[0]
+		case n.Kind == yaml.AliasNode:
+			n = n.Alias
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+// descend calls f for m and all of its descendants in document order.
+func descend(m Match, f func(Match)) {
+	f(m)
+	children(m, func(c Match) {
+		descend(c, f)
+	})
+}
+
+// children calls f for each child of m.
+func children(m Match, f func(Match)) {
+	switch m.Node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(m.Node.Content); i += 2 {
+			f(child(m, m.Node.Content[i].Value, -1, m.Node.Content[i+1]))
+		}
+	case yaml.SequenceNode:
+		for i, item := range m.Node.Content {
+			f(child(m, "", i, item))
+		}
+	}
+}
+
+func child(parent Match, key string, index int, n *yaml.Node) Match {
+	loc := parent.Location
+	if index >= 0 {
+		loc += "[" + strconv.Itoa(index) + "]"
+	} else {
+		loc += "[" + quote(key) + "]"
+	}
+	return Match{Node: resolve(n), Parent: parent.Node, Key: key, Index: index, Location: loc}
+}
+
+// quote renders name as string literal of a normalized path.
+func quote(name string) string {
+	var b strings.Builder
+	b.WriteByte('\'')
+	for _, r := range name {
+		switch {
+		case r == '\'' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20:
+			fmt.Fprintf(&b, "\\u%04x", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+type segment struct {
+	descendant bool
+	selectors  []selector
+}
+
+func (s segment) apply(m Match, root *yaml.Node) []Match {
+	var out []Match
+	for _, sel := range s.selectors {
+		out = append(out, sel.apply(m, root)...)
+	}
+	return out
+}
+
+type selector interface {
+	apply(m Match, root *yaml.Node) []Match
+}
+
+type nameSelector string
+
+func (s nameSelector) apply(m Match, _ *yaml.Node) []Match {
+	if m.Node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Node.Content); i += 2 {
+		if m.Node.Content[i].Value == string(s) {
+			return []Match{child(m, string(s), -1, m.Node.Content[i+1])}
+		}
+	}
+	return nil
+}
+
+type wildcardSelector struct{}
+
+func (wildcardSelector) apply(m Match, _ *yaml.Node) []Match {
+	var out []Match
+	children(m, func(c Match) { out = append(out, c) })
+	return out
+}
+
+type indexSelector int
+
+func (s indexSelector) apply(m Match, _ *yaml.Node) []Match {
+	if m.Node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	i := int(s)
+	if i < 0 {
+		i += len(m.Node.Content)
+	}
+	if i < 0 || i >= len(m.Node.Content) {
+		return nil
+	}
+	return []Match{child(m, "", i, m.Node.Content[i])}
+}
+
+type sliceSelector struct {
+	start, end *int
+	step       int
+}
+
+func (s sliceSelector) apply(m Match, _ *yaml.Node) []Match {
+	if m.Node.Kind != yaml.SequenceNode || s.step == 0 {
+		return nil
+	}
+	n := len(m.Node.Content)
+	normalize := func(i *int, def int) int {
+		if i == nil {
+			return def
+		}
+		if *i < 0 {
+			return *i + n
+		}
+		return *i
+	}
+	clamp := func(i, lo, hi int) int {
+		return min(max(i, lo), hi)
+	}
+	var out []Match
+	if s.step > 0 {
+		lower, upper := clamp(normalize(s.start, 0), 0, n), clamp(normalize(s.end, n), 0, n)
+		for i := lower; i < upper; i += s.step {
+			out = append(out, child(m, "", i, m.Node.Content[i]))
+		}
+	} else {
+		upper, lower := clamp(normalize(s.start, n-1), -1, n-1), clamp(normalize(s.end, -n-1), -1, n-1)
+		for i := upper; i > lower; i += s.step {
+			out = append(out, child(m, "", i, m.Node.Content[i]))
+		}
+	}
+	return out
+}
+
+type filterSelector struct {
+	expr logical
+}
+
+func (s filterSelector) apply(m Match, root *yaml.Node) []Match {
+	var out []Match
+	children(m, func(c Match) {
+		if s.expr.test(c.Node, root) {
+			out = append(out, c)
+		}
+	})
+	return out
+}
@@ -0,0 +1,137 @@
+/*
+ *  jsonpath_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package jsonpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+const store = `
+store:
+  book:
+    - category: reference
+      author: Nigel Rees
+      title: Sayings of the Century
+      price: 8.95
+    - category: fiction
+      author: Evelyn Waugh
+      title: Sword of Honour
+      price: 12.99
+    - category: fiction
+      author: Herman Melville
+      title: Moby Dick
+      isbn: 0-553-21311-3
+      price: 8.99
+    - category: fiction
+      author: J. R. R. Tolkien
+      title: The Lord of the Rings
+      isbn: 0-395-19395-8
+      price: 22.99
+  bicycle:
+    color: red
+    price: 399
+paths:
+  /pets/{id}:
+    get:
+      operationId: getPet
+`
+
+type JSONPathSuite struct {
+	suite.Suite
+	root yaml.Node
+}
+
+func (suite *JSONPathSuite) SetupSuite() {
+	require.NoError(suite.T(), yaml.Unmarshal([]byte(store), &suite.root))
+}
+
+func (suite *JSONPathSuite) values(expr string) []string {
+	p, err := Parse(expr)
+	require.NoError(suite.T(), err)
+	var values []string
+	for _, m := range p.Select(&suite.root) {
+		values = append(values, m.Node.Value)
+	}
+	return values
+}
+
+func (suite *JSONPathSuite) TestSelectors() {
+	testCases := []struct {
+		expr     string
+		expected []string
+	}{
+		{"$.store.book[*].author", []string{"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"}},
+		{"$..author", []string{"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"}},
+		{"$.store.*.color", []string{"red"}},
+		{"$['store']['bicycle'][\"color\"]", []string{"red"}},
+		{"$..book[2].title", []string{"Moby Dick"}},
+		{"$..book[-1].title", []string{"The Lord of the Rings"}},
+		{"$..book[0,1].title", []string{"Sayings of the Century", "Sword of Honour"}},
+		{"$..book[:2].title", []string{"Sayings of the Century", "Sword of Honour"}},
+		{"$..book[::-2].title", []string{"The Lord of the Rings", "Sword of Honour"}},
+		{"$..book[?@.isbn].title", []string{"Moby Dick", "The Lord of the Rings"}},
+		{"$..book[?(@.price < 10)].title", []string{"Sayings of the Century", "Moby Dick"}},
+		{"$..book[?@.price > 10 && @.category == 'fiction'].title", []string{"Sword of Honour", "The Lord of the Rings"}},
+		{"$..book[?!@.isbn || @.price >= 22.99].title", []string{"Sayings of the Century", "Sword of Honour", "The Lord of the Rings"}},
+		{"$..book[?@.price < $.store.bicycle.price && @.price > 12].title", []string{"Sword of Honour", "The Lord of the Rings"}},
+		{"$..book[?match(@.author, 'H.*')].title", []string{"Moby Dick"}},
+		{"$..book[?search(@.title, 'of')].title", []string{"Sayings of the Century", "Sword of Honour", "The Lord of the Rings"}},
+		{"$..book[?length(@.title) == 9].title", []string{"Moby Dick"}},
+		{"$.store[?count(@.*) == 2].color", []string{"red"}},
+		{"$.paths['/pets/{id}'].get.operationId", []string{"getPet"}},
+		{"$.paths[*][?@.operationId == 'getPet'].operationId", []string{"getPet"}},
+		{"$.nothing", nil},
+	}
+	for _, tC := range testCases {
+		suite.T().Run(tC.expr, func(t *testing.T) {
+			assert.Equal(t, tC.expected, suite.values(tC.expr))
+		})
+	}
+}
+
+func (suite *JSONPathSuite) TestMatchLocation() {
+	matches := MustParse("$.store.book[1].title").Select(&suite.root)
+	require.Len(suite.T(), matches, 1)
+	m := matches[0]
+	assert.Equal(suite.T(), "$['store']['book'][1]['title']", m.Location)
+	assert.Equal(suite.T(), "title", m.Key)
+	assert.Equal(suite.T(), -1, m.Index)
+	assert.Equal(suite.T(), yaml.MappingNode, m.Parent.Kind)
+
+	matches = MustParse("$.store.book[1]").Select(&suite.root)
+	require.Len(suite.T(), matches, 1)
+	assert.Equal(suite.T(), 1, matches[0].Index)
+	assert.Equal(suite.T(), yaml.SequenceNode, matches[0].Parent.Kind)
+}
+
+func (suite *JSONPathSuite) TestInvalid() {
+	for _, expr := range []string{"", "store", "$.", "$[", "$['a'", "$[?@.a ==]", "$[?foo(@)]", "$.a)"} {
+		_, err := Parse(expr)
+		assert.Error(suite.T(), err, expr)
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	suite.Run(t, new(JSONPathSuite))
+}
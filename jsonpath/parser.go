@@ -0,0 +1,405 @@
+/*
+ *  parser.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package jsonpath
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// parser is a recursive descent parser for JSONPath expressions.
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) peek() byte {
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+func (p *parser) consume(prefix string) bool {
+	if strings.HasPrefix(p.s[p.pos:], prefix) {
+		p.pos += len(prefix)
+		return true
+	}
+	return false
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\n\r", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// query parses a query starting with the identifier ident, which is $ or @.
+func (p *parser) query(ident byte) (*Path, error) {
+	start := p.pos
+	if p.peek() != ident {
+		return nil, p.errorf("expected %q", ident)
+	}
+	p.pos++
+	path := &Path{}
+	for {
+		save := p.pos
+		p.skipSpace()
+		var seg segment
+		var err error
+		switch {
+		case p.consume(".."):
+			seg.descendant = true
+			if p.peek() == '[' {
+				seg.selectors, err = p.bracketed()
+			} else {
+				seg.selectors, err = p.shorthand()
+			}
+		case p.consume("."):
+			seg.selectors, err = p.shorthand()
+		case p.peek() == '[':
+			seg.selectors, err = p.bracketed()
+		default:
+			p.pos = save
+			path.expr = p.s[start:p.pos]
+			return path, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		path.segments = append(path.segments, seg)
+	}
+}
+
+// shorthand parses the wildcard or member name following a dot.
+func (p *parser) shorthand() ([]selector, error) {
+	if p.consume("*") {
+		return []selector{wildcardSelector{}}, nil
+	}
+	start := p.pos
+	for p.pos < len(p.s) {
+		r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+		if !(r == '_' || unicode.IsLetter(r) || r >= 0x80 || (p.pos > start && unicode.IsDigit(r))) {
+			break
+		}
+		p.pos += size
+	}
+	if p.pos == start {
+		return nil, p.errorf("expected member name")
+	}
+	return []selector{nameSelector(p.s[start:p.pos])}, nil
+}
+
+// bracketed parses a comma separated list of selectors in brackets.
+func (p *parser) bracketed() ([]selector, error) {
+	p.pos++ // [
+	var selectors []selector
+	for {
+		p.skipSpace()
+		sel, err := p.selector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, sel)
+		p.skipSpace()
+		if p.consume("]") {
+			return selectors, nil
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("expected ',' or ']'")
+		}
+	}
+}
+
+func (p *parser) selector() (selector, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		s, err := p.stringLiteral()
+		return nameSelector(s), err
+	case c == '*':
+		p.pos++
+		return wildcardSelector{}, nil
+	case c == '?':
+		p.pos++
+		p.skipSpace()
+		expr, err := p.logicalOr()
+		return filterSelector{expr: expr}, err
+	default:
+		return p.indexOrSlice()
+	}
+}
+
+func (p *parser) indexOrSlice() (selector, error) {
+	var bounds [3]*int
+	colons := 0
+	for {
+		p.skipSpace()
+		if c := p.peek(); c == '-' || (c >= '0' && c <= '9') {
+			i, err := p.integer()
+			if err != nil {
+				return nil, err
+			}
+			bounds[colons] = &i
+			p.skipSpace()
+		}
+		if colons < 2 && p.consume(":") {
+			colons++
+			continue
+		}
+		break
+	}
+	if colons == 0 {
+		if bounds[0] == nil {
+			return nil, p.errorf("expected selector")
+		}
+		return indexSelector(*bounds[0]), nil
+	}
+	step := 1
+	if bounds[2] != nil {
+		step = *bounds[2]
+	}
+	return sliceSelector{start: bounds[0], end: bounds[1], step: step}, nil
+}
+
+func (p *parser) integer() (int, error) {
+	start := p.pos
+	p.consume("-")
+	for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+	i, err := strconv.Atoi(p.s[start:p.pos])
+	if err != nil {
+		return 0, p.errorf("invalid integer %q", p.s[start:p.pos])
+	}
+	return i, nil
+}
+
+// stringLiteral parses a single or double quoted string.
+func (p *parser) stringLiteral() (string, error) {
+	quote := p.s[p.pos]
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return b.String(), nil
+		case c == '\\':
+			p.pos++
+			if p.pos >= len(p.s) {
+				return "", p.errorf("unterminated escape")
+			}
+			e := p.s[p.pos]
+			p.pos++
+			switch e {
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if p.pos+4 > len(p.s) {
+					return "", p.errorf("invalid unicode escape")
+				}
+				r, err := strconv.ParseUint(p.s[p.pos:p.pos+4], 16, 32)
+				if err != nil {
+					return "", p.errorf("invalid unicode escape")
+				}
+				p.pos += 4
+				b.WriteRune(rune(r))
+			default:
+				b.WriteByte(e)
+			}
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *parser) logicalOr() (logical, error) {
+	var or orExpr
+	for {
+		and, err := p.logicalAnd()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, and)
+		p.skipSpace()
+		if !p.consume("||") {
+			break
+		}
+		p.skipSpace()
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return or, nil
+}
+
+func (p *parser) logicalAnd() (logical, error) {
+	var and andExpr
+	for {
+		basic, err := p.basic()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, basic)
+		p.skipSpace()
+		if !p.consume("&&") {
+			break
+		}
+		p.skipSpace()
+	}
+	if len(and) == 1 {
+		return and[0], nil
+	}
+	return and, nil
+}
+
+// basic parses a negation, a parenthesized expression, a comparison or a test.
+func (p *parser) basic() (logical, error) {
+	if p.consume("!") {
+		p.skipSpace()
+		expr, err := p.basic()
+		return notExpr{expr: expr}, err
+	}
+	if p.consume("(") {
+		p.skipSpace()
+		expr, err := p.logicalOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if !p.consume(")") {
+			return nil, p.errorf("expected ')'")
+		}
+		return expr, nil
+	}
+
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			p.skipSpace()
+			right, err := p.operand()
+			if err != nil {
+				return nil, err
+			}
+			return compareExpr{op: op, left: left, right: right}, nil
+		}
+	}
+	switch x := left.(type) {
+	case *query:
+		return existsExpr{query: x}, nil
+	case *funcCall:
+		return funcTest{call: x}, nil
+	}
+	return nil, p.errorf("expected comparison")
+}
+
+// operand parses a literal, an embedded query or a function call.
+func (p *parser) operand() (operand, error) {
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		path, err := p.query(c)
+		return &query{relative: c == '@', path: path}, err
+	case c == '\'' || c == '"':
+		s, err := p.stringLiteral()
+		return literal{v: s}, err
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.number()
+	}
+	for _, kw := range []struct {
+		word  string
+		value interface{}
+	}{{"true", true}, {"false", false}, {"null", nil}} {
+		if p.consume(kw.word) {
+			return literal{v: kw.value}, nil
+		}
+	}
+	return p.function()
+}
+
+func (p *parser) number() (operand, error) {
+	start := p.pos
+	p.consume("-")
+	for p.pos < len(p.s) && strings.IndexByte("0123456789.eE+-", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+	f, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+	if err != nil {
+		return nil, p.errorf("invalid number %q", p.s[start:p.pos])
+	}
+	return literal{v: f}, nil
+}
+
+func (p *parser) function() (operand, error) {
+	start := p.pos
+	for p.pos < len(p.s) && (p.s[p.pos] == '_' || (p.s[p.pos] >= 'a' && p.s[p.pos] <= 'z') || (p.pos > start && p.s[p.pos] >= '0' && p.s[p.pos] <= '9')) {
+		p.pos++
+	}
+	name := p.s[start:p.pos]
+	if name == "" || !p.consume("(") {
+		p.pos = start
+		return nil, p.errorf("expected operand")
+	}
+	arity, ok := functions[name]
+	if !ok {
+		return nil, p.errorf("unknown function %q", name)
+	}
+	call := &funcCall{name: name}
+	p.skipSpace()
+	for !p.consume(")") {
+		if len(call.args) > 0 {
+			if !p.consume(",") {
+				return nil, p.errorf("expected ',' or ')'")
+			}
+			p.skipSpace()
+		}
+		arg, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+		p.skipSpace()
+	}
+	if len(call.args) != arity {
+		return nil, errors.New(name + " takes " + strconv.Itoa(arity) + " argument(s)")
+	}
+	return call, nil
+}
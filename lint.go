@@ -0,0 +1,62 @@
+/*
+ *  lint.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mwmahlberg/swagger-ui/lint"
+)
+
+// Lint checks the spec against the built-in rules of the lint package,
+// adjusted by c, which may be nil. Setup fails if any finding has the
+// severity lint.Error, while the other findings are reported by Warnings.
+// The spec is linted as served, that is after conversions and overlays.
+func Lint(c *lint.Config) HandlerOption {
+	return func(suh *SwaggerUi) {
+		if c == nil {
+			c = &lint.Config{}
+		}
+		suh.lintConfig = c
+	}
+}
+
+func (ui *SwaggerUi) lintSpec() error {
+	l, err := lint.New(ui.lintConfig)
+	if err != nil {
+		return err
+	}
+	findings, err := l.Lint(ui.specContent)
+	if err != nil {
+		return err
+	}
+	var errs []string
+	for _, f := range findings {
+		if f.Severity == lint.Error {
+			errs = append(errs, f.String())
+			continue
+		}
+		ui.warnings = append(ui.warnings, f.String())
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,319 @@
+/*
+ *  lint.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// Package lint checks OpenAPI specs against a configurable set of rules.
+//
+// The built-in rules check for operationIds, declared tags, descriptions,
+// unused components, kebab-case paths and examples that do not match their
+// schemas. Their severities can be changed, and custom rules can be added,
+// in a YAML configuration:
+//
+//	rules:
+//	  kebab-case-paths: error
+//	  info-description: off
+//	custom:
+//	  - name: contact-email
+//	    description: The API must name a contact email.
+//	    severity: error
+//	    given: $.info
+//	    field: contact
+//	    required: true
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/mwmahlberg/swagger-ui/jsonpath"
+	"github.com/mwmahlberg/swagger-ui/openapi"
+	"gopkg.in/yaml.v3"
+)
+
+// Severity is the importance of a finding.
+type Severity int
+
+const (
+	// Off disables a rule.
+	Off Severity = iota
+	Info
+	Warn
+	Error
+)
+
+var severityNames = []string{"off", "info", "warn", "error"}
+
+// String returns the name of s, as used in configurations.
+func (s Severity) String() string {
+	if s < Off || s > Error {
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+	return severityNames[s]
+}
+
+// ParseSeverity parses the name of a severity. "warning" is accepted for "warn".
+func ParseSeverity(name string) (Severity, error) {
+	if name == "warning" {
+		return Warn, nil
+	}
+	if i := slices.Index(severityNames, name); i >= 0 {
+		return Severity(i), nil
+	}
+	return Off, fmt.Errorf("unknown severity %q", name)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (s *Severity) UnmarshalYAML(value *yaml.Node) (err error) {
+	*s, err = ParseSeverity(value.Value)
+	return err
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Finding is a violation of a rule.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	// Path is the normalized JSONPath of the offending node, as in "$['paths']['/pets']['get']".
+	Path string `json:"path"`
+	// Line is the line of the offending node in the spec, if known.
+	Line int `json:"line,omitempty"`
+}
+
+// String formats f as in "12: error: operation-id: ... at $['paths']".
+func (f Finding) String() string {
+	var b strings.Builder
+	if f.Line > 0 {
+		fmt.Fprintf(&b, "%d: ", f.Line)
+	}
+	fmt.Fprintf(&b, "%s: %s: %s at %s", f.Severity, f.Rule, f.Message, f.Path)
+	return b.String()
+}
+
+// HasErrors reports whether any of findings has the severity Error.
+func HasErrors(findings []Finding) bool {
+	return slices.ContainsFunc(findings, func(f Finding) bool { return f.Severity == Error })
+}
+
+// Config configures a Linter.
+type Config struct {
+	// Rules overrides the severities of built-in rules by name.
+	Rules map[string]Severity `yaml:"rules"`
+	// Custom lists additional rules.
+	Custom []CustomRule `yaml:"custom"`
+}
+
+// CustomRule checks the nodes selected by a JSONPath expression.
+//
+// If Required, Pattern or NotPattern is set, each selected node, or its Field,
+// must satisfy them. Otherwise, each selected node having the Field, if set,
+// is a finding. This makes it easy to forbid constructs with filter selectors,
+// such as deprecated operations with "$.paths[*][?@.deprecated == true]".
+type CustomRule struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Severity    Severity `yaml:"severity"`
+	// Message is reported for findings. It defaults to the description.
+	Message string `yaml:"message"`
+	// Given is the JSONPath expression selecting the nodes to check.
+	Given string `yaml:"given"`
+	// Field is a key of the selected mappings to check instead of the mappings.
+	Field string `yaml:"field"`
+	// Required demands that the field is present.
+	Required bool `yaml:"required"`
+	// Pattern and NotPattern are regular expressions a scalar must or must not match.
+	Pattern    string `yaml:"pattern"`
+	NotPattern string `yaml:"notPattern"`
+}
+
+// ParseConfig parses a YAML configuration.
+func ParseConfig(data []byte) (*Config, error) {
+	var c Config
+	dec := yaml.NewDecoder(strings.NewReader(string(data)))
+	dec.KnownFields(true)
+	if err := dec.Decode(&c); err != nil {
+		return nil, fmt.Errorf("parsing lint config: %w", err)
+	}
+	return &c, nil
+}
+
+// rule is a compiled rule of a Linter.
+type rule struct {
+	name     string
+	severity Severity
+	check    func(*spec, func(m jsonpath.Match, msg string))
+}
+
+// Linter checks specs against a set of rules.
+type Linter struct {
+	rules []rule
+}
+
+// New returns a Linter with the built-in rules, adjusted by c, which may be nil.
+func New(c *Config) (*Linter, error) {
+	if c == nil {
+		c = &Config{}
+	}
+	l := &Linter{}
+	known := make(map[string]bool)
+	for _, r := range builtins {
+		known[r.Name] = true
+		severity := r.Severity
+		if s, ok := c.Rules[r.Name]; ok {
+			severity = s
+		}
+		if severity != Off {
+			l.rules = append(l.rules, rule{name: r.Name, severity: severity, check: r.check})
+		}
+	}
+	for name := range c.Rules {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown rule %q", name)
+		}
+	}
+	for i, cr := range c.Custom {
+		if cr.Name == "" {
+			return nil, fmt.Errorf("custom rule %d: name missing", i)
+		}
+		if known[cr.Name] {
+			return nil, fmt.Errorf("custom rule %s: name already used", cr.Name)
+		}
+		known[cr.Name] = true
+		check, err := cr.compile()
+		if err != nil {
+			return nil, fmt.Errorf("custom rule %s: %w", cr.Name, err)
+		}
+		severity := cr.Severity
+		if severity == Off {
+			severity = Warn
+		}
+		l.rules = append(l.rules, rule{name: cr.Name, severity: severity, check: check})
+	}
+	return l, nil
+}
+
+// Lint checks spec, which may be YAML or JSON, and returns the findings
+// ordered by line. It fails only if spec is not an OpenAPI document.
+func (l *Linter) Lint(spec []byte) ([]Finding, error) {
+	s, err := parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	for _, r := range l.rules {
+		r.check(s, func(m jsonpath.Match, msg string) {
+			findings = append(findings, Finding{
+				Rule: r.name, Severity: r.severity, Message: msg, Path: m.Location, Line: m.Node.Line})
+		})
+	}
+	slices.SortStableFunc(findings, func(a, b Finding) int { return a.Line - b.Line })
+	return findings, nil
+}
+
+// spec is a document being linted.
+type spec struct {
+	root *yaml.Node
+	doc  *openapi.Document
+}
+
+func parse(data []byte) (*spec, error) {
+	doc, err := openapi.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	return &spec{root: &root, doc: doc}, nil
+}
+
+// compile returns the check of the custom rule.
+func (cr CustomRule) compile() (func(*spec, func(jsonpath.Match, string)), error) {
+	if cr.Given == "" {
+		return nil, errors.New("given missing")
+	}
+	given, err := jsonpath.Parse(cr.Given)
+	if err != nil {
+		return nil, err
+	}
+	var pattern, notPattern *regexp.Regexp
+	if cr.Pattern != "" {
+		if pattern, err = regexp.Compile(cr.Pattern); err != nil {
+			return nil, err
+		}
+	}
+	if cr.NotPattern != "" {
+		if notPattern, err = regexp.Compile(cr.NotPattern); err != nil {
+			return nil, err
+		}
+	}
+	message := cr.Message
+	if message == "" {
+		message = cr.Description
+	}
+	if message == "" {
+		message = "violates " + cr.Name
+	}
+	forbid := !cr.Required && pattern == nil && notPattern == nil
+
+	return func(s *spec, report func(jsonpath.Match, string)) {
+		for _, m := range given.Select(s.root) {
+			target := m.Node
+			if cr.Field != "" {
+				if target = fieldValue(m.Node, cr.Field); target != nil {
+					// Report violations of the field at the field.
+					m = jsonpath.Match{Node: target, Parent: m.Node, Key: cr.Field, Index: -1,
+						Location: m.Location + "['" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(cr.Field) + "']"}
+				}
+			}
+			switch {
+			case forbid:
+				if target != nil {
+					report(m, message)
+				}
+			case target == nil:
+				if cr.Required {
+					report(m, message)
+				}
+			case pattern != nil && (target.Kind != yaml.ScalarNode || !pattern.MatchString(target.Value)),
+				notPattern != nil && target.Kind == yaml.ScalarNode && notPattern.MatchString(target.Value):
+				report(m, message)
+			}
+		}
+	}, nil
+}
+
+// fieldValue returns the value of key in the mapping m, or nil.
+func fieldValue(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+/*
+ *  lint_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const cleanSpec = `openapi: 3.0.3
+info:
+  title: Pets
+  description: Manages pets.
+  version: "1.0"
+tags:
+  - name: pets
+paths:
+  /pets/{pet-id}:
+    get:
+      operationId: showPet
+      summary: Shows a pet
+      tags: [pets]
+      parameters:
+        - name: pet-id
+          in: path
+          required: true
+          schema:
+            type: integer
+          example: 1
+      responses:
+        "200":
+          description: The pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+      example:
+        name: Rex
+`
+
+type LintSuite struct {
+	suite.Suite
+}
+
+func (suite *LintSuite) lint(c *Config, spec string) []Finding {
+	l, err := New(c)
+	require.NoError(suite.T(), err)
+	findings, err := l.Lint([]byte(spec))
+	require.NoError(suite.T(), err)
+	return findings
+}
+
+func (suite *LintSuite) TestClean() {
+	assert.Empty(suite.T(), suite.lint(nil, cleanSpec))
+}
+
+func (suite *LintSuite) TestNotASpec() {
+	l, err := New(nil)
+	require.NoError(suite.T(), err)
+	_, err = l.Lint([]byte("foo: bar"))
+	assert.Error(suite.T(), err)
+}
+
+func (suite *LintSuite) TestConfig() {
+	c, err := ParseConfig([]byte(`rules:
+  info-description: error
+  operation-description: off
+custom:
+  - name: contact
+    description: The API must name a contact.
+    given: $.info
+    field: contact
+    required: true
+  - name: no-deprecated
+    severity: error
+    message: deprecated operations must be removed
+    given: $.paths[*][?@.deprecated == true]
+  - name: semver
+    severity: info
+    given: $.info
+    field: version
+    pattern: '^\d+\.\d+\.\d+$'
+`))
+	require.NoError(suite.T(), err)
+
+	findings := suite.lint(c, `openapi: 3.0.3
+info:
+  title: Pets
+  version: "1.0"
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      deprecated: true
+      responses:
+        "200":
+          description: The pets
+`)
+	require.Len(suite.T(), findings, 4)
+	byRule := make(map[string]Finding)
+	for _, f := range findings {
+		byRule[f.Rule] = f
+	}
+	assert.Equal(suite.T(), Error, byRule["info-description"].Severity)
+	assert.Equal(suite.T(), Warn, byRule["contact"].Severity, "custom rules default to warn")
+	assert.Equal(suite.T(), "The API must name a contact.", byRule["contact"].Message)
+	assert.Equal(suite.T(), "$['paths']['/pets']['get']", byRule["no-deprecated"].Path)
+	assert.Equal(suite.T(), 8, byRule["no-deprecated"].Line)
+	assert.Equal(suite.T(), Info, byRule["semver"].Severity)
+	assert.Equal(suite.T(), "$['info']['version']", byRule["semver"].Path)
+	assert.Equal(suite.T(), 4, byRule["semver"].Line)
+	assert.True(suite.T(), HasErrors(findings))
+}
+
+func (suite *LintSuite) TestInvalidConfig() {
+	testCases := []struct {
+		desc   string
+		config string
+	}{
+		{desc: "Unknown Rule", config: "rules: {no-such-rule: error}"},
+		{desc: "Unknown Severity", config: "rules: {operation-id: fatal}"},
+		{desc: "Unknown Field", config: "rule: {operation-id: error}"},
+		{desc: "Missing Given", config: "custom: [{name: foo}]"},
+		{desc: "Invalid Path", config: "custom: [{name: foo, given: '$.['}]"},
+		{desc: "Invalid Pattern", config: "custom: [{name: foo, given: $.info, pattern: '('}]"},
+		{desc: "Duplicate Name", config: "custom: [{name: operation-id, given: $.info}]"},
+	}
+	for _, tC := range testCases {
+		suite.T().Run(tC.desc, func(t *testing.T) {
+			c, err := ParseConfig([]byte(tC.config))
+			if err == nil {
+				_, err = New(c)
+			}
+			assert.Error(t, err)
+		})
+	}
+}
+
+func (suite *LintSuite) TestFindingString() {
+	f := Finding{Rule: "operation-id", Severity: Error, Message: "operationId is missing", Path: "$['paths']['/pets']['get']", Line: 7}
+	assert.Equal(suite.T(), "7: error: operation-id: operationId is missing at $['paths']['/pets']['get']", f.String())
+}
+
+func TestLint(t *testing.T) {
+	suite.Run(t, new(LintSuite))
+}
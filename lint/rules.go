@@ -0,0 +1,245 @@
+/*
+ *  rules.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mwmahlberg/swagger-ui/jsonpath"
+	"github.com/mwmahlberg/swagger-ui/openapi"
+	"gopkg.in/yaml.v3"
+)
+
+// builtin is a rule that is part of every Linter unless turned off.
+type builtin struct {
+	Name        string
+	Description string
+	Severity    Severity
+	check       func(*spec, func(m jsonpath.Match, msg string))
+}
+
+// Builtins returns the names, descriptions and default severities of the built-in rules.
+func Builtins() []CustomRule {
+	rules := make([]CustomRule, len(builtins))
+	for i, b := range builtins {
+		rules[i] = CustomRule{Name: b.Name, Description: b.Description, Severity: b.Severity}
+	}
+	return rules
+}
+
+var builtins = []builtin{
+	{
+		Name:        "operation-id",
+		Description: "Operations must have an operationId.",
+		Severity:    Error,
+		check: func(s *spec, report func(jsonpath.Match, string)) {
+			for _, op := range operations.Select(s.root) {
+				if fieldValue(op.Node, "operationId") == nil {
+					report(op, "operationId is missing")
+				}
+			}
+		},
+	},
+	{
+		Name:        "operation-id-unique",
+		Description: "The operationIds must be unique.",
+		Severity:    Error,
+		check: func(s *spec, report func(jsonpath.Match, string)) {
+			seen := make(map[string]string)
+			for _, id := range operationIDs.Select(s.root) {
+				if first, ok := seen[id.Node.Value]; ok {
+					report(id, fmt.Sprintf("operationId %q is already used at %s", id.Node.Value, first))
+					continue
+				}
+				seen[id.Node.Value] = id.Location
+			}
+		},
+	},
+	{
+		Name:        "operation-tags-declared",
+		Description: "The tags of operations must be declared in the top-level tags.",
+		Severity:    Warn,
+		check: func(s *spec, report func(jsonpath.Match, string)) {
+			declared := make(map[string]bool)
+			for _, name := range tagNames.Select(s.root) {
+				declared[name.Node.Value] = true
+			}
+			for _, tag := range operationTags.Select(s.root) {
+				if !declared[tag.Node.Value] {
+					report(tag, fmt.Sprintf("tag %q is not declared", tag.Node.Value))
+				}
+			}
+		},
+	},
+	{
+		Name:        "operation-description",
+		Description: "Operations must have a description or a summary.",
+		Severity:    Warn,
+		check: func(s *spec, report func(jsonpath.Match, string)) {
+			for _, op := range operations.Select(s.root) {
+				if fieldValue(op.Node, "description") == nil && fieldValue(op.Node, "summary") == nil {
+					report(op, "operation has neither a description nor a summary")
+				}
+			}
+		},
+	},
+	{
+		Name:        "info-description",
+		Description: "The API must have a description.",
+		Severity:    Info,
+		check: func(s *spec, report func(jsonpath.Match, string)) {
+			for _, info := range infoPath.Select(s.root) {
+				if fieldValue(info.Node, "description") == nil {
+					report(info, "info has no description")
+				}
+			}
+		},
+	},
+	{
+		Name:        "no-unused-components",
+		Description: "Components must be referenced.",
+		Severity:    Warn,
+		check: func(s *spec, report func(jsonpath.Match, string)) {
+			unused, err := openapi.UnusedComponents(s.doc)
+			if err != nil {
+				return
+			}
+			isUnused := make(map[string]bool)
+			for _, ref := range unused {
+				isUnused[ref] = true
+			}
+			for _, kind := range componentKinds {
+				for _, c := range kind.path.Select(s.root) {
+					if isUnused[openapi.ComponentRef(kind.name, c.Key)] {
+						report(c, fmt.Sprintf("%s %q is not used", kind.name, c.Key))
+					}
+				}
+			}
+		},
+	},
+	{
+		Name:        "kebab-case-paths",
+		Description: "Path segments must be kebab-case.",
+		Severity:    Warn,
+		check: func(s *spec, report func(jsonpath.Match, string)) {
+			for _, p := range paths.Select(s.root) {
+				for _, segment := range strings.Split(p.Key, "/") {
+					if segment == "" || strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+						continue
+					}
+					if !kebabCase.MatchString(segment) {
+						report(p, fmt.Sprintf("path segment %q is not kebab-case", segment))
+						break
+					}
+				}
+			}
+		},
+	},
+	{
+		Name:        "example-matches-schema",
+		Description: "Examples must be valid against their schemas.",
+		Severity:    Error,
+		check:       checkExamples,
+	},
+}
+
+var (
+	operations    = jsonpath.MustParse("$.paths[*]['get','put','post','delete','options','head','patch','trace']")
+	operationIDs  = jsonpath.MustParse("$.paths[*]['get','put','post','delete','options','head','patch','trace'].operationId")
+	operationTags = jsonpath.MustParse("$.paths[*]['get','put','post','delete','options','head','patch','trace'].tags[*]")
+	tagNames      = jsonpath.MustParse("$.tags[*].name")
+	infoPath      = jsonpath.MustParse("$.info")
+	paths         = jsonpath.MustParse("$.paths[*]")
+	kebabCase     = regexp.MustCompile(`^[a-z0-9]+(?:[-.][a-z0-9]+)*$`)
+
+	// exampleHolders select the objects that may have a schema and examples.
+	exampleHolders = []*jsonpath.Path{
+		jsonpath.MustParse("$..parameters[*]"),
+		jsonpath.MustParse("$..headers[*]"),
+		jsonpath.MustParse("$..content[*]"),
+	}
+	componentSchemas = jsonpath.MustParse("$.components.schemas[*]")
+)
+
+type componentKind struct {
+	name string
+	path *jsonpath.Path
+}
+
+var componentKinds = func() []componentKind {
+	var kinds []componentKind
+	for _, name := range []string{
+		"schemas", "responses", "parameters", "examples", "requestBodies", "headers",
+		"securitySchemes", "links", "callbacks", "pathItems",
+	} {
+		kinds = append(kinds, componentKind{name: name, path: jsonpath.MustParse("$.components." + name + "[*]")})
+	}
+	return kinds
+}()
+
+// checkExamples validates the examples of parameters, headers and media
+// types against their schemas, as well as the examples of component schemas.
+func checkExamples(s *spec, report func(jsonpath.Match, string)) {
+	var root interface{}
+	if err := s.root.Decode(&root); err != nil {
+		return
+	}
+	v := &validator{root: root}
+	seen := make(map[*yaml.Node]bool)
+	for _, holders := range exampleHolders {
+		for _, m := range holders.Select(s.root) {
+			schema := fieldValue(m.Node, "schema")
+			if schema == nil || seen[m.Node] {
+				continue
+			}
+			seen[m.Node] = true
+			var decoded interface{}
+			if schema.Decode(&decoded) != nil {
+				continue
+			}
+			if example := fieldValue(m.Node, "example"); example != nil {
+				v.check(decoded, example, "example", m, report)
+			}
+			if examples := fieldValue(m.Node, "examples"); examples != nil && examples.Kind == yaml.MappingNode {
+				for i := 0; i+1 < len(examples.Content); i += 2 {
+					value := fieldValue(v.resolveNode(s.root, examples.Content[i+1]), "value")
+					if value != nil {
+						v.check(decoded, value, fmt.Sprintf("example %q", examples.Content[i].Value), m, report)
+					}
+				}
+			}
+		}
+	}
+	for _, m := range componentSchemas.Select(s.root) {
+		var decoded interface{}
+		if m.Node.Decode(&decoded) != nil {
+			continue
+		}
+		if example := fieldValue(m.Node, "example"); example != nil {
+			v.check(decoded, example, "example", m, report)
+		}
+		if examples := fieldValue(m.Node, "examples"); examples != nil && examples.Kind == yaml.SequenceNode {
+			for i, example := range examples.Content {
+				v.check(decoded, example, fmt.Sprintf("example %d", i), m, report)
+			}
+		}
+	}
+}
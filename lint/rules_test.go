@@ -0,0 +1,155 @@
+/*
+ *  rules_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const messySpec = `openapi: 3.0.3
+info:
+  title: Pets
+  version: "1.0"
+paths:
+  /Pets/{id}:
+    get:
+      operationId: getPet
+      tags: [pets]
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+          example: abc
+      responses:
+        "200":
+          description: The pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+              examples:
+                rex:
+                  value:
+                    name: 42
+                ref:
+                  $ref: '#/components/examples/NoName'
+  /pet-store/pets:
+    post:
+      operationId: getPet
+      description: Adds a pet
+      responses:
+        "201":
+          description: Added
+    put:
+      summary: Updates a pet
+      responses:
+        "200":
+          description: Updated
+components:
+  examples:
+    NoName:
+      value: {}
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+    Unused:
+      type: integer
+      minimum: 10
+      example: 5
+`
+
+type RulesSuite struct {
+	suite.Suite
+	findings map[string][]Finding
+}
+
+func (suite *RulesSuite) SetupSuite() {
+	l, err := New(nil)
+	suite.Require().NoError(err)
+	findings, err := l.Lint([]byte(messySpec))
+	suite.Require().NoError(err)
+	suite.findings = make(map[string][]Finding)
+	for _, f := range findings {
+		suite.findings[f.Rule] = append(suite.findings[f.Rule], f)
+	}
+}
+
+func (suite *RulesSuite) messages(rule string) []string {
+	var messages []string
+	for _, f := range suite.findings[rule] {
+		messages = append(messages, f.Message)
+	}
+	return messages
+}
+
+func (suite *RulesSuite) TestOperationID() {
+	assert.Equal(suite.T(), []string{"operationId is missing"}, suite.messages("operation-id"))
+	assert.Equal(suite.T(), "$['paths']['/pet-store/pets']['put']", suite.findings["operation-id"][0].Path)
+	assert.Equal(suite.T(),
+		[]string{`operationId "getPet" is already used at $['paths']['/Pets/{id}']['get']['operationId']`},
+		suite.messages("operation-id-unique"))
+}
+
+func (suite *RulesSuite) TestTags() {
+	assert.Equal(suite.T(), []string{`tag "pets" is not declared`}, suite.messages("operation-tags-declared"))
+}
+
+func (suite *RulesSuite) TestDescriptions() {
+	assert.Len(suite.T(), suite.findings["operation-description"], 1)
+	assert.Equal(suite.T(), "$['paths']['/Pets/{id}']['get']", suite.findings["operation-description"][0].Path)
+	assert.Equal(suite.T(), []string{"info has no description"}, suite.messages("info-description"))
+	assert.Equal(suite.T(), Info, suite.findings["info-description"][0].Severity)
+}
+
+func (suite *RulesSuite) TestUnusedComponents() {
+	assert.Equal(suite.T(), []string{`schemas "Unused" is not used`}, suite.messages("no-unused-components"))
+}
+
+func (suite *RulesSuite) TestKebabCase() {
+	assert.Equal(suite.T(), []string{`path segment "Pets" is not kebab-case`}, suite.messages("kebab-case-paths"))
+}
+
+func (suite *RulesSuite) TestExamples() {
+	assert.ElementsMatch(suite.T(), []string{
+		"example: /: expected integer, got string",
+		`example "rex": /name: expected string, got integer`,
+		`example "ref": /: property "name" is missing`,
+		"example: /: 5 is less than the minimum 10",
+	}, suite.messages("example-matches-schema"))
+}
+
+func (suite *RulesSuite) TestBuiltins() {
+	rules := Builtins()
+	assert.Len(suite.T(), rules, len(builtins))
+	assert.Equal(suite.T(), "operation-id", rules[0].Name)
+	assert.Equal(suite.T(), Error, rules[0].Severity)
+}
+
+func TestRules(t *testing.T) {
+	suite.Run(t, new(RulesSuite))
+}
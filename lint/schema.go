@@ -0,0 +1,357 @@
+/*
+ *  schema.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mwmahlberg/swagger-ui/jsonpath"
+	"gopkg.in/yaml.v3"
+)
+
+// maxDepth limits the recursion into schemas, guarding against reference cycles.
+const maxDepth = 64
+
+// validator checks values against the subset of JSON Schema used by OpenAPI.
+// Formats are not checked.
+type validator struct {
+	// root is the decoded document, used to resolve references.
+	root interface{}
+}
+
+// check validates the example node against schema and reports the violations found.
+func (v *validator) check(schema interface{}, example *yaml.Node, label string, m jsonpath.Match, report func(jsonpath.Match, string)) {
+	var value interface{}
+	if example.Decode(&value) != nil {
+		return
+	}
+	var errs []string
+	v.validate(schema, value, "", 0, &errs)
+	for _, err := range errs {
+		report(m, label+": "+err)
+	}
+}
+
+// validate appends the violations of value against schema at the JSON pointer at to errs.
+func (v *validator) validate(schema, value interface{}, at string, depth int, errs *[]string) {
+	if depth > maxDepth {
+		return
+	}
+	fail := func(format string, args ...interface{}) {
+		where := at
+		if where == "" {
+			where = "/"
+		}
+		*errs = append(*errs, where+": "+fmt.Sprintf(format, args...))
+	}
+	switch s := schema.(type) {
+	case bool:
+		if !s {
+			fail("no value is allowed")
+		}
+		return
+	case map[string]interface{}:
+		if ref, ok := s["$ref"].(string); ok {
+			if target, ok := v.resolve(ref); ok {
+				v.validate(target, value, at, depth+1, errs)
+			}
+			// In OpenAPI 3.0, siblings of $ref are ignored.
+			if len(s) == 1 || !isOAS31(v.root) {
+				return
+			}
+		}
+		v.validateObject(s, value, at, depth, errs, fail)
+	}
+}
+
+func (v *validator) validateObject(s map[string]interface{}, value interface{}, at string, depth int, errs *[]string, fail func(string, ...interface{})) {
+	if value == nil && s["nullable"] == true {
+		return
+	}
+	if types := schemaTypes(s["type"]); len(types) > 0 {
+		actual := typeOf(value)
+		matches := false
+		for _, t := range types {
+			if t == actual || t == "number" && actual == "integer" {
+				matches = true
+			}
+		}
+		if !matches {
+			fail("expected %s, got %s", strings.Join(types, " or "), actual)
+			return
+		}
+	}
+	if enum, ok := s["enum"].([]interface{}); ok && !containsValue(enum, value) {
+		fail("value is not one of the enum values")
+	}
+	if c, ok := s["const"]; ok && !equalValues(c, value) {
+		fail("value does not equal the constant")
+	}
+
+	switch val := value.(type) {
+	case string:
+		if n, ok := number(s["minLength"]); ok && float64(len([]rune(val))) < n {
+			fail("string shorter than %v", n)
+		}
+		if n, ok := number(s["maxLength"]); ok && float64(len([]rune(val))) > n {
+			fail("string longer than %v", n)
+		}
+		if p, ok := s["pattern"].(string); ok {
+			if re, err := regexp.Compile(p); err == nil && !re.MatchString(val) {
+				fail("string does not match %q", p)
+			}
+		}
+	case map[string]interface{}:
+		if required, ok := s["required"].([]interface{}); ok {
+			for _, r := range required {
+				if name, ok := r.(string); ok {
+					if _, ok := val[name]; !ok {
+						fail("property %q is missing", name)
+					}
+				}
+			}
+		}
+		props, _ := s["properties"].(map[string]interface{})
+		for name, pv := range val {
+			child := at + "/" + strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+			if ps, ok := props[name]; ok {
+				v.validate(ps, pv, child, depth+1, errs)
+				continue
+			}
+			switch ap := s["additionalProperties"].(type) {
+			case bool:
+				if !ap {
+					fail("property %q is not allowed", name)
+				}
+			case map[string]interface{}:
+				v.validate(ap, pv, child, depth+1, errs)
+			}
+		}
+	case []interface{}:
+		if n, ok := number(s["minItems"]); ok && float64(len(val)) < n {
+			fail("fewer than %v items", n)
+		}
+		if n, ok := number(s["maxItems"]); ok && float64(len(val)) > n {
+			fail("more than %v items", n)
+		}
+		if items, ok := s["items"]; ok {
+			for i, item := range val {
+				v.validate(items, item, at+"/"+strconv.Itoa(i), depth+1, errs)
+			}
+		}
+	default:
+		if n, ok := number(value); ok {
+			validateNumber(s, n, fail)
+		}
+	}
+
+	if all, ok := s["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			v.validate(sub, value, at, depth+1, errs)
+		}
+	}
+	if anyOf, ok := s["anyOf"].([]interface{}); ok && v.countValid(anyOf, value, depth) == 0 {
+		fail("value matches none of anyOf")
+	}
+	if oneOf, ok := s["oneOf"].([]interface{}); ok {
+		if n := v.countValid(oneOf, value, depth); n != 1 {
+			fail("value matches %d of oneOf instead of exactly one", n)
+		}
+	}
+	if not, ok := s["not"]; ok && v.countValid([]interface{}{not}, value, depth) == 1 {
+		fail("value matches the schema of not")
+	}
+}
+
+func validateNumber(s map[string]interface{}, n float64, fail func(string, ...interface{})) {
+	if m, ok := number(s["minimum"]); ok {
+		if n < m || n == m && s["exclusiveMinimum"] == true {
+			fail("%v is less than the minimum %v", n, m)
+		}
+	}
+	if m, ok := number(s["maximum"]); ok {
+		if n > m || n == m && s["exclusiveMaximum"] == true {
+			fail("%v is greater than the maximum %v", n, m)
+		}
+	}
+	// In OpenAPI 3.1, the exclusive bounds are numbers.
+	if m, ok := number(s["exclusiveMinimum"]); ok && n <= m {
+		fail("%v is not greater than %v", n, m)
+	}
+	if m, ok := number(s["exclusiveMaximum"]); ok && n >= m {
+		fail("%v is not less than %v", n, m)
+	}
+	if m, ok := number(s["multipleOf"]); ok && m > 0 {
+		if q := n / m; math.Abs(q-math.Round(q)) > 1e-9 {
+			fail("%v is not a multiple of %v", n, m)
+		}
+	}
+}
+
+// countValid returns the number of schemas value is valid against.
+func (v *validator) countValid(schemas []interface{}, value interface{}, depth int) int {
+	n := 0
+	for _, s := range schemas {
+		var errs []string
+		v.validate(s, value, "", depth+1, &errs)
+		if len(errs) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// resolve looks up a local reference in the document.
+func (v *validator) resolve(ref string) (interface{}, bool) {
+	pointer, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil, false
+	}
+	if p, err := url.PathUnescape(pointer); err == nil {
+		pointer = p
+	}
+	cur := v.root
+	for _, token := range strings.Split(pointer, "/")[1:] {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch c := cur.(type) {
+		case map[string]interface{}:
+			if cur, ok = c[token]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil, false
+			}
+			cur = c[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// resolveNode follows a local reference object n within root, returning n if it is none.
+func (v *validator) resolveNode(root, n *yaml.Node) *yaml.Node {
+	ref := fieldValue(n, "$ref")
+	if ref == nil {
+		return n
+	}
+	pointer, ok := strings.CutPrefix(ref.Value, "#/")
+	if !ok {
+		return n
+	}
+	cur := root
+	if cur.Kind == yaml.DocumentNode && len(cur.Content) > 0 {
+		cur = cur.Content[0]
+	}
+	for _, token := range strings.Split(pointer, "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		if cur = fieldValue(cur, token); cur == nil {
+			return n
+		}
+	}
+	return cur
+}
+
+func isOAS31(root interface{}) bool {
+	doc, _ := root.(map[string]interface{})
+	version, _ := doc["openapi"].(string)
+	return strings.HasPrefix(version, "3.1")
+}
+
+// schemaTypes returns the types allowed by the type keyword, be it a string or, in OpenAPI 3.1, an array.
+func schemaTypes(t interface{}) []string {
+	switch t := t.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		var types []string
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// typeOf returns the JSON Schema type of a decoded value.
+func typeOf(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string, time.Time:
+		return "string"
+	case int, int64, uint64:
+		return "integer"
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if equalValues(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalValues compares decoded values, treating numbers of different Go types as equal.
+func equalValues(a, b interface{}) bool {
+	if na, ok := number(a); ok {
+		nb, ok := number(b)
+		return ok && na == nb
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,59 @@
+/*
+ *  lint_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"testing"
+
+	"github.com/mwmahlberg/swagger-ui/lint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type LintSuite struct {
+	suite.Suite
+}
+
+func (suite *LintSuite) TestErrorsFailSetup() {
+	// refSpec has no operationId, which is an error by default.
+	_, err := New(Spec("pets.yaml", []byte(refSpec)), Lint(nil))
+	require.ErrorAs(suite.T(), err, &SetupError{})
+	assert.Contains(suite.T(), err.Error(), "operation-id")
+}
+
+func (suite *LintSuite) TestWarnings() {
+	ui, err := New(Spec("pets.yaml", []byte(refSpec)), Lint(&lint.Config{
+		Rules: map[string]lint.Severity{"operation-id": lint.Warn},
+	}))
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), ui.Warnings(),
+		"8: warn: operation-id: operationId is missing at $['paths']['/pets']['get']")
+}
+
+func (suite *LintSuite) TestInvalidConfig() {
+	_, err := New(Spec("pets.yaml", []byte(refSpec)), Lint(&lint.Config{
+		Rules: map[string]lint.Severity{"no-such-rule": lint.Warn},
+	}))
+	assert.ErrorAs(suite.T(), err, &SetupError{})
+}
+
+func TestLint(t *testing.T) {
+	suite.Run(t, new(LintSuite))
+}
@@ -0,0 +1,63 @@
+/*
+ *  live.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"path"
+	"sync"
+	"time"
+)
+
+// liveFiles holds files that change while the handler is running. Unlike the
+// overlay, they can safely be replaced while being served.
+type liveFiles struct {
+	mu    sync.RWMutex
+	files map[string]liveFile
+}
+
+type liveFile struct {
+	name    string
+	data    []byte
+	modTime time.Time
+}
+
+func newLiveFiles() *liveFiles {
+	return &liveFiles{files: make(map[string]liveFile)}
+}
+
+// set replaces the content of the file name.
+func (l *liveFiles) set(name string, data []byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.files[name] = liveFile{name: path.Base(name), data: data, modTime: time.Now()}
+}
+
+// remove deletes the file name, so that it is served from the overlay again, if present.
+func (l *liveFiles) remove(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.files, name)
+}
+
+func (l *liveFiles) get(name string) (liveFile, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	f, ok := l.files[name]
+	return f, ok
+}
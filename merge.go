@@ -0,0 +1,38 @@
+/*
+ *  merge.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"github.com/mwmahlberg/swagger-ui/openapi"
+)
+
+// MergedSpec sets the spec to the combination of several documents, for
+// example of all services behind an API portal. See openapi.Merge for how
+// paths, tags and components are combined.
+func MergedSpec(info *openapi.Info, sources ...openapi.MergeSource) HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.mergeInfo = info
+		suh.mergeSources = sources
+	}
+}
+
+func (ui *SwaggerUi) mergeSpecs() (err error) {
+	ui.document, err = openapi.Merge(ui.mergeInfo, ui.mergeSources...)
+	return err
+}
@@ -0,0 +1,65 @@
+/*
+ *  merge_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"testing"
+
+	"github.com/mwmahlberg/swagger-ui/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type MergeSuite struct {
+	suite.Suite
+}
+
+func (suite *MergeSuite) TestMergedSpec() {
+	pets, err := openapi.Parse([]byte(refSpec))
+	require.NoError(suite.T(), err)
+	store := &openapi.Document{OpenAPI: "3.0.3", Info: &openapi.Info{Title: "Store", Version: "1.0"},
+		Paths: map[string]*openapi.PathItem{"/orders": {Get: &openapi.Operation{OperationID: "listOrders",
+			Responses: map[string]*openapi.Response{"200": {Description: "The orders"}}}}}}
+
+	ui, err := New(MergedSpec(&openapi.Info{Title: "Portal", Version: "1.0"},
+		openapi.MergeSource{Name: "pets", Document: pets, PathPrefix: "/pets-service"},
+		openapi.MergeSource{Name: "store", Document: store, PathPrefix: "/store"}))
+	require.NoError(suite.T(), err)
+
+	doc, err := ui.Document()
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Portal", doc.Info.Title)
+	assert.Contains(suite.T(), doc.Paths, "/pets-service/pets")
+	assert.Contains(suite.T(), doc.Paths, "/store/orders")
+}
+
+func (suite *MergeSuite) TestConflict() {
+	pets, err := openapi.Parse([]byte(refSpec))
+	require.NoError(suite.T(), err)
+
+	_, err = New(MergedSpec(&openapi.Info{Title: "Portal", Version: "1.0"},
+		openapi.MergeSource{Name: "a", Document: pets},
+		openapi.MergeSource{Name: "b", Document: pets}))
+	assert.ErrorAs(suite.T(), err, &SetupError{})
+}
+
+func TestMerge(t *testing.T) {
+	suite.Run(t, new(MergeSuite))
+}
@@ -0,0 +1,215 @@
+/*
+ *  bundle.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Bundle reads the document root from fsys and inlines every external
+// reference, such as "schemas/pet.yaml" or "paths.yaml#/pets", so that the
+// result is a single self-contained document.
+//
+// Relative references are resolved against the file that contains them. The
+// first reference to a target is replaced by its content; all further
+// references, including recursive ones, point to that location instead.
+// References that only lead back to themselves are reported as ErrCyclicRef.
+func Bundle(fsys fs.FS, root string) (*Document, error) {
+	root = path.Clean(root)
+	b := &bundler{
+		fsys:   fsys,
+		root:   root,
+		files:  make(map[string]*yaml.Node),
+		placed: make(map[string]string),
+	}
+	data, err := fs.ReadFile(fsys, root)
+	if err != nil {
+		return nil, err
+	}
+	n, err := parseNode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", root, err)
+	}
+	if err := b.walk(n, root, ""); err != nil {
+		return nil, err
+	}
+	return decodeDocument(n, isJSON(data))
+}
+
+type bundler struct {
+	fsys fs.FS
+	root string
+	// files caches the parsed referenced files by path.
+	files map[string]*yaml.Node
+	// placed maps targets to the pointer at which they were inlined.
+	placed map[string]string
+}
+
+// walk inlines the external references found in n, which is located at
+// pointer in the bundled document and was read from file.
+func (b *bundler) walk(n *yaml.Node, file, pointer string) error {
+	switch n.Kind {
+	case yaml.MappingNode:
+		if ref := mappingValue(n, "$ref"); ref != nil && ref.Kind == yaml.ScalarNode {
+			return b.inline(n, ref.Value, file, pointer)
+		}
+		keys := make([]int, 0, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			// Components are placed first, so that references
+			// elsewhere point to them rather than the other way round.
+			if pointer == "" && n.Content[i].Value == "components" {
+				keys = append([]int{i}, keys...)
+				continue
+			}
+			keys = append(keys, i)
+		}
+		for _, i := range keys {
+			if err := b.walk(n.Content[i+1], file, pointer+"/"+escapePointer(n.Content[i].Value)); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range n.Content {
+			if err := b.walk(item, file, pointer+"/"+strconv.Itoa(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// inline replaces the reference node n with the content ref points to.
+func (b *bundler) inline(n *yaml.Node, ref, file, pointer string) error {
+	var chain []string
+	for {
+		target, fragment, err := b.target(ref, file)
+		if err != nil {
+			return err
+		}
+		if target == b.root {
+			setMappingValue(n, "$ref", stringNode("#"+fragment))
+			return nil
+		}
+		key := target + "#" + fragment
+		if p, ok := b.placed[key]; ok {
+			setMappingValue(n, "$ref", stringNode("#"+p))
+			return nil
+		}
+		for _, k := range chain {
+			if k == key {
+				return fmt.Errorf("%s: %s: %w", file, ref, ErrCyclicRef)
+			}
+		}
+		chain = append(chain, key)
+
+		doc, err := b.load(target)
+		if err != nil {
+			return err
+		}
+		v, err := pointerLookup(doc, fragment)
+		if err != nil {
+			return fmt.Errorf("%s: %s: %w: %s", file, ref, ErrUnresolvedRef, err)
+		}
+		if next := mappingValue(v, "$ref"); v.Kind == yaml.MappingNode && next != nil {
+			ref, file = next.Value, target
+			continue
+		}
+
+		for _, k := range chain {
+			b.placed[k] = pointer
+		}
+		replaceRef(n, v)
+		return b.walk(n, target, pointer)
+	}
+}
+
+// target splits ref into the path of the file it refers to and the JSON pointer within it.
+func (b *bundler) target(ref, file string) (string, string, error) {
+	filePart, fragment, _ := strings.Cut(ref, "#")
+	if filePart == "" {
+		return file, fragment, nil
+	}
+	if u, err := url.Parse(filePart); err == nil && u.Scheme != "" {
+		return "", "", fmt.Errorf("%s: %s: remote references are not supported", file, ref)
+	}
+	if unescaped, err := url.PathUnescape(filePart); err == nil {
+		filePart = unescaped
+	}
+	if path.IsAbs(filePart) {
+		return "", "", fmt.Errorf("%s: %s: absolute references are not supported", file, ref)
+	}
+	target := path.Join(path.Dir(file), filePart)
+	if !fs.ValidPath(target) {
+		return "", "", fmt.Errorf("%s: %s: reference leaves the file system", file, ref)
+	}
+	return target, fragment, nil
+}
+
+// load returns the parsed content of the file name.
+func (b *bundler) load(name string) (*yaml.Node, error) {
+	if n, ok := b.files[name]; ok {
+		return n, nil
+	}
+	data, err := fs.ReadFile(b.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("%s: empty document", name)
+	}
+	b.files[name] = doc.Content[0]
+	return doc.Content[0], nil
+}
+
+// replaceRef replaces the reference node n with a copy of target. Keys next
+// to the reference, such as a description, take precedence over the target's.
+func replaceRef(n, target *yaml.Node) {
+	content := copyNode(target)
+	if content.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if n.Content[i].Value != "$ref" {
+				setMappingValue(content, n.Content[i].Value, n.Content[i+1])
+			}
+		}
+	}
+	*n = *content
+}
+
+// copyNode returns a deep copy of n.
+func copyNode(n *yaml.Node) *yaml.Node {
+	c := *n
+	if n.Content != nil {
+		c.Content = make([]*yaml.Node, len(n.Content))
+		for i, child := range n.Content {
+			c.Content[i] = copyNode(child)
+		}
+	}
+	return &c
+}
@@ -0,0 +1,143 @@
+/*
+ *  bundle_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+var specTree = fstest.MapFS{
+	"api/openapi.yaml": {Data: []byte(`openapi: 3.0.3
+info:
+  title: Pets
+  version: "1.0"
+paths:
+  /pets:
+    $ref: paths/pets.yaml
+components:
+  schemas:
+    Pet:
+      $ref: schemas/pet.yaml
+    Error:
+      $ref: '#/components/schemas/Problem'
+    Problem:
+      type: object
+`)},
+	"api/paths/pets.yaml": {Data: []byte(`get:
+  operationId: listPets
+  responses:
+    "200":
+      description: The pets
+      content:
+        application/json:
+          schema:
+            type: array
+            items:
+              $ref: ../schemas/pet.yaml
+    default:
+      description: An error
+      content:
+        application/json:
+          schema:
+            $ref: ../openapi.yaml#/components/schemas/Error
+`)},
+	"api/schemas/pet.yaml": {Data: []byte(`type: object
+properties:
+  name:
+    type: string
+  owner:
+    $ref: '#/definitions/Owner'
+  parent:
+    $ref: pet.yaml
+definitions:
+  Owner:
+    type: object
+    properties:
+      pets:
+        type: array
+        items:
+          $ref: ./pet.yaml
+`)},
+	"cycle/openapi.yaml": {Data: []byte(`openapi: 3.0.3
+info:
+  title: Cycle
+  version: "1.0"
+paths: {}
+components:
+  schemas:
+    A:
+      $ref: a.yaml
+`)},
+	"cycle/a.yaml": {Data: []byte(`$ref: b.yaml`)},
+	"cycle/b.yaml": {Data: []byte(`$ref: a.yaml`)},
+	"missing/openapi.yaml": {Data: []byte(`openapi: 3.0.3
+info:
+  title: Missing
+  version: "1.0"
+paths:
+  /pets:
+    $ref: nowhere.yaml
+`)},
+}
+
+type BundleSuite struct {
+	suite.Suite
+}
+
+func (suite *BundleSuite) TestBundle() {
+	doc, err := Bundle(specTree, "api/openapi.yaml")
+	require.NoError(suite.T(), err)
+
+	pet := doc.Components.Schemas["Pet"]
+	require.NotNil(suite.T(), pet)
+	assert.Empty(suite.T(), pet.Ref)
+	assert.Equal(suite.T(), "#/components/schemas/Pet", pet.Properties["parent"].Ref)
+	owner := pet.Properties["owner"]
+	assert.Empty(suite.T(), owner.Ref)
+	assert.Equal(suite.T(), "#/components/schemas/Pet", owner.Properties["pets"].Items.Ref)
+
+	op := doc.Paths["/pets"].Get
+	require.NotNil(suite.T(), op)
+	assert.Equal(suite.T(), "listPets", op.OperationID)
+	assert.Equal(suite.T(), "#/components/schemas/Pet", op.Responses["200"].Content["application/json"].Schema.Items.Ref)
+	assert.Equal(suite.T(), "#/components/schemas/Error", op.Responses["default"].Content["application/json"].Schema.Ref)
+
+	out, err := doc.YAML()
+	require.NoError(suite.T(), err)
+	assert.NotContains(suite.T(), string(out), ".yaml")
+}
+
+func (suite *BundleSuite) TestCycle() {
+	_, err := Bundle(specTree, "cycle/openapi.yaml")
+	assert.ErrorIs(suite.T(), err, ErrCyclicRef)
+}
+
+func (suite *BundleSuite) TestMissingFile() {
+	_, err := Bundle(specTree, "missing/openapi.yaml")
+	assert.Error(suite.T(), err)
+}
+
+func TestBundle(t *testing.T) {
+	suite.Run(t, new(BundleSuite))
+}
@@ -0,0 +1,135 @@
+/*
+ *  components.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnusedComponents returns references to the components of d that are
+// referenced neither from outside the components nor from a used component,
+// such as "#/components/schemas/Unused", in document order. Security schemes
+// count as used if a security requirement names them.
+func UnusedComponents(d *Document) ([]string, error) {
+	root, err := d.render()
+	if err != nil {
+		return nil, err
+	}
+	all := mappingValue(root, "components")
+	if all == nil {
+		return nil, nil
+	}
+	rest := mappingNode()
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "components" {
+			appendPair(rest, root.Content[i], root.Content[i+1])
+		}
+	}
+	used := usedComponents(all, rest)
+
+	var unused []string
+	for i := 0; i+1 < len(all.Content); i += 2 {
+		kind, components := all.Content[i].Value, all.Content[i+1]
+		if strings.HasPrefix(kind, "x-") || components.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j < len(components.Content); j += 2 {
+			if name := components.Content[j].Value; !used[kind][name] {
+				unused = append(unused, ComponentRef(kind, name))
+			}
+		}
+	}
+	return unused, nil
+}
+
+// usedComponents returns the names of the components in all, by kind, that
+// are referenced from the node from, directly or through other components.
+func usedComponents(all, from *yaml.Node) map[string]map[string]bool {
+	used := make(map[string]map[string]bool)
+	var queue []*yaml.Node
+	use := func(kind, name string) {
+		if used[kind] == nil {
+			used[kind] = make(map[string]bool)
+		}
+		if used[kind][name] {
+			return
+		}
+		used[kind][name] = true
+		if n := mappingValue(mappingValue(all, kind), name); n != nil {
+			queue = append(queue, n)
+		}
+	}
+	collectComponents(from, use)
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		collectComponents(n, use)
+	}
+	return used
+}
+
+// collectComponents calls use for each component referenced within n, be it
+// by $ref, by a discriminator mapping or, for security schemes, by name in a
+// security requirement.
+func collectComponents(n *yaml.Node, use func(kind, name string)) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i].Value, n.Content[i+1]
+			switch {
+			case key == "$ref" && value.Kind == yaml.ScalarNode:
+				useRef(value.Value, use)
+			case key == "mapping" && value.Kind == yaml.MappingNode:
+				for j := 1; j < len(value.Content); j += 2 {
+					useRef(value.Content[j].Value, use)
+				}
+			case key == "security" && value.Kind == yaml.SequenceNode:
+				for _, req := range value.Content {
+					if req.Kind != yaml.MappingNode {
+						continue
+					}
+					for j := 0; j+1 < len(req.Content); j += 2 {
+						use("securitySchemes", req.Content[j].Value)
+					}
+				}
+			}
+			collectComponents(value, use)
+		}
+	case yaml.SequenceNode:
+		for _, item := range n.Content {
+			collectComponents(item, use)
+		}
+	}
+}
+
+// useRef calls use for the component ref points into, if any.
+func useRef(ref string, use func(kind, name string)) {
+	rest, ok := strings.CutPrefix(ref, "#/components/")
+	if !ok {
+		return
+	}
+	kind, name, ok := strings.Cut(rest, "/")
+	if !ok {
+		return
+	}
+	name, _, _ = strings.Cut(name, "/")
+	use(kind, unescapePointer(name))
+}
@@ -0,0 +1,66 @@
+/*
+ *  components_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ComponentsSuite struct {
+	suite.Suite
+}
+
+func (suite *ComponentsSuite) TestUnusedComponents() {
+	doc, err := Parse([]byte(storeSpec))
+	require.NoError(suite.T(), err)
+	unused, err := UnusedComponents(doc)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"#/components/schemas/Unused"}, unused)
+}
+
+func (suite *ComponentsSuite) TestSelfReference() {
+	doc, err := Parse([]byte(`openapi: 3.1.0
+info: {title: Tree, version: "1"}
+components:
+  schemas:
+    Node:
+      type: object
+      properties:
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Node'
+  securitySchemes:
+    key: {type: apiKey, in: header, name: X-Key}
+security:
+  - key: []
+`))
+	require.NoError(suite.T(), err)
+	unused, err := UnusedComponents(doc)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"#/components/schemas/Node"}, unused)
+}
+
+func TestComponents(t *testing.T) {
+	suite.Run(t, new(ComponentsSuite))
+}
@@ -0,0 +1,90 @@
+/*
+ *  deref.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Dereference returns a copy of d with all local references replaced by the
+// content they point to, for tools that cannot follow $ref.
+//
+// A reference within the content it points to, as found in recursive schemas,
+// cannot be inlined and is kept. The components are retained, so that such
+// references remain valid.
+func Dereference(d *Document) (*Document, error) {
+	root, err := d.render()
+	if err != nil {
+		return nil, err
+	}
+	out := copyNode(root)
+	if err := dereference(root, out, "", nil); err != nil {
+		return nil, err
+	}
+	return decodeDocument(out, d.sourceJSON)
+}
+
+// dereference inlines the references within n, looking up their targets in
+// root. source is the location in root n was copied from, and stack holds the
+// references currently being inlined.
+func dereference(root, n *yaml.Node, source string, stack []string) error {
+	switch n.Kind {
+	case yaml.MappingNode:
+		if ref := mappingValue(n, "$ref"); ref != nil && ref.Kind == yaml.ScalarNode {
+			return inlineRef(root, n, ref.Value, source, stack)
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if err := dereference(root, n.Content[i+1], source+"/"+escapePointer(n.Content[i].Value), stack); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range n.Content {
+			if err := dereference(root, item, source+"/"+strconv.Itoa(i), stack); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func inlineRef(root, n *yaml.Node, ref, source string, stack []string) error {
+	if !strings.HasPrefix(ref, "#") {
+		return fmt.Errorf("%q: %w", ref, ErrExternalRef)
+	}
+	// A reference to a location enclosing the reference itself is recursive.
+	if pointer := ref[1:]; source == pointer || strings.HasPrefix(source, pointer+"/") {
+		return nil
+	}
+	for _, r := range stack {
+		if r == ref {
+			return nil
+		}
+	}
+	target, err := pointerLookup(root, ref[1:])
+	if err != nil {
+		return fmt.Errorf("%q: %w: %s", ref, ErrUnresolvedRef, err)
+	}
+	replaceRef(n, target)
+	return dereference(root, n, ref[1:], append(stack, ref))
+}
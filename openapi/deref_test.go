@@ -0,0 +1,66 @@
+/*
+ *  deref_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type DereferenceSuite struct {
+	suite.Suite
+}
+
+func (suite *DereferenceSuite) TestDereference() {
+	doc, err := Parse([]byte(commentedSpec))
+	require.NoError(suite.T(), err)
+	doc.Components.Schemas["Loop"] = &Schema{Type: TypeOf("object"), Properties: map[string]*Schema{
+		"next": RefSchema("Loop"),
+	}}
+
+	deref, err := Dereference(doc)
+	require.NoError(suite.T(), err)
+
+	op := deref.Paths["/pets/{id}"].Get
+	assert.Equal(suite.T(), "id", op.Parameters[0].Name)
+	assert.Empty(suite.T(), op.Parameters[0].Ref)
+	schema := op.Responses["200"].Content["application/json"].Schema
+	assert.Empty(suite.T(), schema.Ref)
+	assert.Equal(suite.T(), TypeOf("string"), schema.Properties["name"].Type)
+
+	loop := deref.Components.Schemas["Loop"]
+	assert.Equal(suite.T(), "#/components/schemas/Loop", loop.Properties["next"].Ref)
+
+	assert.Equal(suite.T(), "#/components/parameters/ID", doc.Paths["/pets/{id}"].Get.Parameters[0].Ref,
+		"the original document must not change")
+}
+
+func (suite *DereferenceSuite) TestExternalRef() {
+	doc := &Document{OpenAPI: "3.0.3", Info: &Info{Title: "External", Version: "1.0"},
+		Components: &Components{Schemas: map[string]*Schema{"Pet": {Ref: "pet.yaml"}}}}
+	_, err := Dereference(doc)
+	assert.ErrorIs(suite.T(), err, ErrExternalRef)
+}
+
+func TestDereference(t *testing.T) {
+	suite.Run(t, new(DereferenceSuite))
+}
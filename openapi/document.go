@@ -0,0 +1,381 @@
+/*
+ *  document.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Methods lists the HTTP methods a PathItem can hold operations for, in the
+// order they appear in the specification.
+var Methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Document is the root object of an OpenAPI document.
+type Document struct {
+	OpenAPI           string                 `yaml:"openapi"`
+	Info              *Info                  `yaml:"info"`
+	JSONSchemaDialect string                 `yaml:"jsonSchemaDialect,omitempty"`
+	Servers           []*Server              `yaml:"servers,omitempty"`
+	Paths             map[string]*PathItem   `yaml:"paths"`
+	Webhooks          map[string]*PathItem   `yaml:"webhooks,omitempty"`
+	Components        *Components            `yaml:"components,omitempty"`
+	Security          SecurityRequirements   `yaml:"security,omitempty"`
+	Tags              []*Tag                 `yaml:"tags,omitempty"`
+	ExternalDocs      *ExternalDocs          `yaml:"externalDocs,omitempty"`
+	Extensions        map[string]interface{} `yaml:",inline"`
+
+	// node is the root node the document was parsed from. It retains the
+	// order of keys and the comments of the source when rendering.
+	node *yaml.Node
+	// sourceJSON is set if the document was parsed from JSON.
+	sourceJSON bool
+}
+
+// Info provides metadata about the API.
+type Info struct {
+	Title          string                 `yaml:"title"`
+	Summary        string                 `yaml:"summary,omitempty"`
+	Description    string                 `yaml:"description,omitempty"`
+	TermsOfService string                 `yaml:"termsOfService,omitempty"`
+	Contact        *Contact               `yaml:"contact,omitempty"`
+	License        *License               `yaml:"license,omitempty"`
+	Version        string                 `yaml:"version"`
+	Extensions     map[string]interface{} `yaml:",inline"`
+}
+
+// Contact information for the API.
+type Contact struct {
+	Name       string                 `yaml:"name,omitempty"`
+	URL        string                 `yaml:"url,omitempty"`
+	Email      string                 `yaml:"email,omitempty"`
+	Extensions map[string]interface{} `yaml:",inline"`
+}
+
+// License information for the API.
+type License struct {
+	Name       string                 `yaml:"name"`
+	Identifier string                 `yaml:"identifier,omitempty"`
+	URL        string                 `yaml:"url,omitempty"`
+	Extensions map[string]interface{} `yaml:",inline"`
+}
+
+// Server describes a server hosting the API.
+type Server struct {
+	URL         string                     `yaml:"url"`
+	Description string                     `yaml:"description,omitempty"`
+	Variables   map[string]*ServerVariable `yaml:"variables,omitempty"`
+	Extensions  map[string]interface{}     `yaml:",inline"`
+}
+
+// ServerVariable is a variable for server URL template substitution.
+type ServerVariable struct {
+	Enum        []string               `yaml:"enum,omitempty"`
+	Default     string                 `yaml:"default"`
+	Description string                 `yaml:"description,omitempty"`
+	Extensions  map[string]interface{} `yaml:",inline"`
+}
+
+// PathItem describes the operations available on a single path.
+type PathItem struct {
+	Ref         string                 `yaml:"$ref,omitempty"`
+	Summary     string                 `yaml:"summary,omitempty"`
+	Description string                 `yaml:"description,omitempty"`
+	Get         *Operation             `yaml:"get,omitempty"`
+	Put         *Operation             `yaml:"put,omitempty"`
+	Post        *Operation             `yaml:"post,omitempty"`
+	Delete      *Operation             `yaml:"delete,omitempty"`
+	Options     *Operation             `yaml:"options,omitempty"`
+	Head        *Operation             `yaml:"head,omitempty"`
+	Patch       *Operation             `yaml:"patch,omitempty"`
+	Trace       *Operation             `yaml:"trace,omitempty"`
+	Servers     []*Server              `yaml:"servers,omitempty"`
+	Parameters  []*Parameter           `yaml:"parameters,omitempty"`
+	Extensions  map[string]interface{} `yaml:",inline"`
+}
+
+// Operation returns the operation for the lower case method, or nil.
+func (p *PathItem) Operation(method string) *Operation {
+	if ptr := p.operationPtr(method); ptr != nil {
+		return *ptr
+	}
+	return nil
+}
+
+// SetOperation sets the operation for the lower case method. It panics for unknown methods.
+func (p *PathItem) SetOperation(method string, op *Operation) {
+	ptr := p.operationPtr(method)
+	if ptr == nil {
+		panic("openapi: unknown method " + method)
+	}
+	*ptr = op
+}
+
+func (p *PathItem) operationPtr(method string) **Operation {
+	switch method {
+	case "get":
+		return &p.Get
+	case "put":
+		return &p.Put
+	case "post":
+		return &p.Post
+	case "delete":
+		return &p.Delete
+	case "options":
+		return &p.Options
+	case "head":
+		return &p.Head
+	case "patch":
+		return &p.Patch
+	case "trace":
+		return &p.Trace
+	}
+	return nil
+}
+
+// Operation describes a single API operation on a path.
+type Operation struct {
+	Tags         []string               `yaml:"tags,omitempty"`
+	Summary      string                 `yaml:"summary,omitempty"`
+	Description  string                 `yaml:"description,omitempty"`
+	ExternalDocs *ExternalDocs          `yaml:"externalDocs,omitempty"`
+	OperationID  string                 `yaml:"operationId,omitempty"`
+	Parameters   []*Parameter           `yaml:"parameters,omitempty"`
+	RequestBody  *RequestBody           `yaml:"requestBody,omitempty"`
+	Responses    map[string]*Response   `yaml:"responses,omitempty"`
+	Callbacks    map[string]interface{} `yaml:"callbacks,omitempty"`
+	Deprecated   bool                   `yaml:"deprecated,omitempty"`
+	Security     SecurityRequirements   `yaml:"security,omitempty"`
+	Servers      []*Server              `yaml:"servers,omitempty"`
+	Extensions   map[string]interface{} `yaml:",inline"`
+}
+
+// Parameter describes a single operation parameter.
+type Parameter struct {
+	Ref             string                 `yaml:"$ref,omitempty"`
+	Name            string                 `yaml:"name,omitempty"`
+	In              string                 `yaml:"in,omitempty"`
+	Description     string                 `yaml:"description,omitempty"`
+	Required        bool                   `yaml:"required,omitempty"`
+	Deprecated      bool                   `yaml:"deprecated,omitempty"`
+	AllowEmptyValue bool                   `yaml:"allowEmptyValue,omitempty"`
+	Style           string                 `yaml:"style,omitempty"`
+	Explode         *bool                  `yaml:"explode,omitempty"`
+	Schema          *Schema                `yaml:"schema,omitempty"`
+	Example         interface{}            `yaml:"example,omitempty"`
+	Examples        map[string]*Example    `yaml:"examples,omitempty"`
+	Content         map[string]*MediaType  `yaml:"content,omitempty"`
+	Extensions      map[string]interface{} `yaml:",inline"`
+}
+
+// RequestBody describes a single request body.
+type RequestBody struct {
+	Ref         string                 `yaml:"$ref,omitempty"`
+	Description string                 `yaml:"description,omitempty"`
+	Content     map[string]*MediaType  `yaml:"content,omitempty"`
+	Required    bool                   `yaml:"required,omitempty"`
+	Extensions  map[string]interface{} `yaml:",inline"`
+}
+
+// MediaType provides schema and examples for a media type.
+type MediaType struct {
+	Schema     *Schema                `yaml:"schema,omitempty"`
+	Example    interface{}            `yaml:"example,omitempty"`
+	Examples   map[string]*Example    `yaml:"examples,omitempty"`
+	Encoding   map[string]interface{} `yaml:"encoding,omitempty"`
+	Extensions map[string]interface{} `yaml:",inline"`
+}
+
+// Example holds an example value.
+type Example struct {
+	Ref           string                 `yaml:"$ref,omitempty"`
+	Summary       string                 `yaml:"summary,omitempty"`
+	Description   string                 `yaml:"description,omitempty"`
+	Value         interface{}            `yaml:"value,omitempty"`
+	ExternalValue string                 `yaml:"externalValue,omitempty"`
+	Extensions    map[string]interface{} `yaml:",inline"`
+}
+
+// Response describes a single response of an operation.
+type Response struct {
+	Ref         string                 `yaml:"$ref,omitempty"`
+	Description string                 `yaml:"description,omitempty"`
+	Headers     map[string]*Header     `yaml:"headers,omitempty"`
+	Content     map[string]*MediaType  `yaml:"content,omitempty"`
+	Links       map[string]interface{} `yaml:"links,omitempty"`
+	Extensions  map[string]interface{} `yaml:",inline"`
+}
+
+// Header describes a response header.
+type Header struct {
+	Ref         string                 `yaml:"$ref,omitempty"`
+	Description string                 `yaml:"description,omitempty"`
+	Required    bool                   `yaml:"required,omitempty"`
+	Deprecated  bool                   `yaml:"deprecated,omitempty"`
+	Schema      *Schema                `yaml:"schema,omitempty"`
+	Example     interface{}            `yaml:"example,omitempty"`
+	Extensions  map[string]interface{} `yaml:",inline"`
+}
+
+// Components holds reusable objects referenced from other parts of the document.
+type Components struct {
+	Schemas         map[string]*Schema         `yaml:"schemas,omitempty"`
+	Responses       map[string]*Response       `yaml:"responses,omitempty"`
+	Parameters      map[string]*Parameter      `yaml:"parameters,omitempty"`
+	Examples        map[string]*Example        `yaml:"examples,omitempty"`
+	RequestBodies   map[string]*RequestBody    `yaml:"requestBodies,omitempty"`
+	Headers         map[string]*Header         `yaml:"headers,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `yaml:"securitySchemes,omitempty"`
+	Links           map[string]interface{}     `yaml:"links,omitempty"`
+	Callbacks       map[string]interface{}     `yaml:"callbacks,omitempty"`
+	PathItems       map[string]*PathItem       `yaml:"pathItems,omitempty"`
+	Extensions      map[string]interface{}     `yaml:",inline"`
+}
+
+// SecurityScheme defines a security scheme usable by the operations.
+type SecurityScheme struct {
+	Ref              string                 `yaml:"$ref,omitempty"`
+	Type             string                 `yaml:"type,omitempty"`
+	Description      string                 `yaml:"description,omitempty"`
+	Name             string                 `yaml:"name,omitempty"`
+	In               string                 `yaml:"in,omitempty"`
+	Scheme           string                 `yaml:"scheme,omitempty"`
+	BearerFormat     string                 `yaml:"bearerFormat,omitempty"`
+	Flows            *OAuthFlows            `yaml:"flows,omitempty"`
+	OpenIDConnectURL string                 `yaml:"openIdConnectUrl,omitempty"`
+	Extensions       map[string]interface{} `yaml:",inline"`
+}
+
+// OAuthFlows configures the supported OAuth flows.
+type OAuthFlows struct {
+	Implicit          *OAuthFlow             `yaml:"implicit,omitempty"`
+	Password          *OAuthFlow             `yaml:"password,omitempty"`
+	ClientCredentials *OAuthFlow             `yaml:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlow             `yaml:"authorizationCode,omitempty"`
+	Extensions        map[string]interface{} `yaml:",inline"`
+}
+
+// OAuthFlow configures a single OAuth flow.
+type OAuthFlow struct {
+	AuthorizationURL string                 `yaml:"authorizationUrl,omitempty"`
+	TokenURL         string                 `yaml:"tokenUrl,omitempty"`
+	RefreshURL       string                 `yaml:"refreshUrl,omitempty"`
+	Scopes           map[string]string      `yaml:"scopes"`
+	Extensions       map[string]interface{} `yaml:",inline"`
+}
+
+// SecurityRequirement maps the names of security schemes to the required scopes.
+type SecurityRequirement map[string][]string
+
+// SecurityRequirements lists alternative security requirements.
+// An empty, non-nil list removes the security of the document for an operation.
+type SecurityRequirements []SecurityRequirement
+
+// IsZero is used by the YAML encoder to omit nil lists but keep empty ones.
+func (s SecurityRequirements) IsZero() bool {
+	return s == nil
+}
+
+// Tag adds metadata to a tag used by operations.
+type Tag struct {
+	Name         string                 `yaml:"name"`
+	Description  string                 `yaml:"description,omitempty"`
+	ExternalDocs *ExternalDocs          `yaml:"externalDocs,omitempty"`
+	Extensions   map[string]interface{} `yaml:",inline"`
+}
+
+// ExternalDocs references external documentation.
+type ExternalDocs struct {
+	Description string                 `yaml:"description,omitempty"`
+	URL         string                 `yaml:"url"`
+	Extensions  map[string]interface{} `yaml:",inline"`
+}
+
+// YAML renders the document as YAML.
+func (d *Document) YAML() ([]byte, error) {
+	n, err := d.render()
+	if err != nil {
+		return nil, err
+	}
+	return encodeNode(n, false)
+}
+
+// JSON renders the document as indented JSON.
+func (d *Document) JSON() ([]byte, error) {
+	n, err := d.render()
+	if err != nil {
+		return nil, err
+	}
+	return encodeNode(n, true)
+}
+
+// Marshal renders the document in the format it was parsed from.
+// Documents that were not parsed are rendered as YAML.
+func (d *Document) Marshal() ([]byte, error) {
+	if d.sourceJSON {
+		return d.JSON()
+	}
+	return d.YAML()
+}
+
+// IsJSON reports whether the document was parsed from JSON.
+func (d *Document) IsJSON() bool {
+	return d.sourceJSON
+}
+
+// render encodes the document into a node. For parsed documents the changes
+// are merged into the source node, so that unchanged parts keep their
+// comments, key order and scalar styles.
+func (d *Document) render() (*yaml.Node, error) {
+	n, err := toNode(d)
+	if err != nil {
+		return nil, err
+	}
+	if d.node == nil {
+		return n, nil
+	}
+	d.node = mergeNode(d.node, n)
+	return d.node, nil
+}
+
+// Parse parses an OpenAPI document in YAML or JSON format.
+func Parse(data []byte) (*Document, error) {
+	root, err := parseNode(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing document: %w", err)
+	}
+	return decodeDocument(root, isJSON(data))
+}
+
+// decodeDocument decodes the root node of a document parsed from YAML or, if
+// asJSON is set, from JSON.
+func decodeDocument(root *yaml.Node, asJSON bool) (*Document, error) {
+	var d Document
+	if err := root.Decode(&d); err != nil {
+		return nil, fmt.Errorf("decoding document: %w", err)
+	}
+	if d.OpenAPI == "" {
+		return nil, errors.New("not an OpenAPI document: openapi field missing")
+	}
+	d.node = root
+	d.sourceJSON = asJSON
+	return &d, nil
+}
@@ -0,0 +1,177 @@
+/*
+ *  document_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const commentedSpec = `# Service description
+openapi: 3.0.3
+info:
+  title: Pets # the title
+  version: "1.0"
+x-owner: team-pets
+paths:
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+        - $ref: '#/components/parameters/ID'
+      responses:
+        "200":
+          $ref: '#/components/responses/Pet'
+components:
+  parameters:
+    ID:
+      name: id
+      in: path
+      required: true
+      schema:
+        type: integer
+  responses:
+    Pet:
+      description: A pet
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Alias'
+  schemas:
+    # Pets are animals.
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+    Alias:
+      $ref: '#/components/schemas/Pet'
+    Loop:
+      $ref: '#/components/schemas/Loop'
+`
+
+const orderedJSON = `{
+  "openapi": "3.1.0",
+  "paths": {},
+  "info": {
+    "version": "1.0",
+    "title": "Ordered"
+  },
+  "x-rate": 1.0
+}`
+
+type DocumentSuite struct {
+	suite.Suite
+}
+
+func (suite *DocumentSuite) TestParseRejectsNonOpenAPI() {
+	_, err := Parse([]byte("foo: bar"))
+	assert.Error(suite.T(), err)
+}
+
+func (suite *DocumentSuite) TestRoundTripYAML() {
+	doc, err := Parse([]byte(commentedSpec))
+	require.NoError(suite.T(), err)
+	assert.False(suite.T(), doc.IsJSON())
+	assert.Equal(suite.T(), "team-pets", doc.Extensions["x-owner"])
+
+	out, err := doc.Marshal()
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), commentedSpec, string(out))
+}
+
+func (suite *DocumentSuite) TestRoundTripJSON() {
+	doc, err := Parse([]byte(orderedJSON))
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), doc.IsJSON())
+
+	out, err := doc.Marshal()
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), orderedJSON+"\n", string(out))
+}
+
+func (suite *DocumentSuite) TestEditKeepsComments() {
+	doc, err := Parse([]byte(commentedSpec))
+	require.NoError(suite.T(), err)
+
+	doc.Info.Title = "Animals"
+	doc.Servers = []*Server{{URL: "https://api.example.com"}}
+	delete(doc.Extensions, "x-owner")
+	doc.Paths["/pets/{id}"].Get.Summary = "Get a pet"
+
+	out, err := doc.YAML()
+	require.NoError(suite.T(), err)
+	s := string(out)
+	assert.Contains(suite.T(), s, "# Service description")
+	assert.Contains(suite.T(), s, "title: Animals # the title")
+	assert.Contains(suite.T(), s, "# Pets are animals.")
+	assert.Contains(suite.T(), s, "summary: Get a pet")
+	assert.Contains(suite.T(), s, "url: https://api.example.com")
+	assert.NotContains(suite.T(), s, "x-owner")
+	assert.Less(suite.T(), strings.Index(s, "info:"), strings.Index(s, "paths:"))
+}
+
+func (suite *DocumentSuite) TestResolve() {
+	doc, err := Parse([]byte(commentedSpec))
+	require.NoError(suite.T(), err)
+	op := doc.Paths["/pets/{id}"].Get
+
+	p, err := doc.ResolveParameter(op.Parameters[0])
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "id", p.Name)
+	assert.Same(suite.T(), doc.Components.Parameters["ID"], p)
+
+	r, err := doc.ResolveResponse(op.Responses["200"])
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "A pet", r.Description)
+
+	s, err := doc.ResolveSchema(r.Content["application/json"].Schema)
+	require.NoError(suite.T(), err)
+	assert.Same(suite.T(), doc.Components.Schemas["Pet"], s)
+
+	_, err = doc.ResolveSchema(RefSchema("Loop"))
+	assert.ErrorIs(suite.T(), err, ErrCyclicRef)
+
+	_, err = doc.ResolveSchema(RefSchema("Missing"))
+	assert.ErrorIs(suite.T(), err, ErrUnresolvedRef)
+
+	_, err = doc.ResolveSchema(&Schema{Ref: "other.yaml#/Pet"})
+	assert.ErrorIs(suite.T(), err, ErrExternalRef)
+}
+
+func (suite *DocumentSuite) TestResolveRef() {
+	doc, err := Parse([]byte(commentedSpec))
+	require.NoError(suite.T(), err)
+
+	var op Operation
+	require.NoError(suite.T(), doc.ResolveRef("#/paths/~1pets~1%7Bid%7D/get", &op))
+	assert.Equal(suite.T(), "getPet", op.OperationID)
+
+	var name string
+	require.NoError(suite.T(), doc.ResolveRef("#/components/parameters/ID/name", &name))
+	assert.Equal(suite.T(), "id", name)
+}
+
+func TestDocument(t *testing.T) {
+	suite.Run(t, new(DocumentSuite))
+}
@@ -0,0 +1,321 @@
+/*
+ *  downgrade.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Downgrade31 returns a best-effort OpenAPI 3.0.3 view of the OpenAPI 3.1
+// document d, for tools that only read 3.0.
+//
+// Type arrays and null alternatives become nullable, numeric exclusive bounds
+// become their boolean form, examples arrays are reduced to their first
+// element and const becomes a single-valued enum. Keywords and fields without
+// an equivalent are dropped and described in the returned warnings.
+func Downgrade31(d *Document) (*Document, []string, error) {
+	if !strings.HasPrefix(d.OpenAPI, "3.1") {
+		return nil, nil, fmt.Errorf("not an OpenAPI 3.1 document: version %q", d.OpenAPI)
+	}
+	root, err := d.render()
+	if err != nil {
+		return nil, nil, err
+	}
+	var dg downgrader
+	out := dg.document(copyNode(root))
+	doc, err := decodeDocument(out, d.sourceJSON)
+	if err != nil {
+		return nil, nil, err
+	}
+	return doc, dg.warnings, nil
+}
+
+type downgrader struct {
+	warnings []string
+}
+
+func (dg *downgrader) warn(pointer, format string, args ...interface{}) {
+	dg.warnings = append(dg.warnings, "#"+pointer+": "+fmt.Sprintf(format, args...))
+}
+
+func (dg *downgrader) document(root *yaml.Node) *yaml.Node {
+	setMappingValue(root, "openapi", stringNode("3.0.3"))
+	for _, key := range []string{"webhooks", "jsonSchemaDialect"} {
+		if deleteMappingKey(root, key) {
+			dg.warn("/"+key, "not supported by OpenAPI 3.0, dropped")
+		}
+	}
+	if info := mappingValue(root, "info"); info != nil {
+		if deleteMappingKey(info, "summary") {
+			dg.warn("/info/summary", "not supported by OpenAPI 3.0, dropped")
+		}
+		if deleteMappingKey(mappingValue(info, "license"), "identifier") {
+			dg.warn("/info/license/identifier", "not supported by OpenAPI 3.0, dropped")
+		}
+	}
+	if components := mappingValue(root, "components"); components != nil {
+		if deleteMappingKey(components, "pathItems") {
+			dg.warn("/components/pathItems", "not supported by OpenAPI 3.0, dropped")
+		}
+		if schemes := mappingValue(components, "securitySchemes"); schemes != nil {
+			for i := 0; i+1 < len(schemes.Content); i += 2 {
+				if t := mappingValue(schemes.Content[i+1], "type"); t != nil && t.Value == "mutualTLS" {
+					dg.warn("/components/securitySchemes/"+escapePointer(schemes.Content[i].Value), "mutualTLS is not supported by OpenAPI 3.0")
+				}
+			}
+		}
+	}
+	if mappingValue(root, "paths") == nil {
+		setMappingValue(root, "paths", mappingNode())
+	}
+	dg.walk(root, "")
+	return root
+}
+
+// walk looks for schemas and reference objects outside of schemas.
+func (dg *downgrader) walk(n *yaml.Node, pointer string) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		if ref := mappingValue(n, "$ref"); ref != nil && len(n.Content) > 2 && !isPathItem(pointer) {
+			dg.warn(pointer, "fields next to $ref are not supported by OpenAPI 3.0, dropped")
+			n.Content = []*yaml.Node{stringNode("$ref"), ref}
+			return
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i].Value, n.Content[i+1]
+			p := pointer + "/" + escapePointer(key)
+			switch {
+			case key == "schema":
+				n.Content[i+1] = dg.schema(value, p)
+			case key == "schemas" && pointer == "/components":
+				for j := 0; j+1 < len(value.Content); j += 2 {
+					value.Content[j+1] = dg.schema(value.Content[j+1], p+"/"+escapePointer(value.Content[j].Value))
+				}
+			case key == "example" || key == "value" || strings.HasPrefix(key, "x-"):
+				// Literal values and extensions are not inspected.
+			default:
+				dg.walk(value, p)
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range n.Content {
+			dg.walk(item, pointer+"/"+strconv.Itoa(i))
+		}
+	}
+}
+
+// isPathItem reports whether pointer addresses a path item, which may have
+// fields next to $ref in 3.0 as well.
+func isPathItem(pointer string) bool {
+	rest, ok := strings.CutPrefix(pointer, "/paths/")
+	return ok && !strings.Contains(rest, "/")
+}
+
+// unsupportedKeywords are JSON Schema keywords OpenAPI 3.0 schemas lack.
+var unsupportedKeywords = []string{
+	"$schema", "$id", "$anchor", "$dynamicAnchor", "$dynamicRef", "$defs", "$comment",
+	"prefixItems", "contains", "minContains", "maxContains", "if", "then", "else",
+	"dependentSchemas", "dependentRequired", "patternProperties", "propertyNames",
+	"unevaluatedProperties", "unevaluatedItems", "contentSchema",
+}
+
+// subschemaKeys hold a single schema within a schema.
+var subschemaKeys = []string{"items", "not", "additionalProperties"}
+
+// schema returns the 3.0 form of the schema s.
+func (dg *downgrader) schema(s *yaml.Node, pointer string) *yaml.Node {
+	switch {
+	case s.Kind == yaml.ScalarNode && s.ShortTag() == "!!bool":
+		// Boolean schemas are expressed as the empty schema and its negation.
+		if s.Value == "true" {
+			return mappingNode()
+		}
+		n := mappingNode()
+		appendPair(n, stringNode("not"), mappingNode())
+		return n
+	case s.Kind != yaml.MappingNode:
+		return s
+	}
+
+	if ref := mappingValue(s, "$ref"); ref != nil && len(s.Content) > 2 {
+		// Keywords next to $ref are ignored in 3.0, so the reference is
+		// combined with them instead.
+		deleteMappingKey(s, "$ref")
+		allOf := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{refNode(ref.Value)}}
+		setMappingValue(s, "allOf", allOf)
+	}
+
+	for _, key := range unsupportedKeywords {
+		if deleteMappingKey(s, key) {
+			dg.warn(pointer+"/"+escapePointer(key), "not supported by OpenAPI 3.0, dropped")
+		}
+	}
+	dg.contentKeywords(s, pointer)
+	dg.types(s, pointer)
+	dg.exclusiveBound(s, "exclusiveMinimum", "minimum", pointer)
+	dg.exclusiveBound(s, "exclusiveMaximum", "maximum", pointer)
+
+	if examples := mappingValue(s, "examples"); examples != nil && examples.Kind == yaml.SequenceNode {
+		deleteMappingKey(s, "examples")
+		if len(examples.Content) > 0 && mappingValue(s, "example") == nil {
+			setMappingValue(s, "example", examples.Content[0])
+		}
+		if len(examples.Content) > 1 {
+			dg.warn(pointer+"/examples", "only the first of %d examples is kept", len(examples.Content))
+		}
+	}
+	if c := mappingValue(s, "const"); c != nil {
+		deleteMappingKey(s, "const")
+		setMappingValue(s, "enum", &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{c}})
+	}
+
+	nullAlternatives(s)
+	for _, key := range subschemaKeys {
+		if v := mappingValue(s, key); v != nil && !(key == "additionalProperties" && v.Kind == yaml.ScalarNode) {
+			setMappingValue(s, key, dg.schema(v, pointer+"/"+key))
+		}
+	}
+	if props := mappingValue(s, "properties"); props != nil {
+		for i := 0; i+1 < len(props.Content); i += 2 {
+			props.Content[i+1] = dg.schema(props.Content[i+1], pointer+"/properties/"+escapePointer(props.Content[i].Value))
+		}
+	}
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		if list := mappingValue(s, key); list != nil {
+			for i, item := range list.Content {
+				list.Content[i] = dg.schema(item, pointer+"/"+key+"/"+strconv.Itoa(i))
+			}
+		}
+	}
+	return s
+}
+
+// types turns type arrays into a single type, using nullable for "null" and
+// anyOf for several other types.
+func (dg *downgrader) types(s *yaml.Node, pointer string) {
+	t := mappingValue(s, "type")
+	if t == nil {
+		return
+	}
+	var types []string
+	if t.Kind == yaml.SequenceNode {
+		types = stringList(t)
+	} else {
+		types = []string{t.Value}
+	}
+	var nonNull []string
+	nullable := false
+	for _, typ := range types {
+		if typ == "null" {
+			nullable = true
+		} else {
+			nonNull = append(nonNull, typ)
+		}
+	}
+	switch len(nonNull) {
+	case 0:
+		deleteMappingKey(s, "type")
+		dg.warn(pointer+"/type", "the null type has no equivalent, using nullable")
+	case 1:
+		setMappingValue(s, "type", stringNode(nonNull[0]))
+	default:
+		deleteMappingKey(s, "type")
+		anyOf := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, typ := range nonNull {
+			alt := mappingNode()
+			appendPair(alt, stringNode("type"), stringNode(typ))
+			anyOf.Content = append(anyOf.Content, alt)
+		}
+		setMappingValue(s, "anyOf", anyOf)
+	}
+	if nullable {
+		setMappingValue(s, "nullable", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"})
+	}
+}
+
+// nullAlternatives removes {type: null} from anyOf and oneOf in favor of nullable.
+func nullAlternatives(s *yaml.Node) {
+	for _, key := range []string{"anyOf", "oneOf"} {
+		list := mappingValue(s, key)
+		if list == nil {
+			continue
+		}
+		kept := list.Content[:0]
+		nullable := false
+		for _, alt := range list.Content {
+			if isNullSchema(alt) {
+				nullable = true
+				continue
+			}
+			kept = append(kept, alt)
+		}
+		list.Content = kept
+		if nullable {
+			setMappingValue(s, "nullable", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"})
+		}
+	}
+}
+
+// isNullSchema reports whether s is {type: null}.
+func isNullSchema(s *yaml.Node) bool {
+	if s.Kind != yaml.MappingNode || len(s.Content) != 2 {
+		return false
+	}
+	t := mappingValue(s, "type")
+	if t != nil && t.Kind == yaml.SequenceNode && len(t.Content) == 1 {
+		t = t.Content[0]
+	}
+	return t != nil && t.Value == "null"
+}
+
+// exclusiveBound converts a numeric exclusive bound into the boolean form of 3.0.
+func (dg *downgrader) exclusiveBound(s *yaml.Node, exclusive, inclusive, pointer string) {
+	v := mappingValue(s, exclusive)
+	if v == nil || v.ShortTag() == "!!bool" {
+		return
+	}
+	if other := mappingValue(s, inclusive); other != nil {
+		dg.warn(pointer+"/"+exclusive, "combined with %s, keeping the exclusive bound only", inclusive)
+	}
+	setMappingValue(s, inclusive, v)
+	setMappingValue(s, exclusive, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"})
+}
+
+// contentKeywords maps contentEncoding and contentMediaType to the formats of 3.0.
+func (dg *downgrader) contentKeywords(s *yaml.Node, pointer string) {
+	encoding := mappingValue(s, "contentEncoding")
+	mediaType := mappingValue(s, "contentMediaType")
+	if encoding == nil && mediaType == nil {
+		return
+	}
+	deleteMappingKey(s, "contentEncoding")
+	deleteMappingKey(s, "contentMediaType")
+	switch {
+	case encoding != nil && encoding.Value == "base64":
+		setMappingValue(s, "format", stringNode("byte"))
+	case encoding == nil && mediaType.Value == "application/octet-stream":
+		setMappingValue(s, "format", stringNode("binary"))
+	default:
+		dg.warn(pointer, "contentEncoding and contentMediaType are not supported by OpenAPI 3.0, dropped")
+	}
+}
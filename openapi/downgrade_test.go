@@ -0,0 +1,107 @@
+/*
+ *  downgrade_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type DowngradeSuite struct {
+	suite.Suite
+	doc      *Document
+	warnings []string
+}
+
+func (suite *DowngradeSuite) SetupTest() {
+	data, err := os.ReadFile("testdata/openapi31.yaml")
+	require.NoError(suite.T(), err)
+	doc, err := Parse(data)
+	require.NoError(suite.T(), err)
+	suite.doc, suite.warnings, err = Downgrade31(doc)
+	require.NoError(suite.T(), err)
+}
+
+func (suite *DowngradeSuite) TestDocument() {
+	assert.Equal(suite.T(), "3.0.3", suite.doc.OpenAPI)
+	assert.Empty(suite.T(), suite.doc.Webhooks)
+	assert.Empty(suite.T(), suite.doc.JSONSchemaDialect)
+	assert.Empty(suite.T(), suite.doc.Info.Summary)
+	assert.Empty(suite.T(), suite.doc.Info.License.Identifier)
+}
+
+func (suite *DowngradeSuite) TestSchemas() {
+	limit := suite.doc.Paths["/pets"].Get.Parameters[0].Schema
+	assert.Equal(suite.T(), true, limit.ExclusiveMinimum)
+	require.NotNil(suite.T(), limit.Minimum)
+	assert.Zero(suite.T(), *limit.Minimum)
+
+	pet := suite.doc.Components.Schemas["Pet"].Properties
+	assert.Equal(suite.T(), TypeOf("string"), pet["name"].Type)
+	assert.True(suite.T(), pet["name"].Nullable)
+	assert.Equal(suite.T(), "Rex", pet["name"].Example)
+	assert.Empty(suite.T(), pet["name"].Examples)
+
+	assert.Equal(suite.T(), []interface{}{"dog"}, pet["kind"].Enum)
+	assert.Nil(suite.T(), pet["kind"].Const)
+
+	assert.Empty(suite.T(), pet["id"].Type)
+	assert.Len(suite.T(), pet["id"].AnyOf, 2)
+
+	assert.Empty(suite.T(), pet["owner"].Ref)
+	require.Len(suite.T(), pet["owner"].AllOf, 1)
+	assert.Equal(suite.T(), "#/components/schemas/Owner", pet["owner"].AllOf[0].Ref)
+	assert.Equal(suite.T(), "The owner", pet["owner"].Description)
+
+	assert.Equal(suite.T(), "byte", pet["photo"].Format)
+
+	require.Len(suite.T(), pet["parent"].AnyOf, 1)
+	assert.True(suite.T(), pet["parent"].Nullable)
+
+	_, isBool := pet["extra"].Bool()
+	assert.False(suite.T(), isBool)
+}
+
+func (suite *DowngradeSuite) TestWarnings() {
+	all := strings.Join(suite.warnings, "\n")
+	for _, w := range []string{
+		"#/webhooks", "#/jsonSchemaDialect", "#/info/summary", "#/info/license/identifier",
+		"#/components/schemas/Pet/properties/tags/prefixItems",
+		"#/components/schemas/Pet/properties/name/examples",
+	} {
+		assert.Contains(suite.T(), all, w+": ")
+	}
+	assert.NotContains(suite.T(), all, "parent")
+}
+
+func (suite *DowngradeSuite) TestRejects30() {
+	doc, err := Parse([]byte(commentedSpec))
+	require.NoError(suite.T(), err)
+	_, _, err = Downgrade31(doc)
+	assert.Error(suite.T(), err)
+}
+
+func TestDowngrade(t *testing.T) {
+	suite.Run(t, new(DowngradeSuite))
+}
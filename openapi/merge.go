@@ -0,0 +1,455 @@
+/*
+ *  merge.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MergeSource is a document taking part in a merge.
+type MergeSource struct {
+	// Name identifies the source. It is used to rename conflicting components
+	// and operationIds, for example "Pet" of the source "billing" becomes
+	// "billing.Pet".
+	Name     string
+	Document *Document
+	// PathPrefix is prepended to all paths of the document, as in "/billing".
+	// The servers of the document are kept for its paths only without a prefix,
+	// since they would not serve the prefixed paths.
+	PathPrefix string
+	// TagPrefix is prepended to all tags of the document.
+	TagPrefix string
+}
+
+// componentKinds lists the kinds of components in the order of the specification.
+var componentKinds = []string{
+	"schemas", "responses", "parameters", "examples", "requestBodies", "headers",
+	"securitySchemes", "links", "callbacks", "pathItems",
+}
+
+// Merge combines several documents into one with the given info.
+//
+// Components that are identical in several sources are kept once, while
+// conflicting ones are renamed and the references to them rewritten. So are
+// operationIds used by several sources, along with the links naming them. Global
+// security requirements and servers of a source move to its operations and
+// path items. Documents of OpenAPI 3.0 and 3.1 cannot be mixed, and two
+// sources defining the same operation result in an error.
+func Merge(info *Info, sources ...MergeSource) (*Document, error) {
+	if len(sources) == 0 {
+		return nil, errors.New("nothing to merge")
+	}
+	infoNode, err := toNode(info)
+	if err != nil {
+		return nil, err
+	}
+	m := &merger{
+		out:        mappingNode(),
+		paths:      mappingNode(),
+		components: mappingNode(),
+		tags:       &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"},
+		operations: make(map[string]bool),
+	}
+	for _, src := range sources {
+		if err := m.add(src); err != nil {
+			return nil, fmt.Errorf("merging %s: %w", src.Name, err)
+		}
+	}
+	appendPair(m.out, stringNode("openapi"), stringNode(m.version))
+	appendPair(m.out, stringNode("info"), infoNode)
+	appendPair(m.out, stringNode("paths"), m.paths)
+	if m.webhooks != nil {
+		appendPair(m.out, stringNode("webhooks"), m.webhooks)
+	}
+	if len(m.components.Content) > 0 {
+		appendPair(m.out, stringNode("components"), m.components)
+	}
+	if len(m.tags.Content) > 0 {
+		appendPair(m.out, stringNode("tags"), m.tags)
+	}
+	return decodeDocument(m.out, false)
+}
+
+type merger struct {
+	version    string
+	out        *yaml.Node
+	paths      *yaml.Node
+	webhooks   *yaml.Node
+	components *yaml.Node
+	tags       *yaml.Node
+	operations map[string]bool // The operationIds merged so far
+}
+
+func (m *merger) add(src MergeSource) error {
+	if src.Document == nil {
+		return errors.New("no document")
+	}
+	n, err := toNode(src.Document)
+	if err != nil {
+		return err
+	}
+	version := src.Document.OpenAPI
+	switch {
+	case m.version == "":
+		m.version = version
+	case strings.HasPrefix(m.version, "3.0") != strings.HasPrefix(version, "3.0"):
+		return fmt.Errorf("cannot merge OpenAPI %s into %s", version, m.version)
+	case version > m.version:
+		m.version = version
+	}
+
+	renames := m.renames(src.Name, mappingValue(n, "components"))
+	rewriteRefs(n, renames)
+	if schemes := renamesOf(renames, "securitySchemes"); len(schemes) > 0 {
+		renameSecurity(n, schemes)
+	}
+	m.addComponents(mappingValue(n, "components"), renames)
+	m.renameOperations(src.Name, n)
+
+	security := mappingValue(n, "security")
+	var servers *yaml.Node
+	if src.PathPrefix == "" {
+		servers = mappingValue(n, "servers")
+	}
+	if paths := mappingValue(n, "paths"); paths != nil {
+		prefix := strings.TrimSuffix(src.PathPrefix, "/")
+		for i := 0; i+1 < len(paths.Content); i += 2 {
+			key, item := paths.Content[i], paths.Content[i+1]
+			if strings.HasPrefix(key.Value, "x-") {
+				continue
+			}
+			prepareOperations(item, src.TagPrefix, security)
+			if servers != nil && mappingValue(item, "servers") == nil {
+				setMappingValue(item, "servers", servers)
+			}
+			if err := mergePathItem(m.paths, prefix+key.Value, item); err != nil {
+				return err
+			}
+		}
+	}
+	if webhooks := mappingValue(n, "webhooks"); webhooks != nil {
+		if m.webhooks == nil {
+			m.webhooks = mappingNode()
+		}
+		for i := 0; i+1 < len(webhooks.Content); i += 2 {
+			item := webhooks.Content[i+1]
+			prepareOperations(item, src.TagPrefix, security)
+			if err := mergePathItem(m.webhooks, webhooks.Content[i].Value, item); err != nil {
+				return err
+			}
+		}
+	}
+	if tags := mappingValue(n, "tags"); tags != nil {
+		for _, tag := range tags.Content {
+			name := mappingValue(tag, "name")
+			if name == nil {
+				continue
+			}
+			name.Value = src.TagPrefix + name.Value
+			if !m.hasTag(name.Value) {
+				m.tags.Content = append(m.tags.Content, tag)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *merger) hasTag(name string) bool {
+	for _, tag := range m.tags.Content {
+		if v := mappingValue(tag, "name"); v != nil && v.Value == name {
+			return true
+		}
+	}
+	return false
+}
+
+// renames determines the components of a source that conflict with the
+// components merged so far, and returns their new names keyed by kind and name.
+func (m *merger) renames(source string, components *yaml.Node) map[string]string {
+	renames := make(map[string]string)
+	if components == nil {
+		return renames
+	}
+	// Renaming a component changes the components referring to it, so
+	// this is repeated until no further conflicts arise.
+	for changed := true; changed; {
+		changed = false
+		for _, kind := range componentKinds {
+			defs := mappingValue(components, kind)
+			existing := mappingValue(m.components, kind)
+			if defs == nil || existing == nil {
+				continue
+			}
+			for i := 0; i+1 < len(defs.Content); i += 2 {
+				name := defs.Content[i].Value
+				key := kind + "/" + name
+				if _, ok := renames[key]; ok {
+					continue
+				}
+				other := mappingValue(existing, name)
+				if other == nil {
+					continue
+				}
+				def := copyNode(defs.Content[i+1])
+				rewriteRefs(def, renames)
+				if !nodesEqual(def, other) {
+					renames[key] = uniqueName(source+"."+name, existing, defs)
+					changed = true
+				}
+			}
+		}
+	}
+	return renames
+}
+
+// renameOperations renames the operationIds of a source that are used by the
+// sources merged so far. Since links name operations by operationId as well,
+// all values of operationId fields within n are renamed.
+func (m *merger) renameOperations(source string, n *yaml.Node) {
+	ids := make(map[string]bool)
+	collectOperationIds(n, ids)
+	renames := make(map[string]string)
+	for id := range ids {
+		if !m.operations[id] {
+			continue
+		}
+		renamed := source + "." + id
+		for i := 2; m.operations[renamed] || ids[renamed]; i++ {
+			renamed = source + "." + id + "_" + strconv.Itoa(i)
+		}
+		renames[id] = renamed
+	}
+	if len(renames) > 0 {
+		renameOperationIds(n, renames)
+	}
+	for id := range ids {
+		if renamed, ok := renames[id]; ok {
+			id = renamed
+		}
+		m.operations[id] = true
+	}
+}
+
+// collectOperationIds adds the values of all operationId fields within n to ids.
+func collectOperationIds(n *yaml.Node, ids map[string]bool) {
+	walkOperationIds(n, func(id *yaml.Node) { ids[id.Value] = true })
+}
+
+func renameOperationIds(n *yaml.Node, renames map[string]string) {
+	walkOperationIds(n, func(id *yaml.Node) {
+		if renamed, ok := renames[id.Value]; ok {
+			id.Value = renamed
+		}
+	})
+}
+
+func walkOperationIds(n *yaml.Node, f func(*yaml.Node)) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			if key.Value == "operationId" && value.Kind == yaml.ScalarNode {
+				f(value)
+				continue
+			}
+			walkOperationIds(value, f)
+		}
+	case yaml.SequenceNode:
+		for _, item := range n.Content {
+			walkOperationIds(item, f)
+		}
+	}
+}
+
+// uniqueName returns name, or name with a number appended, so that it is not
+// used in any of the given mappings.
+func uniqueName(name string, mappings ...*yaml.Node) string {
+	candidate := name
+	for i := 2; ; i++ {
+		taken := false
+		for _, m := range mappings {
+			if mappingValue(m, candidate) != nil {
+				taken = true
+			}
+		}
+		if !taken {
+			return candidate
+		}
+		candidate = name + "_" + strconv.Itoa(i)
+	}
+}
+
+// renamesOf returns the renames of one kind of component, keyed by old name.
+func renamesOf(renames map[string]string, kind string) map[string]string {
+	names := make(map[string]string)
+	for key, name := range renames {
+		if old, ok := strings.CutPrefix(key, kind+"/"); ok {
+			names[old] = name
+		}
+	}
+	return names
+}
+
+// addComponents adds the components of a source, skipping those that were found identical.
+func (m *merger) addComponents(components *yaml.Node, renames map[string]string) {
+	if components == nil {
+		return
+	}
+	for _, kind := range componentKinds {
+		defs := mappingValue(components, kind)
+		if defs == nil {
+			continue
+		}
+		target := ensureMapping(m.components, kind)
+		for i := 0; i+1 < len(defs.Content); i += 2 {
+			name := defs.Content[i].Value
+			if renamed, ok := renames[kind+"/"+name]; ok {
+				name = renamed
+			}
+			if mappingValue(target, name) == nil {
+				appendPair(target, stringNode(name), defs.Content[i+1])
+			}
+		}
+	}
+}
+
+// rewriteRefs rewrites the references to renamed components within n.
+func rewriteRefs(n *yaml.Node, renames map[string]string) {
+	if len(renames) == 0 {
+		return
+	}
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			if value.Kind == yaml.ScalarNode && (key.Value == "$ref" || strings.HasPrefix(value.Value, "#/components/")) {
+				value.Value = renameRef(value.Value, renames)
+				continue
+			}
+			rewriteRefs(value, renames)
+		}
+	case yaml.SequenceNode:
+		for _, item := range n.Content {
+			rewriteRefs(item, renames)
+		}
+	}
+}
+
+func renameRef(ref string, renames map[string]string) string {
+	rest, ok := strings.CutPrefix(ref, "#/components/")
+	if !ok {
+		return ref
+	}
+	kind, tail, _ := strings.Cut(rest, "/")
+	name, sub, hasSub := strings.Cut(tail, "/")
+	renamed, ok := renames[kind+"/"+unescapePointer(name)]
+	if !ok {
+		return ref
+	}
+	ref = "#/components/" + kind + "/" + escapePointer(renamed)
+	if hasSub {
+		ref += "/" + sub
+	}
+	return ref
+}
+
+// renameSecurity renames the security schemes used by the security requirements within the document.
+func renameSecurity(n *yaml.Node, schemes map[string]string) {
+	rename := func(requirements *yaml.Node) {
+		if requirements == nil {
+			return
+		}
+		for _, req := range requirements.Content {
+			for i := 0; i+1 < len(req.Content); i += 2 {
+				if renamed, ok := schemes[req.Content[i].Value]; ok {
+					req.Content[i].Value = renamed
+				}
+			}
+		}
+	}
+	rename(mappingValue(n, "security"))
+	for _, key := range []string{"paths", "webhooks"} {
+		items := mappingValue(n, key)
+		if items == nil {
+			continue
+		}
+		for i := 1; i < len(items.Content); i += 2 {
+			for _, method := range Methods {
+				rename(mappingValue(mappingValue(items.Content[i], method), "security"))
+			}
+		}
+	}
+}
+
+// prepareOperations prefixes the tags of the operations of item and applies
+// the global security requirements to those without their own.
+func prepareOperations(item *yaml.Node, tagPrefix string, security *yaml.Node) {
+	for _, method := range Methods {
+		op := mappingValue(item, method)
+		if op == nil {
+			continue
+		}
+		if tags := mappingValue(op, "tags"); tags != nil && tagPrefix != "" {
+			for _, tag := range tags.Content {
+				tag.Value = tagPrefix + tag.Value
+			}
+		}
+		if security != nil && mappingValue(op, "security") == nil {
+			setMappingValue(op, "security", copyNode(security))
+		}
+	}
+}
+
+// mergePathItem adds item to paths under key, combining it with an existing
+// path item of different operations.
+func mergePathItem(paths *yaml.Node, key string, item *yaml.Node) error {
+	existing := mappingValue(paths, key)
+	if existing == nil {
+		appendPair(paths, stringNode(key), item)
+		return nil
+	}
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		field, value := item.Content[i].Value, item.Content[i+1]
+		other := mappingValue(existing, field)
+		switch {
+		case other == nil:
+			appendPair(existing, item.Content[i], value)
+		case isMethod(field):
+			return fmt.Errorf("%s %s is defined more than once", strings.ToUpper(field), key)
+		case !nodesEqual(value, other):
+			return fmt.Errorf("%s: conflicting %s", key, field)
+		}
+	}
+	return nil
+}
+
+// nodesEqual reports whether a and b hold the same data, regardless of style and comments.
+func nodesEqual(a, b *yaml.Node) bool {
+	var va, vb interface{}
+	if a.Decode(&va) != nil || b.Decode(&vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,247 @@
+/*
+ *  merge_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const petsService = `openapi: 3.0.3
+info:
+  title: Pets
+  version: "1.0"
+servers:
+  - url: https://pets.example.com
+security:
+  - key: []
+tags:
+  - name: pets
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      responses:
+        "200":
+          description: The pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+        default:
+          $ref: '#/components/responses/Error'
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+        owner:
+          $ref: '#/components/schemas/Owner'
+    Owner:
+      type: object
+      properties:
+        name:
+          type: string
+    Error:
+      type: object
+      properties:
+        message:
+          type: string
+  responses:
+    Error:
+      description: An error
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Error'
+  securitySchemes:
+    key:
+      type: apiKey
+      name: X-API-Key
+      in: header
+`
+
+const storeService = `openapi: 3.0.3
+info:
+  title: Store
+  version: "2.0"
+tags:
+  - name: pets
+    description: Pets for sale
+paths:
+  /orders:
+    post:
+      operationId: createOrder
+      tags: [orders]
+      security:
+        - key: []
+      responses:
+        "200":
+          description: The order
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Order'
+        default:
+          description: An error
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Error'
+components:
+  schemas:
+    Order:
+      type: object
+      properties:
+        pet:
+          $ref: '#/components/schemas/Pet'
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+        owner:
+          $ref: '#/components/schemas/Owner'
+    Owner:
+      type: object
+      properties:
+        id:
+          type: integer
+    Error:
+      type: object
+      properties:
+        message:
+          type: string
+  securitySchemes:
+    key:
+      type: apiKey
+      name: api_key
+      in: query
+`
+
+type MergeSuite struct {
+	suite.Suite
+	pets, store *Document
+}
+
+func (suite *MergeSuite) SetupTest() {
+	var err error
+	suite.pets, err = Parse([]byte(petsService))
+	require.NoError(suite.T(), err)
+	suite.store, err = Parse([]byte(storeService))
+	require.NoError(suite.T(), err)
+}
+
+func (suite *MergeSuite) TestMerge() {
+	doc, err := Merge(&Info{Title: "Portal", Version: "1.0"},
+		MergeSource{Name: "pets", Document: suite.pets},
+		MergeSource{Name: "store", Document: suite.store, PathPrefix: "/store/", TagPrefix: "store-"})
+	require.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), "3.0.3", doc.OpenAPI)
+	assert.Equal(suite.T(), "Portal", doc.Info.Title)
+	require.Contains(suite.T(), doc.Paths, "/pets")
+	require.Contains(suite.T(), doc.Paths, "/store/orders")
+
+	pets := doc.Paths["/pets"]
+	require.Len(suite.T(), pets.Servers, 1)
+	assert.Equal(suite.T(), "https://pets.example.com", pets.Servers[0].URL)
+	assert.Equal(suite.T(), SecurityRequirements{{"key": {}}}, pets.Get.Security)
+
+	orders := doc.Paths["/store/orders"].Post
+	assert.Empty(suite.T(), doc.Paths["/store/orders"].Servers)
+	assert.Equal(suite.T(), []string{"store-orders"}, orders.Tags)
+	assert.Equal(suite.T(), SecurityRequirements{{"store.key": {}}}, orders.Security)
+
+	schemas := doc.Components.Schemas
+	assert.Len(suite.T(), schemas, 6)
+	assert.Contains(suite.T(), schemas, "Error", "identical components are kept once")
+	assert.Contains(suite.T(), schemas, "store.Owner")
+	assert.Equal(suite.T(), "#/components/schemas/store.Owner", schemas["store.Pet"].Properties["owner"].Ref)
+	assert.Equal(suite.T(), "#/components/schemas/store.Pet", schemas["Order"].Properties["pet"].Ref)
+	assert.Equal(suite.T(), "#/components/schemas/Owner", schemas["Pet"].Properties["owner"].Ref)
+	assert.Equal(suite.T(), "#/components/schemas/Error",
+		orders.Responses["default"].Content["application/json"].Schema.Ref)
+
+	assert.Contains(suite.T(), doc.Components.SecuritySchemes, "key")
+	assert.Contains(suite.T(), doc.Components.SecuritySchemes, "store.key")
+
+	var names []string
+	for _, tag := range doc.Tags {
+		names = append(names, tag.Name)
+	}
+	assert.Equal(suite.T(), []string{"pets", "store-pets"}, names)
+}
+
+func (suite *MergeSuite) TestDuplicateOperation() {
+	_, err := Merge(&Info{Title: "Portal", Version: "1.0"},
+		MergeSource{Name: "a", Document: suite.pets},
+		MergeSource{Name: "b", Document: suite.pets})
+	assert.ErrorContains(suite.T(), err, "GET /pets")
+}
+
+func (suite *MergeSuite) TestDuplicateOperationId() {
+	service := `openapi: 3.0.3
+info: {title: Service, version: "1.0"}
+paths:
+  /items:
+    get:
+      operationId: list
+      responses:
+        "200":
+          description: Items
+          links:
+            first:
+              operationId: list
+`
+	a, err := Parse([]byte(service))
+	require.NoError(suite.T(), err)
+	b, err := Parse([]byte(service))
+	require.NoError(suite.T(), err)
+	doc, err := Merge(&Info{Title: "Portal", Version: "1.0"},
+		MergeSource{Name: "a", Document: a, PathPrefix: "/a"},
+		MergeSource{Name: "b", Document: b, PathPrefix: "/b"})
+	require.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), "list", doc.Paths["/a/items"].Get.OperationID)
+	renamed := doc.Paths["/b/items"].Get
+	assert.Equal(suite.T(), "b.list", renamed.OperationID)
+	link := renamed.Responses["200"].Links["first"].(map[string]interface{})
+	assert.Equal(suite.T(), "b.list", link["operationId"], "links follow the renamed operation")
+}
+
+func (suite *MergeSuite) TestMixedVersions() {
+	suite.store.OpenAPI = "3.1.0"
+	_, err := Merge(&Info{Title: "Portal", Version: "1.0"},
+		MergeSource{Name: "pets", Document: suite.pets},
+		MergeSource{Name: "store", Document: suite.store})
+	assert.Error(suite.T(), err)
+}
+
+func TestMerge(t *testing.T) {
+	suite.Run(t, new(MergeSuite))
+}
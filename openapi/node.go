@@ -0,0 +1,425 @@
+/*
+ *  node.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// isJSON reports whether data looks like a JSON document rather than YAML.
+func isJSON(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \t\r\n\ufeff")
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+// parseNode parses YAML or JSON data into the mapping node at the root of the document.
+func parseNode(data []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, errors.New("empty document")
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: document root is not a mapping", root.Line)
+	}
+	return root, nil
+}
+
+// encodeNode renders node as YAML or, if asJSON is set, as indented JSON.
+func encodeNode(node *yaml.Node, asJSON bool) ([]byte, error) {
+	var buf bytes.Buffer
+	if asJSON {
+		if err := writeJSON(&buf, node); err != nil {
+			return nil, err
+		}
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, buf.Bytes(), "", "  "); err != nil {
+			return nil, err
+		}
+		indented.WriteByte('\n')
+		return indented.Bytes(), nil
+	}
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeJSON writes node as compact JSON, keeping the order of mapping keys.
+func writeJSON(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeJSON(buf, node.Content[0])
+	case yaml.AliasNode:
+		return writeJSON(buf, node.Alias)
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(node.Content[i].Value)
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := writeJSON(buf, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case yaml.ScalarNode:
+		return writeScalar(buf, node)
+	default:
+		return fmt.Errorf("line %d: unsupported node kind %d", node.Line, node.Kind)
+	}
+	return nil
+}
+
+// jsonNumber matches the number grammar of JSON.
+var jsonNumber = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+func writeScalar(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.ShortTag() {
+	case "!!null":
+		buf.WriteString("null")
+		return nil
+	case "!!int", "!!float":
+		if jsonNumber.MatchString(node.Value) {
+			// Keep the number as written, such as 1.0 or 1e3.
+			buf.WriteString(node.Value)
+			return nil
+		}
+		fallthrough
+	case "!!bool":
+		var v interface{}
+		if err := node.Decode(&v); err != nil {
+			return err
+		}
+		if f, ok := v.(float64); ok && (math.IsInf(f, 0) || math.IsNaN(f)) {
+			// JSON cannot represent these, so they are kept as strings.
+			v = node.Value
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		return nil
+	}
+	b, _ := json.Marshal(node.Value)
+	buf.Write(b)
+	return nil
+}
+
+// mappingValue returns the value of key in the mapping node m, or nil.
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue sets key in the mapping node m to value, appending it if it does not exist.
+func setMappingValue(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content, stringNode(key), value)
+}
+
+// ensureMapping returns the mapping stored under key in m, creating it if necessary.
+func ensureMapping(m *yaml.Node, key string) *yaml.Node {
+	v := mappingValue(m, key)
+	if v == nil || v.Kind != yaml.MappingNode {
+		v = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		setMappingValue(m, key, v)
+	}
+	return v
+}
+
+func stringNode(s string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s}
+}
+
+// toNode encodes v into a YAML node.
+func toNode(v interface{}) (*yaml.Node, error) {
+	var n yaml.Node
+	if err := n.Encode(v); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// mergeNode merges updated into orig and returns the result. Mapping keys
+// keep the order of orig, keys missing from updated are removed and new keys
+// are appended. Comments of orig are retained, and so are scalars whose value
+// did not change, including their quoting style.
+func mergeNode(orig, updated *yaml.Node) *yaml.Node {
+	switch {
+	case orig == nil:
+		return updated
+	case updated == nil:
+		return nil
+	case orig.Kind == yaml.DocumentNode && len(orig.Content) == 1:
+		if updated.Kind == yaml.DocumentNode && len(updated.Content) == 1 {
+			updated = updated.Content[0]
+		}
+		orig.Content[0] = mergeNode(orig.Content[0], updated)
+		return orig
+	case orig.Kind != updated.Kind:
+		copyComments(updated, orig)
+		return updated
+	}
+
+	switch orig.Kind {
+	case yaml.MappingNode:
+		values := make(map[string]*yaml.Node, len(updated.Content)/2)
+		for i := 0; i+1 < len(updated.Content); i += 2 {
+			values[updated.Content[i].Value] = updated.Content[i+1]
+		}
+		seen := make(map[string]bool, len(values))
+		content := make([]*yaml.Node, 0, len(updated.Content))
+		for i := 0; i+1 < len(orig.Content); i += 2 {
+			key := orig.Content[i].Value
+			v, ok := values[key]
+			if !ok || seen[key] {
+				continue
+			}
+			seen[key] = true
+			content = append(content, orig.Content[i], mergeNode(orig.Content[i+1], v))
+		}
+		for i := 0; i+1 < len(updated.Content); i += 2 {
+			if !seen[updated.Content[i].Value] {
+				content = append(content, updated.Content[i], updated.Content[i+1])
+			}
+		}
+		orig.Content = content
+		return orig
+	case yaml.SequenceNode:
+		content := make([]*yaml.Node, len(updated.Content))
+		for i, item := range updated.Content {
+			if i < len(orig.Content) {
+				content[i] = mergeNode(orig.Content[i], item)
+			} else {
+				content[i] = item
+			}
+		}
+		orig.Content = content
+		return orig
+	case yaml.ScalarNode:
+		if scalarEqual(orig, updated) {
+			return orig
+		}
+		copyComments(updated, orig)
+		return updated
+	}
+	return updated
+}
+
+// scalarEqual reports whether two scalars represent the same value, even if
+// they are written differently, such as 1.0 and 1.
+func scalarEqual(a, b *yaml.Node) bool {
+	if a.Value == b.Value {
+		return true
+	}
+	if a.ShortTag() == "!!str" || b.ShortTag() == "!!str" {
+		return false
+	}
+	var va, vb interface{}
+	if a.Decode(&va) != nil || b.Decode(&vb) != nil {
+		return false
+	}
+	fa, okA := toFloat(va)
+	fb, okB := toFloat(vb)
+	return okA && okB && fa == fb
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func copyComments(dst, src *yaml.Node) {
+	dst.HeadComment = src.HeadComment
+	dst.LineComment = src.LineComment
+	dst.FootComment = src.FootComment
+}
+
+// escapePointer escapes a JSON pointer reference token.
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+// unescapePointer reverses escapePointer.
+func unescapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+}
+
+// pointerLookup returns the node the JSON pointer addresses within root.
+// Reference tokens may additionally be percent-encoded, as they are in URI fragments.
+func pointerLookup(root *yaml.Node, pointer string) (*yaml.Node, error) {
+	cur := root
+	if pointer == "" {
+		return cur, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		if unescaped, err := url.PathUnescape(token); err == nil {
+			token = unescaped
+		}
+		token = unescapePointer(token)
+		for cur.Kind == yaml.AliasNode {
+			cur = cur.Alias
+		}
+		switch cur.Kind {
+		case yaml.MappingNode:
+			next := mappingValue(cur, token)
+			if next == nil {
+				return nil, fmt.Errorf("%q not found", token)
+			}
+			cur = next
+		case yaml.SequenceNode:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(cur.Content) {
+				return nil, fmt.Errorf("invalid index %q", token)
+			}
+			cur = cur.Content[i]
+		default:
+			return nil, fmt.Errorf("cannot descend into scalar at %q", token)
+		}
+	}
+	return cur, nil
+}
+
+// MergeSchemas adds schemas to components.schemas of spec, replacing
+// existing schemas of the same name, and returns the result in the format of
+// spec. The rest of the document, including the order of keys and comments,
+// is left untouched.
+func MergeSchemas(spec []byte, schemas map[string]*Schema) ([]byte, error) {
+	root, err := parseNode(spec)
+	if err != nil {
+		return nil, fmt.Errorf("parsing spec: %w", err)
+	}
+	target := ensureMapping(ensureMapping(root, "components"), "schemas")
+
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		n, err := toNode(schemas[name])
+		if err != nil {
+			return nil, fmt.Errorf("encoding schema %q: %w", name, err)
+		}
+		setMappingValue(target, name, n)
+	}
+	return encodeNode(root, isJSON(spec))
+}
+
+func mappingNode() *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+}
+
+// refNode returns a reference object pointing to ref.
+func refNode(ref string) *yaml.Node {
+	n := mappingNode()
+	appendPair(n, stringNode("$ref"), stringNode(ref))
+	return n
+}
+
+// appendPair appends key and value to the mapping node m, keeping the comments of key.
+func appendPair(m, key, value *yaml.Node) {
+	m.Content = append(m.Content, key, value)
+}
+
+// renamedKey returns a copy of the key node with a new name, keeping its comments.
+func renamedKey(key *yaml.Node, name string) *yaml.Node {
+	k := *key
+	k.Value = name
+	k.Tag = "!!str"
+	k.Style = 0
+	return &k
+}
+
+// deleteMappingKey removes key from the mapping node m and reports whether it was present.
+func deleteMappingKey(m *yaml.Node, key string) bool {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+/*
+ *  overlay.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mwmahlberg/swagger-ui/jsonpath"
+	"gopkg.in/yaml.v3"
+)
+
+// Overlay is a document of the OpenAPI Overlay Specification 1.0, describing
+// changes to apply to an OpenAPI document.
+type Overlay struct {
+	Overlay    string                 `yaml:"overlay"`
+	Info       *OverlayInfo           `yaml:"info"`
+	Extends    string                 `yaml:"extends,omitempty"`
+	Actions    []*OverlayAction       `yaml:"actions"`
+	Extensions map[string]interface{} `yaml:",inline"`
+}
+
+// OverlayInfo provides metadata about an overlay.
+type OverlayInfo struct {
+	Title      string                 `yaml:"title"`
+	Version    string                 `yaml:"version"`
+	Extensions map[string]interface{} `yaml:",inline"`
+}
+
+// OverlayAction changes the nodes its JSONPath target selects.
+type OverlayAction struct {
+	Target      string `yaml:"target"`
+	Description string `yaml:"description,omitempty"`
+	// Update is merged into the selected objects or appended to the selected arrays.
+	Update yaml.Node `yaml:"update,omitempty"`
+	// Remove removes the selected nodes.
+	Remove     bool                   `yaml:"remove,omitempty"`
+	Extensions map[string]interface{} `yaml:",inline"`
+}
+
+// ParseOverlay parses an overlay document in YAML or JSON format.
+func ParseOverlay(data []byte) (*Overlay, error) {
+	var o Overlay
+	if err := yaml.Unmarshal(data, &o); err != nil {
+		return nil, fmt.Errorf("parsing overlay: %w", err)
+	}
+	if !strings.HasPrefix(o.Overlay, "1.") {
+		return nil, fmt.Errorf("unsupported overlay version %q", o.Overlay)
+	}
+	if len(o.Actions) == 0 {
+		return nil, errors.New("overlay has no actions")
+	}
+	for i, a := range o.Actions {
+		if a.Target == "" {
+			return nil, fmt.Errorf("action %d: target missing", i)
+		}
+		if a.Remove == (a.Update.Kind != 0) {
+			return nil, fmt.Errorf("action %d: exactly one of update and remove is required", i)
+		}
+	}
+	return &o, nil
+}
+
+// Apply returns a copy of d with the actions of the overlay applied in order.
+// Updates merge objects recursively and append to arrays; for scalar targets
+// the value is replaced. A target matching nothing is an error.
+func (o *Overlay) Apply(d *Document) (*Document, error) {
+	root, err := d.render()
+	if err != nil {
+		return nil, err
+	}
+	out := copyNode(root)
+	for i, a := range o.Actions {
+		if err := a.apply(out); err != nil {
+			return nil, fmt.Errorf("action %d (%s): %w", i, a.Target, err)
+		}
+	}
+	return decodeDocument(out, d.sourceJSON)
+}
+
+func (a *OverlayAction) apply(root *yaml.Node) error {
+	path, err := jsonpath.Parse(a.Target)
+	if err != nil {
+		return err
+	}
+	matches := path.Select(root)
+	if len(matches) == 0 {
+		return errors.New("target matches nothing")
+	}
+	if a.Remove {
+		// Removing from the back keeps the indexes of earlier matches valid.
+		for i := len(matches) - 1; i >= 0; i-- {
+			remove(matches[i])
+		}
+		return nil
+	}
+	for _, m := range matches {
+		update := copyNode(&a.Update)
+		switch {
+		case m.Node.Kind == yaml.MappingNode && update.Kind == yaml.MappingNode:
+			mergeUpdate(m.Node, update)
+		case m.Node.Kind == yaml.SequenceNode && update.Kind == yaml.SequenceNode:
+			m.Node.Content = append(m.Node.Content, update.Content...)
+		case m.Node.Kind == yaml.SequenceNode:
+			m.Node.Content = append(m.Node.Content, update)
+		case m.Node.Kind == yaml.ScalarNode && m.Parent != nil:
+			replace(m, update)
+		default:
+			return fmt.Errorf("cannot update %s with %s", kindName(m.Node), kindName(update))
+		}
+	}
+	return nil
+}
+
+// mergeUpdate merges the mapping update into target, recursively for nested mappings.
+func mergeUpdate(target, update *yaml.Node) {
+	for i := 0; i+1 < len(update.Content); i += 2 {
+		key, value := update.Content[i], update.Content[i+1]
+		existing := mappingValue(target, key.Value)
+		if existing != nil && existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode {
+			mergeUpdate(existing, value)
+			continue
+		}
+		setMappingValue(target, key.Value, value)
+	}
+}
+
+func remove(m jsonpath.Match) {
+	switch {
+	case m.Parent == nil:
+	case m.Index >= 0:
+		m.Parent.Content = append(m.Parent.Content[:m.Index], m.Parent.Content[m.Index+1:]...)
+	default:
+		deleteMappingKey(m.Parent, m.Key)
+	}
+}
+
+func replace(m jsonpath.Match, value *yaml.Node) {
+	if m.Index >= 0 {
+		m.Parent.Content[m.Index] = value
+		return
+	}
+	setMappingValue(m.Parent, m.Key, value)
+}
+
+func kindName(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	return "scalar"
+}
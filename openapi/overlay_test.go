@@ -0,0 +1,116 @@
+/*
+ *  overlay_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const publicOverlay = `overlay: 1.0.0
+info:
+  title: Public API
+  version: "1.0"
+actions:
+  - target: $.info
+    update:
+      title: Public pets
+      contact:
+        name: Support
+  - target: $.paths[*][?@.operationId == 'getPet']
+    update:
+      tags: [pets]
+  - target: $.paths[*].get.parameters
+    update:
+      name: verbose
+      in: query
+      schema:
+        type: boolean
+  - target: $.components.schemas.Loop
+    remove: true
+  - target: $.info.version
+    update: "2.0"
+`
+
+type OverlaySuite struct {
+	suite.Suite
+	doc *Document
+}
+
+func (suite *OverlaySuite) SetupTest() {
+	var err error
+	suite.doc, err = Parse([]byte(commentedSpec))
+	require.NoError(suite.T(), err)
+}
+
+func (suite *OverlaySuite) TestApply() {
+	o, err := ParseOverlay([]byte(publicOverlay))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Public API", o.Info.Title)
+
+	doc, err := o.Apply(suite.doc)
+	require.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), "Public pets", doc.Info.Title)
+	assert.Equal(suite.T(), "2.0", doc.Info.Version)
+	assert.Equal(suite.T(), "Support", doc.Info.Contact.Name)
+	op := doc.Paths["/pets/{id}"].Get
+	assert.Equal(suite.T(), []string{"pets"}, op.Tags)
+	require.Len(suite.T(), op.Parameters, 2)
+	assert.Equal(suite.T(), "verbose", op.Parameters[1].Name)
+	assert.NotContains(suite.T(), doc.Components.Schemas, "Loop")
+	assert.Contains(suite.T(), doc.Components.Schemas, "Pet")
+
+	out, err := doc.YAML()
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(out), "# Pets are animals.")
+
+	assert.Equal(suite.T(), "Pets", suite.doc.Info.Title, "the original document must not change")
+}
+
+func (suite *OverlaySuite) TestTargetMatchesNothing() {
+	o, err := ParseOverlay([]byte(`overlay: 1.0.0
+info: {title: Broken, version: "1"}
+actions:
+  - target: $.paths['/unknown']
+    remove: true
+`))
+	require.NoError(suite.T(), err)
+	_, err = o.Apply(suite.doc)
+	assert.ErrorContains(suite.T(), err, "matches nothing")
+}
+
+func (suite *OverlaySuite) TestInvalidOverlays() {
+	for _, data := range []string{
+		"overlay: 2.0.0\nactions: [{target: $, remove: true}]",
+		"overlay: 1.0.0\nactions: []",
+		"overlay: 1.0.0\nactions: [{target: $.info}]",
+		"overlay: 1.0.0\nactions: [{remove: true}]",
+	} {
+		_, err := ParseOverlay([]byte(data))
+		assert.Error(suite.T(), err, data)
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	suite.Run(t, new(OverlaySuite))
+}
@@ -0,0 +1,140 @@
+/*
+ *  ref.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrExternalRef is returned when resolving a reference to another document.
+	ErrExternalRef = errors.New("external reference")
+	// ErrUnresolvedRef is returned when the target of a reference does not exist.
+	ErrUnresolvedRef = errors.New("unresolved reference")
+	// ErrCyclicRef is returned when a chain of references leads back to itself.
+	ErrCyclicRef = errors.New("cyclic reference")
+)
+
+// ResolveRef decodes the value the local reference ref, such as
+// "#/components/schemas/Pet" or "#/paths/~1pets/get", points to into v.
+func (d *Document) ResolveRef(ref string, v interface{}) error {
+	if !strings.HasPrefix(ref, "#") {
+		return fmt.Errorf("%q: %w", ref, ErrExternalRef)
+	}
+	root, err := toNode(d)
+	if err != nil {
+		return err
+	}
+	n, err := pointerLookup(root, ref[1:])
+	if err != nil {
+		return fmt.Errorf("%q: %w: %s", ref, ErrUnresolvedRef, err)
+	}
+	return n.Decode(v)
+}
+
+// componentName returns the name of the component of the given kind that ref points to.
+func componentName(ref, kind string) (string, bool) {
+	prefix := "#/components/" + kind + "/"
+	if !strings.HasPrefix(ref, prefix) || strings.Contains(ref[len(prefix):], "/") {
+		return "", false
+	}
+	return unescapePointer(ref[len(prefix):]), true
+}
+
+// resolve follows the chain of references starting at obj. References to
+// components of the given kind yield the component itself, so that changes to
+// the result apply to the document; other references yield a decoded copy.
+func resolve[T any](d *Document, obj *T, refOf func(*T) string, kind string, components func(*Components) map[string]*T) (*T, error) {
+	seen := make(map[string]bool)
+	for obj != nil && refOf(obj) != "" {
+		ref := refOf(obj)
+		if seen[ref] {
+			return nil, fmt.Errorf("%q: %w", ref, ErrCyclicRef)
+		}
+		seen[ref] = true
+
+		if name, ok := componentName(ref, kind); ok {
+			var target *T
+			if d.Components != nil {
+				target = components(d.Components)[name]
+			}
+			if target == nil {
+				return nil, fmt.Errorf("%q: %w", ref, ErrUnresolvedRef)
+			}
+			obj = target
+			continue
+		}
+		var next T
+		if err := d.ResolveRef(ref, &next); err != nil {
+			return nil, err
+		}
+		obj = &next
+	}
+	return obj, nil
+}
+
+// ResolveSchema follows the references of s and returns the schema they lead to.
+func (d *Document) ResolveSchema(s *Schema) (*Schema, error) {
+	return resolve(d, s, func(s *Schema) string { return s.Ref }, "schemas",
+		func(c *Components) map[string]*Schema { return c.Schemas })
+}
+
+// ResolveParameter follows the references of p and returns the parameter they lead to.
+func (d *Document) ResolveParameter(p *Parameter) (*Parameter, error) {
+	return resolve(d, p, func(p *Parameter) string { return p.Ref }, "parameters",
+		func(c *Components) map[string]*Parameter { return c.Parameters })
+}
+
+// ResolveRequestBody follows the references of b and returns the request body they lead to.
+func (d *Document) ResolveRequestBody(b *RequestBody) (*RequestBody, error) {
+	return resolve(d, b, func(b *RequestBody) string { return b.Ref }, "requestBodies",
+		func(c *Components) map[string]*RequestBody { return c.RequestBodies })
+}
+
+// ResolveResponse follows the references of r and returns the response they lead to.
+func (d *Document) ResolveResponse(r *Response) (*Response, error) {
+	return resolve(d, r, func(r *Response) string { return r.Ref }, "responses",
+		func(c *Components) map[string]*Response { return c.Responses })
+}
+
+// ResolveHeader follows the references of h and returns the header they lead to.
+func (d *Document) ResolveHeader(h *Header) (*Header, error) {
+	return resolve(d, h, func(h *Header) string { return h.Ref }, "headers",
+		func(c *Components) map[string]*Header { return c.Headers })
+}
+
+// ResolveExample follows the references of e and returns the example they lead to.
+func (d *Document) ResolveExample(e *Example) (*Example, error) {
+	return resolve(d, e, func(e *Example) string { return e.Ref }, "examples",
+		func(c *Components) map[string]*Example { return c.Examples })
+}
+
+// ResolveSecurityScheme follows the references of s and returns the security scheme they lead to.
+func (d *Document) ResolveSecurityScheme(s *SecurityScheme) (*SecurityScheme, error) {
+	return resolve(d, s, func(s *SecurityScheme) string { return s.Ref }, "securitySchemes",
+		func(c *Components) map[string]*SecurityScheme { return c.SecuritySchemes })
+}
+
+// ResolvePathItem follows the references of p and returns the path item they lead to.
+func (d *Document) ResolvePathItem(p *PathItem) (*PathItem, error) {
+	return resolve(d, p, func(p *PathItem) string { return p.Ref }, "pathItems",
+		func(c *Components) map[string]*PathItem { return c.PathItems })
+}
@@ -0,0 +1,427 @@
+/*
+ *  reflect.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+	// typeArgsRegex matches the package paths in the type arguments of generic type names.
+	typeArgsRegex = regexp.MustCompile(`[\w./-]*\.`)
+)
+
+// Reflector generates schemas from Go types the way encoding/json marshals them.
+//
+// Named struct types become component schemas that are referenced via $ref;
+// they are collected by the Reflector and returned by Schemas. Fields honor
+// the json struct tag including omitempty and the string option, embedded
+// structs are flattened, and time.Time becomes a date-time string. The doc
+// struct tag sets the description of a field and the example tag its example.
+//
+// Fields without omitempty are required. Pointer fields without omitempty are
+// nullable, since encoding/json renders nil pointers as null.
+//
+// The zero value is ready to use and generates OpenAPI 3.0 schemas.
+type Reflector struct {
+	// OpenAPI31 makes the Reflector express nullability with type lists such as
+	// [string, "null"] instead of the nullable keyword of OpenAPI 3.0, and
+	// examples with the examples keyword instead of example.
+	OpenAPI31 bool
+	// Namer returns the component name of a named struct type. If it is nil,
+	// the name of the type is used, qualified with the name of its package if
+	// a different type of the same name has already been reflected.
+	Namer func(reflect.Type) string
+
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+// Reflect returns the schema of the type of v.
+func (r *Reflector) Reflect(v interface{}) *Schema {
+	return r.ReflectType(reflect.TypeOf(v))
+}
+
+// ReflectType returns the schema of t.
+func (r *Reflector) ReflectType(t reflect.Type) *Schema {
+	if r.schemas == nil {
+		r.schemas = make(map[string]*Schema)
+		r.names = make(map[reflect.Type]string)
+	}
+	if t == nil {
+		return &Schema{}
+	}
+	s := r.schema(t)
+	if s == nil {
+		return &Schema{}
+	}
+	return s
+}
+
+// Schemas returns the component schemas of all named struct types reflected so far.
+// The result is meant to be merged into the spec with MergeSchemas.
+func (r *Reflector) Schemas() map[string]*Schema {
+	result := make(map[string]*Schema, len(r.schemas))
+	for name, s := range r.schemas {
+		result[name] = s
+	}
+	return result
+}
+
+// schema returns the schema of t or nil if encoding/json cannot marshal t.
+func (r *Reflector) schema(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Ptr {
+		elem := r.schema(t.Elem())
+		if elem == nil {
+			return nil
+		}
+		return r.nullable(elem)
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: TypeOf("string"), Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	case t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType):
+		// The representation is not known, so any value is accepted.
+		return &Schema{}
+	case t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType):
+		return &Schema{Type: TypeOf("string")}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: TypeOf("boolean")}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: TypeOf("integer"), Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: TypeOf("integer"), Format: "int32"}
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: TypeOf("integer"), Format: "int64", Minimum: float(0)}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: TypeOf("integer"), Format: "int32", Minimum: float(0)}
+	case reflect.Float32:
+		return &Schema{Type: TypeOf("number"), Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: TypeOf("number"), Format: "double"}
+	case reflect.String:
+		return &Schema{Type: TypeOf("string")}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			// encoding/json renders byte slices as base64 strings.
+			return &Schema{Type: TypeOf("string"), Format: "byte"}
+		}
+		items := r.schema(t.Elem())
+		if items == nil {
+			return nil
+		}
+		s := &Schema{Type: TypeOf("array"), Items: items}
+		if t.Kind() == reflect.Array {
+			n := t.Len()
+			s.MinItems, s.MaxItems = &n, &n
+		}
+		return s
+	case reflect.Map:
+		values := r.schema(t.Elem())
+		if values == nil {
+			return nil
+		}
+		return &Schema{Type: TypeOf("object"), AdditionalProperties: values}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.object(t)
+		}
+		return r.named(t)
+	}
+	// Channels, functions and complex numbers cannot be marshalled.
+	return nil
+}
+
+// named registers the named struct type t as a component and returns a reference to it.
+func (r *Reflector) named(t reflect.Type) *Schema {
+	if name, ok := r.names[t]; ok {
+		return RefSchema(name)
+	}
+	name := r.name(t)
+	// The component is registered before it is built to support recursive types.
+	s := &Schema{}
+	r.names[t] = name
+	r.schemas[name] = s
+	*s = *r.object(t)
+	return RefSchema(name)
+}
+
+func (r *Reflector) name(t reflect.Type) string {
+	if r.Namer != nil {
+		return r.Namer(t)
+	}
+	base := typeArgsRegex.ReplaceAllString(t.Name(), "")
+	base = strings.NewReplacer("[", "_", "]", "", ",", "_", " ", "", "*", "").Replace(base)
+	name := base
+	for i := 2; r.schemas[name] != nil; i++ {
+		if pkg := path.Base(t.PkgPath()); i == 2 && pkg != "." && pkg != "" {
+			name = pkg + "." + base
+			if r.schemas[name] == nil {
+				break
+			}
+		}
+		name = base + strconv.Itoa(i)
+	}
+	return name
+}
+
+// nullable returns s modified to accept null.
+func (r *Reflector) nullable(s *Schema) *Schema {
+	switch {
+	case s.Ref != "" && r.OpenAPI31:
+		return &Schema{AnyOf: []*Schema{s, {Type: TypeOf("null")}}}
+	case s.Ref != "":
+		// Keywords next to $ref are ignored in OpenAPI 3.0.
+		return &Schema{AllOf: []*Schema{s}, Nullable: true}
+	case len(s.Type) == 0 || s.Type.Is("null"):
+		return s
+	case r.OpenAPI31:
+		s.Type = append(s.Type, "null")
+	default:
+		s.Nullable = true
+	}
+	return s
+}
+
+// field is a struct field as seen by encoding/json.
+type field struct {
+	name      string
+	tagged    bool
+	omitempty bool
+	asString  bool
+	depth     int
+	sf        reflect.StructField
+}
+
+func (r *Reflector) object(t reflect.Type) *Schema {
+	s := &Schema{Type: TypeOf("object"), Properties: make(map[string]*Schema)}
+	for _, f := range visibleFields(t) {
+		fs := r.fieldSchema(f)
+		if fs == nil {
+			continue
+		}
+		s.Properties[f.name] = fs
+		if !f.omitempty {
+			s.Required = append(s.Required, f.name)
+		}
+	}
+	return s
+}
+
+func (r *Reflector) fieldSchema(f field) *Schema {
+	ft := f.sf.Type
+	var s *Schema
+	if f.omitempty && ft.Kind() == reflect.Ptr {
+		// A nil pointer is omitted rather than rendered as null.
+		s = r.schema(ft.Elem())
+	} else {
+		s = r.schema(ft)
+	}
+	if s == nil {
+		return nil
+	}
+	if f.asString {
+		s = stringified(s)
+	}
+
+	doc, hasDoc := f.sf.Tag.Lookup("doc")
+	example, hasExample := f.sf.Tag.Lookup("example")
+	if !hasDoc && !hasExample {
+		return s
+	}
+	if s.Ref != "" && !r.OpenAPI31 {
+		// Keywords next to $ref are ignored in OpenAPI 3.0.
+		s = &Schema{AllOf: []*Schema{s}}
+	}
+	if hasDoc {
+		s.Description = doc
+	}
+	if hasExample {
+		v := parseExample(example, ft)
+		if r.OpenAPI31 {
+			s.Examples = []interface{}{v}
+		} else {
+			s.Example = v
+		}
+	}
+	return s
+}
+
+// stringified applies the string option of the json tag, which encodes
+// numbers and booleans as JSON strings.
+func stringified(s *Schema) *Schema {
+	switch s.Type.Name() {
+	case "integer", "number", "boolean":
+		c := *s
+		c.Type = TypeOf("string")
+		if s.Type.Is("null") {
+			c.Type = append(c.Type, "null")
+		}
+		c.Format, c.Minimum = "", nil
+		return &c
+	}
+	return s
+}
+
+// parseExample converts the example tag into a value of the kind of t.
+func parseExample(example string, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.String || t == timeType {
+		return example
+	}
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(example), &v); err != nil || v == nil {
+		return example
+	}
+	return v
+}
+
+// visibleFields returns the fields of t that encoding/json marshals,
+// following its rules for embedded structs and conflicting names.
+func visibleFields(t reflect.Type) []field {
+	var all []field
+	collectFields(t, 0, map[reflect.Type]bool{}, &all)
+
+	byName := make(map[string][]int)
+	var order []string
+	for i, f := range all {
+		if _, seen := byName[f.name]; !seen {
+			order = append(order, f.name)
+		}
+		byName[f.name] = append(byName[f.name], i)
+	}
+
+	var result []field
+	for _, name := range order {
+		if f, ok := dominantField(all, byName[name]); ok {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// dominantField picks the shallowest field, preferring tagged ones.
+// Ambiguous fields are dropped, just like encoding/json does.
+func dominantField(all []field, candidates []int) (field, bool) {
+	minDepth := all[candidates[0]].depth
+	for _, i := range candidates {
+		if all[i].depth < minDepth {
+			minDepth = all[i].depth
+		}
+	}
+	var shallow []field
+	for _, i := range candidates {
+		if all[i].depth == minDepth {
+			shallow = append(shallow, all[i])
+		}
+	}
+	if len(shallow) == 1 {
+		return shallow[0], true
+	}
+	var tagged []field
+	for _, f := range shallow {
+		if f.tagged {
+			tagged = append(tagged, f)
+		}
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+	return field{}, false
+}
+
+func collectFields(t reflect.Type, depth int, visited map[reflect.Type]bool, out *[]field) {
+	if visited[t] {
+		return
+	}
+	visited[t] = true
+	defer delete(visited, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if sf.Anonymous {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if !sf.IsExported() && ft.Kind() != reflect.Struct {
+				continue
+			}
+			if name == "" && ft.Kind() == reflect.Struct {
+				collectFields(ft, depth+1, visited, out)
+				continue
+			}
+		} else if !sf.IsExported() {
+			continue
+		}
+
+		f := field{
+			name:   name,
+			tagged: name != "",
+			depth:  depth,
+			sf:     sf,
+		}
+		if f.name == "" {
+			f.name = sf.Name
+		}
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "omitempty":
+				f.omitempty = true
+			case "string":
+				f.asString = true
+			}
+		}
+		*out = append(*out, f)
+	}
+}
+
+func float(f float64) *float64 {
+	return &f
+}
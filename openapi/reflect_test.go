@@ -0,0 +1,178 @@
+/*
+ *  reflect_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type Base struct {
+	ID      int64     `json:"id" doc:"Unique identifier" example:"42"`
+	Created time.Time `json:"created"`
+}
+
+type Label struct {
+	Name string `json:"name"`
+}
+
+type Pet struct {
+	Base
+	Name     string            `json:"name" example:"Rex"`
+	Nickname *string           `json:"nickname"`
+	Age      *int              `json:"age,omitempty"`
+	Count    int               `json:"count,string"`
+	Tags     []Label           `json:"tags,omitempty"`
+	Owner    *Label            `json:"owner"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Photo    []byte            `json:"photo,omitempty"`
+	Extra    json.RawMessage   `json:"extra,omitempty"`
+	IP       net.IP            `json:"ip,omitempty"`
+	Parent   *Pet              `json:"parent,omitempty"`
+	Ignored  string            `json:"-"`
+	internal string
+	Callback func()
+}
+
+type Page[T any] struct {
+	Items []T `json:"items"`
+}
+
+type ReflectSuite struct {
+	suite.Suite
+}
+
+func (suite *ReflectSuite) TestStruct() {
+	var r Reflector
+	s := r.Reflect(Pet{})
+	assert.Equal(suite.T(), "#/components/schemas/Pet", s.Ref)
+
+	schemas := r.Schemas()
+	require.Contains(suite.T(), schemas, "Pet")
+	require.Contains(suite.T(), schemas, "Label")
+	pet := schemas["Pet"]
+
+	assert.Equal(suite.T(), TypeOf("object"), pet.Type)
+	assert.ElementsMatch(suite.T(), []string{"id", "created", "name", "nickname", "count", "owner"}, pet.Required)
+	assert.NotContains(suite.T(), pet.Properties, "Ignored")
+	assert.NotContains(suite.T(), pet.Properties, "internal")
+	assert.NotContains(suite.T(), pet.Properties, "Callback")
+
+	id := pet.Properties["id"]
+	assert.Equal(suite.T(), "Unique identifier", id.Description)
+	assert.Equal(suite.T(), 42, id.Example)
+	assert.Equal(suite.T(), "int64", id.Format)
+
+	assert.Equal(suite.T(), "date-time", pet.Properties["created"].Format)
+	assert.Equal(suite.T(), "Rex", pet.Properties["name"].Example)
+	assert.True(suite.T(), pet.Properties["nickname"].Nullable)
+	assert.False(suite.T(), pet.Properties["age"].Nullable)
+	assert.Equal(suite.T(), TypeOf("string"), pet.Properties["count"].Type)
+	assert.Equal(suite.T(), "#/components/schemas/Label", pet.Properties["tags"].Items.Ref)
+	assert.Equal(suite.T(), &Schema{AllOf: []*Schema{RefSchema("Label")}, Nullable: true}, pet.Properties["owner"])
+	assert.Equal(suite.T(), TypeOf("string"), pet.Properties["labels"].AdditionalProperties.Type)
+	assert.Equal(suite.T(), "byte", pet.Properties["photo"].Format)
+	assert.Equal(suite.T(), &Schema{}, pet.Properties["extra"])
+	assert.Equal(suite.T(), TypeOf("string"), pet.Properties["ip"].Type)
+	assert.Equal(suite.T(), "#/components/schemas/Pet", pet.Properties["parent"].Ref)
+}
+
+func (suite *ReflectSuite) TestOpenAPI31() {
+	r := Reflector{OpenAPI31: true}
+	r.Reflect(Pet{})
+	pet := r.Schemas()["Pet"]
+	assert.Equal(suite.T(), TypeOf("string", "null"), pet.Properties["nickname"].Type)
+	assert.Equal(suite.T(), []interface{}{"Rex"}, pet.Properties["name"].Examples)
+	assert.Len(suite.T(), pet.Properties["owner"].AnyOf, 2)
+}
+
+func (suite *ReflectSuite) TestPrimitives() {
+	var r Reflector
+	assert.Equal(suite.T(), TypeOf("boolean"), r.Reflect(true).Type)
+	assert.Equal(suite.T(), "float", r.Reflect(float32(1)).Format)
+	assert.Equal(suite.T(), float(0), r.Reflect(uint8(1)).Minimum)
+	arr := r.Reflect([3]string{})
+	assert.Equal(suite.T(), 3, *arr.MinItems)
+	assert.Equal(suite.T(), 3, *arr.MaxItems)
+	assert.Equal(suite.T(), &Schema{}, r.Reflect(nil))
+	assert.Equal(suite.T(), TypeOf("object"), r.Reflect(struct{ A int }{}).Type)
+}
+
+func (suite *ReflectSuite) TestGenericName() {
+	var r Reflector
+	s := r.Reflect(Page[Label]{})
+	assert.Equal(suite.T(), "#/components/schemas/Page_Label", s.Ref)
+}
+
+func (suite *ReflectSuite) TestConflictingNames() {
+	var r Reflector
+	r.Reflect(Label{})
+	type Label struct {
+		Other string
+	}
+	s := r.Reflect(Label{})
+	assert.Equal(suite.T(), "#/components/schemas/openapi.Label", s.Ref)
+}
+
+func (suite *ReflectSuite) TestNamer() {
+	r := Reflector{Namer: func(t reflect.Type) string { return "My" + t.Name() }}
+	assert.Equal(suite.T(), "#/components/schemas/MyLabel", r.Reflect(Label{}).Ref)
+}
+
+func (suite *ReflectSuite) TestAmbiguousEmbedded() {
+	type A struct{ X int }
+	type B struct{ X int }
+	type C struct {
+		A
+		B
+		Y int
+	}
+	var r Reflector
+	s := r.Reflect(C{})
+	c := r.Schemas()[refName(s.Ref)]
+	assert.NotContains(suite.T(), c.Properties, "X")
+	assert.Contains(suite.T(), c.Properties, "Y")
+}
+
+func (suite *ReflectSuite) TestMergeIntoSpec() {
+	var r Reflector
+	r.Reflect(Label{})
+	out, err := MergeSchemas([]byte("openapi: 3.0.3\n"), r.Schemas())
+	require.NoError(suite.T(), err)
+
+	var doc map[string]interface{}
+	require.NoError(suite.T(), yaml.Unmarshal(out, &doc))
+	assert.Equal(suite.T(), "object", doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})["Label"].(map[string]interface{})["type"])
+}
+
+func refName(ref string) string {
+	return ref[len("#/components/schemas/"):]
+}
+
+func TestReflect(t *testing.T) {
+	suite.Run(t, new(ReflectSuite))
+}
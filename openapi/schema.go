@@ -0,0 +1,184 @@
+/*
+ *  schema.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// Package openapi provides types for working with OpenAPI documents.
+package openapi
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// Schema is an OpenAPI schema object. It covers the keywords of OpenAPI 3.0
+// as well as the JSON Schema keywords most commonly used with OpenAPI 3.1.
+type Schema struct {
+	Ref         string        `yaml:"$ref,omitempty"`
+	Title       string        `yaml:"title,omitempty"`
+	Description string        `yaml:"description,omitempty"`
+	Type        Types         `yaml:"type,omitempty"`
+	Format      string        `yaml:"format,omitempty"`
+	Enum        []interface{} `yaml:"enum,omitempty"`
+	Const       interface{}   `yaml:"const,omitempty"`
+	Default     interface{}   `yaml:"default,omitempty"`
+	Example     interface{}   `yaml:"example,omitempty"`
+	Examples    []interface{} `yaml:"examples,omitempty"`
+	Nullable    bool          `yaml:"nullable,omitempty"`
+	ReadOnly    bool          `yaml:"readOnly,omitempty"`
+	WriteOnly   bool          `yaml:"writeOnly,omitempty"`
+	Deprecated  bool          `yaml:"deprecated,omitempty"`
+
+	MultipleOf *float64 `yaml:"multipleOf,omitempty"`
+	Minimum    *float64 `yaml:"minimum,omitempty"`
+	Maximum    *float64 `yaml:"maximum,omitempty"`
+	// ExclusiveMinimum is a bool in OpenAPI 3.0 and a number in OpenAPI 3.1.
+	ExclusiveMinimum interface{} `yaml:"exclusiveMinimum,omitempty"`
+	// ExclusiveMaximum is a bool in OpenAPI 3.0 and a number in OpenAPI 3.1.
+	ExclusiveMaximum interface{} `yaml:"exclusiveMaximum,omitempty"`
+	MinLength        *int        `yaml:"minLength,omitempty"`
+	MaxLength        *int        `yaml:"maxLength,omitempty"`
+	Pattern          string      `yaml:"pattern,omitempty"`
+
+	Items       *Schema `yaml:"items,omitempty"`
+	MinItems    *int    `yaml:"minItems,omitempty"`
+	MaxItems    *int    `yaml:"maxItems,omitempty"`
+	UniqueItems bool    `yaml:"uniqueItems,omitempty"`
+
+	Properties           map[string]*Schema `yaml:"properties,omitempty"`
+	Required             []string           `yaml:"required,omitempty"`
+	AdditionalProperties *Schema            `yaml:"additionalProperties,omitempty"`
+	MinProperties        *int               `yaml:"minProperties,omitempty"`
+	MaxProperties        *int               `yaml:"maxProperties,omitempty"`
+
+	AllOf         []*Schema      `yaml:"allOf,omitempty"`
+	OneOf         []*Schema      `yaml:"oneOf,omitempty"`
+	AnyOf         []*Schema      `yaml:"anyOf,omitempty"`
+	Not           *Schema        `yaml:"not,omitempty"`
+	Discriminator *Discriminator `yaml:"discriminator,omitempty"`
+
+	// Extensions holds specification extensions ("x-" keys) as well as all
+	// keywords without a dedicated field, so that they survive a round trip.
+	Extensions map[string]interface{} `yaml:",inline"`
+
+	// boolean is set for the boolean schemas "true" and "false" of JSON Schema.
+	boolean *bool
+}
+
+// Discriminator aids in selecting the schema of a oneOf or anyOf.
+type Discriminator struct {
+	PropertyName string            `yaml:"propertyName"`
+	Mapping      map[string]string `yaml:"mapping,omitempty"`
+}
+
+// BoolSchema returns the JSON Schema boolean schema, which accepts any value
+// if b is true and no value at all if b is false.
+// It is mostly used for AdditionalProperties.
+func BoolSchema(b bool) *Schema {
+	return &Schema{boolean: &b}
+}
+
+// RefSchema returns a schema referring to the component schema name.
+func RefSchema(name string) *Schema {
+	return &Schema{Ref: ComponentRef("schemas", name)}
+}
+
+// Bool reports whether s is a boolean schema and, if so, its value.
+func (s *Schema) Bool() (value bool, isBool bool) {
+	if s == nil || s.boolean == nil {
+		return false, false
+	}
+	return *s.boolean, true
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (s Schema) MarshalYAML() (interface{}, error) {
+	if s.boolean != nil {
+		return *s.boolean, nil
+	}
+	type plain Schema
+	return plain(s), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (s *Schema) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode && value.Tag == "!!bool" {
+		var b bool
+		if err := value.Decode(&b); err != nil {
+			return err
+		}
+		*s = Schema{boolean: &b}
+		return nil
+	}
+	type plain Schema
+	return value.Decode((*plain)(s))
+}
+
+// Types holds the type of a schema. OpenAPI 3.0 allows a single type only,
+// while OpenAPI 3.1 allows a list of types such as [string, "null"].
+type Types []string
+
+// TypeOf returns Types holding the given types.
+func TypeOf(types ...string) Types {
+	return Types(types)
+}
+
+// Is reports whether t contains typ.
+func (t Types) Is(typ string) bool {
+	for _, name := range t {
+		if name == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// Name returns the first type that is not "null", or "" if there is none.
+func (t Types) Name() string {
+	for _, name := range t {
+		if name != "null" {
+			return name
+		}
+	}
+	return ""
+}
+
+// MarshalYAML implements yaml.Marshaler. A single type is written as a scalar.
+func (t Types) MarshalYAML() (interface{}, error) {
+	if len(t) == 1 {
+		return t[0], nil
+	}
+	return []string(t), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (t *Types) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*t = Types{value.Value}
+		return nil
+	}
+	var list []string
+	if err := value.Decode(&list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+// ComponentRef returns the local reference to the component name of the given kind,
+// e.g. ComponentRef("schemas", "Pet") returns "#/components/schemas/Pet".
+func ComponentRef(kind, name string) string {
+	return "#/components/" + kind + "/" + escapePointer(name)
+}
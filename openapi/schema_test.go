@@ -0,0 +1,123 @@
+/*
+ *  schema_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type SchemaSuite struct {
+	suite.Suite
+}
+
+func (suite *SchemaSuite) TestRoundTrip() {
+	const in = `type:
+  - string
+  - "null"
+format: uuid
+x-go-type: uuid.UUID
+additionalProperties: false
+`
+	var s Schema
+	require.NoError(suite.T(), yaml.Unmarshal([]byte(in), &s))
+	assert.Equal(suite.T(), TypeOf("string", "null"), s.Type)
+	assert.Equal(suite.T(), "string", s.Type.Name())
+	assert.True(suite.T(), s.Type.Is("null"))
+	assert.Equal(suite.T(), "uuid.UUID", s.Extensions["x-go-type"])
+	b, isBool := s.AdditionalProperties.Bool()
+	assert.True(suite.T(), isBool)
+	assert.False(suite.T(), b)
+
+	out, err := yaml.Marshal(&s)
+	require.NoError(suite.T(), err)
+	assert.YAMLEq(suite.T(), in, string(out))
+}
+
+func (suite *SchemaSuite) TestSingleTypeIsScalar() {
+	out, err := yaml.Marshal(&Schema{Type: TypeOf("integer")})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "type: integer\n", string(out))
+}
+
+func (suite *SchemaSuite) TestComponentRef() {
+	assert.Equal(suite.T(), "#/components/schemas/Pet", RefSchema("Pet").Ref)
+	assert.Equal(suite.T(), "#/components/schemas/a~1b~0c", ComponentRef("schemas", "a/b~c"))
+}
+
+func (suite *SchemaSuite) TestMergeSchemasYAML() {
+	const spec = `openapi: 3.0.3
+# The paths come first
+paths: {}
+components:
+  schemas:
+    Old:
+      type: string
+`
+	out, err := MergeSchemas([]byte(spec), map[string]*Schema{
+		"Pet": {Type: TypeOf("object")},
+		"Old": {Type: TypeOf("integer")},
+	})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), `openapi: 3.0.3
+# The paths come first
+paths: {}
+components:
+  schemas:
+    Old:
+      type: integer
+    Pet:
+      type: object
+`, string(out))
+}
+
+func (suite *SchemaSuite) TestMergeSchemasJSON() {
+	out, err := MergeSchemas([]byte(`{"openapi":"3.0.3","paths":{}}`), map[string]*Schema{
+		"Pet": {Type: TypeOf("object"), Required: []string{"id"}},
+	})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), `{
+  "openapi": "3.0.3",
+  "paths": {},
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "required": [
+          "id"
+        ]
+      }
+    }
+  }
+}
+`, string(out))
+}
+
+func (suite *SchemaSuite) TestMergeSchemasInvalid() {
+	_, err := MergeSchemas([]byte("- a list"), nil)
+	assert.Error(suite.T(), err)
+}
+
+func TestSchema(t *testing.T) {
+	suite.Run(t, new(SchemaSuite))
+}
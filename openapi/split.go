@@ -0,0 +1,286 @@
+/*
+ *  split.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPart is the name of the part holding the operations not assigned to any other part.
+const DefaultPart = "default"
+
+// Part is a self-contained section of a split document.
+type Part struct {
+	// Name is the tag or path prefix the part was split by, or DefaultPart.
+	Name     string
+	Document *Document
+}
+
+// SplitByTag partitions d into one document per tag. An operation with
+// several tags is part of each of their documents, while operations without
+// tags end up in DefaultPart. Parts are ordered like the tags declared in d,
+// followed by undeclared tags in the order of their first use.
+//
+// Each part carries only the components it references, directly or through
+// other components, and the security schemes its security requirements name.
+func SplitByTag(d *Document) ([]Part, error) {
+	return split(d, func(_ string, op *yaml.Node) []string {
+		var names []string
+		if tags := mappingValue(op, "tags"); tags != nil {
+			for _, tag := range tags.Content {
+				names = append(names, tag.Value)
+			}
+		}
+		return names
+	}, true, tagNames(d))
+}
+
+// SplitByPathPrefix partitions d into one document per path prefix. Each path
+// belongs to the longest prefix it starts with, matching whole path segments
+// only, so that "/pet" does not match "/pets". Paths matching no prefix end up
+// in DefaultPart. Parts are ordered like the prefixes. Webhooks are not part of
+// any document, since they have no path.
+func SplitByPathPrefix(d *Document, prefixes ...string) ([]Part, error) {
+	return split(d, func(path string, _ *yaml.Node) []string {
+		var best string
+		for _, p := range prefixes {
+			trimmed := strings.TrimSuffix(p, "/")
+			if (path == trimmed || strings.HasPrefix(path, trimmed+"/")) && len(p) > len(best) {
+				best = p
+			}
+		}
+		if best == "" {
+			return nil
+		}
+		return []string{best}
+	}, false, prefixes)
+}
+
+// tagNames returns the names of the tags declared in d.
+func tagNames(d *Document) []string {
+	var names []string
+	for _, tag := range d.Tags {
+		if tag != nil {
+			names = append(names, tag.Name)
+		}
+	}
+	return names
+}
+
+// part collects the path items and webhooks of a part while splitting.
+type part struct {
+	name     string
+	paths    *yaml.Node
+	webhooks *yaml.Node
+	tags     map[string]bool
+}
+
+// splitter assigns operations to parts.
+type splitter struct {
+	root   *yaml.Node
+	assign func(path string, op *yaml.Node) []string
+	parts  map[string]*part
+	order  []string
+}
+
+// split partitions d, assigning each operation to the parts named by assign.
+// Parts listed in order come first, whether they have operations or not.
+func split(d *Document, assign func(path string, op *yaml.Node) []string, webhooks bool, order []string) ([]Part, error) {
+	root, err := d.render()
+	if err != nil {
+		return nil, err
+	}
+	s := &splitter{root: root, assign: assign, parts: make(map[string]*part)}
+	for _, name := range order {
+		s.part(name)
+	}
+	if err := s.add(mappingValue(root, "paths"), func(p *part) *yaml.Node { return p.paths }); err != nil {
+		return nil, err
+	}
+	if webhooks {
+		if err := s.add(mappingValue(root, "webhooks"), func(p *part) *yaml.Node {
+			if p.webhooks == nil {
+				p.webhooks = mappingNode()
+			}
+			return p.webhooks
+		}); err != nil {
+			return nil, err
+		}
+	}
+	// The default part is listed last, unless its position was requested.
+	if i := slices.Index(s.order, DefaultPart); i >= 0 && !slices.Contains(order, DefaultPart) {
+		s.order = append(slices.Delete(s.order, i, i+1), DefaultPart)
+	}
+
+	var parts []Part
+	for _, name := range s.order {
+		p := s.parts[name]
+		if len(p.paths.Content) == 0 && p.webhooks == nil {
+			continue
+		}
+		doc, err := decodeDocument(s.document(p), d.sourceJSON)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, Part{Name: name, Document: doc})
+	}
+	return parts, nil
+}
+
+// part returns the part called name, creating it if necessary.
+func (s *splitter) part(name string) *part {
+	p, ok := s.parts[name]
+	if !ok {
+		p = &part{name: name, paths: mappingNode(), tags: make(map[string]bool)}
+		s.parts[name] = p
+		s.order = append(s.order, name)
+	}
+	return p
+}
+
+// add distributes the operations of the path items in items among the parts.
+// A path item is copied to a part with its common fields and the operations
+// assigned to that part.
+func (s *splitter) add(items *yaml.Node, target func(*part) *yaml.Node) error {
+	if items == nil {
+		return nil
+	}
+	for i := 0; i+1 < len(items.Content); i += 2 {
+		key, item := items.Content[i], items.Content[i+1]
+		if ref := mappingValue(item, "$ref"); ref != nil && strings.HasPrefix(ref.Value, "#") {
+			resolved, err := pointerLookup(s.root, ref.Value[1:])
+			if err != nil {
+				return err
+			}
+			item = resolved
+		}
+		copies := make(map[string]*yaml.Node)
+		for j := 0; j+1 < len(item.Content); j += 2 {
+			method, op := item.Content[j].Value, item.Content[j+1]
+			if !isMethod(method) {
+				continue
+			}
+			names := s.assign(key.Value, op)
+			if len(names) == 0 {
+				names = []string{DefaultPart}
+			}
+			for _, name := range names {
+				p := s.part(name)
+				c, ok := copies[name]
+				if !ok {
+					c = commonFields(item)
+					copies[name] = c
+					appendPair(target(p), copyNode(key), c)
+				}
+				appendPair(c, copyNode(item.Content[j]), copyNode(op))
+				if tags := mappingValue(op, "tags"); tags != nil {
+					for _, tag := range tags.Content {
+						p.tags[tag.Value] = true
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// commonFields returns a copy of the path item without its operations.
+func commonFields(item *yaml.Node) *yaml.Node {
+	c := mappingNode()
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		if !isMethod(item.Content[i].Value) {
+			appendPair(c, copyNode(item.Content[i]), copyNode(item.Content[i+1]))
+		}
+	}
+	return c
+}
+
+// document assembles the root node of the document of p.
+func (s *splitter) document(p *part) *yaml.Node {
+	out := mappingNode()
+	hasPaths := false
+	for i := 0; i+1 < len(s.root.Content); i += 2 {
+		key, value := s.root.Content[i], s.root.Content[i+1]
+		switch key.Value {
+		case "paths":
+			appendPair(out, copyNode(key), p.paths)
+			hasPaths = true
+		case "webhooks":
+			if p.webhooks != nil {
+				appendPair(out, copyNode(key), p.webhooks)
+			}
+		case "components":
+			// Pruned once the references of the part are known.
+		case "tags":
+			tags := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: value.Style}
+			for _, tag := range value.Content {
+				if name := mappingValue(tag, "name"); name != nil && p.tags[name.Value] {
+					tags.Content = append(tags.Content, copyNode(tag))
+				}
+			}
+			if len(tags.Content) > 0 {
+				appendPair(out, copyNode(key), tags)
+			}
+		default:
+			appendPair(out, copyNode(key), copyNode(value))
+		}
+	}
+	if !hasPaths && len(p.paths.Content) > 0 {
+		appendPair(out, stringNode("paths"), p.paths)
+	}
+	if components := s.components(out); components != nil {
+		appendPair(out, stringNode("components"), components)
+	}
+	return out
+}
+
+// components returns the components of the document referenced from out, or
+// nil if there are none.
+func (s *splitter) components(out *yaml.Node) *yaml.Node {
+	all := mappingValue(s.root, "components")
+	if all == nil {
+		return nil
+	}
+	used := usedComponents(all, out)
+
+	components := mappingNode()
+	for i := 0; i+1 < len(all.Content); i += 2 {
+		key, value := all.Content[i], all.Content[i+1]
+		if strings.HasPrefix(key.Value, "x-") {
+			appendPair(components, copyNode(key), copyNode(value))
+			continue
+		}
+		kept := mappingNode()
+		for j := 0; j+1 < len(value.Content); j += 2 {
+			if used[key.Value][value.Content[j].Value] {
+				appendPair(kept, copyNode(value.Content[j]), copyNode(value.Content[j+1]))
+			}
+		}
+		if len(kept.Content) > 0 {
+			appendPair(components, copyNode(key), kept)
+		}
+	}
+	if len(components.Content) == 0 {
+		return nil
+	}
+	return components
+}
@@ -0,0 +1,190 @@
+/*
+ *  split_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const storeSpec = `openapi: 3.0.3
+info:
+  title: Store
+  version: "1.0"
+tags:
+  - name: orders
+  - name: pets
+paths:
+  /pets:
+    parameters:
+      - $ref: '#/components/parameters/Limit'
+    get:
+      operationId: listPets
+      tags: [pets]
+      security:
+        - key: []
+      responses:
+        "200":
+          description: The pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+    post:
+      operationId: orderPet
+      tags: [pets, orders]
+      requestBody:
+        $ref: '#/components/requestBodies/Order'
+      responses:
+        "201":
+          description: Ordered
+  /pets/{id}:
+    get:
+      operationId: showPet
+      tags: [pets]
+      responses:
+        "200":
+          description: The pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+  /health:
+    get:
+      operationId: health
+      responses:
+        "204":
+          description: Healthy
+components:
+  parameters:
+    Limit:
+      name: limit
+      in: query
+      schema:
+        type: integer
+  requestBodies:
+    Order:
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Order'
+  schemas:
+    Pet:
+      type: object
+      properties:
+        owner:
+          $ref: '#/components/schemas/Owner'
+    Owner:
+      type: object
+    Order:
+      type: object
+    Unused:
+      type: string
+  securitySchemes:
+    key:
+      type: apiKey
+      in: header
+      name: X-Key
+`
+
+type SplitSuite struct {
+	suite.Suite
+	doc *Document
+}
+
+func (suite *SplitSuite) SetupTest() {
+	doc, err := Parse([]byte(storeSpec))
+	require.NoError(suite.T(), err)
+	suite.doc = doc
+}
+
+func partNames(parts []Part) []string {
+	var names []string
+	for _, p := range parts {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func componentNames[T any](m map[string]T) []string {
+	var names []string
+	for name := range m {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (suite *SplitSuite) TestSplitByTag() {
+	parts, err := SplitByTag(suite.doc)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), []string{"orders", "pets", DefaultPart}, partNames(parts))
+
+	orders := parts[0].Document
+	require.Contains(suite.T(), orders.Paths, "/pets")
+	assert.Nil(suite.T(), orders.Paths["/pets"].Get)
+	assert.NotNil(suite.T(), orders.Paths["/pets"].Post)
+	assert.Len(suite.T(), orders.Paths["/pets"].Parameters, 1, "path item fields are kept")
+	assert.ElementsMatch(suite.T(), []string{"Order"}, componentNames(orders.Components.Schemas))
+	assert.ElementsMatch(suite.T(), []string{"Limit"}, componentNames(orders.Components.Parameters))
+	assert.Empty(suite.T(), orders.Components.SecuritySchemes)
+	assert.Len(suite.T(), orders.Tags, 2, "orderPet is tagged with pets, too")
+
+	pets := parts[1].Document
+	assert.Len(suite.T(), pets.Paths, 2)
+	assert.ElementsMatch(suite.T(), []string{"Pet", "Owner", "Order"}, componentNames(pets.Components.Schemas))
+	assert.ElementsMatch(suite.T(), []string{"key"}, componentNames(pets.Components.SecuritySchemes))
+	assert.Len(suite.T(), pets.Tags, 2)
+
+	health := parts[2].Document
+	assert.Contains(suite.T(), health.Paths, "/health")
+	assert.Nil(suite.T(), health.Components)
+	assert.Empty(suite.T(), health.Tags)
+}
+
+func (suite *SplitSuite) TestSplitByPathPrefix() {
+	parts, err := SplitByPathPrefix(suite.doc, "/pet", "/pets/{id}", "/pets")
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), []string{"/pets/{id}", "/pets", DefaultPart}, partNames(parts))
+
+	assert.Len(suite.T(), parts[0].Document.Paths, 1)
+	assert.Contains(suite.T(), parts[0].Document.Paths, "/pets/{id}")
+	assert.ElementsMatch(suite.T(), []string{"Pet", "Owner"}, componentNames(parts[0].Document.Components.Schemas))
+
+	assert.Len(suite.T(), parts[1].Document.Paths, 1)
+	assert.NotNil(suite.T(), parts[1].Document.Paths["/pets"].Get)
+	assert.NotNil(suite.T(), parts[1].Document.Paths["/pets"].Post)
+	assert.Contains(suite.T(), parts[2].Document.Paths, "/health")
+}
+
+func (suite *SplitSuite) TestSplitKeepsSource() {
+	_, err := SplitByTag(suite.doc)
+	require.NoError(suite.T(), err)
+	out, err := suite.doc.Marshal()
+	require.NoError(suite.T(), err)
+	assert.YAMLEq(suite.T(), storeSpec, string(out))
+}
+
+func TestSplit(t *testing.T) {
+	suite.Run(t, new(SplitSuite))
+}
@@ -0,0 +1,728 @@
+/*
+ *  swagger2.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IsSwagger2 reports whether data holds a Swagger 2.0 document.
+func IsSwagger2(data []byte) bool {
+	root, err := parseNode(data)
+	if err != nil {
+		return false
+	}
+	v := mappingValue(root, "swagger")
+	return v != nil && v.Value == "2.0"
+}
+
+// ConvertSwagger2 converts a Swagger 2.0 document to OpenAPI 3.0.3.
+//
+// Definitions, parameters, responses and security definitions become
+// components, body and form parameters become request bodies using the
+// consumed media types, response schemas are offered for each produced media
+// type and host, basePath and schemes are turned into servers. Constructs
+// without an equivalent are dropped and described in the returned warnings.
+func ConvertSwagger2(data []byte) (*Document, []string, error) {
+	src, err := parseNode(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing document: %w", err)
+	}
+	if v := mappingValue(src, "swagger"); v == nil || v.Value != "2.0" {
+		return nil, nil, errors.New("not a Swagger 2.0 document: swagger field missing")
+	}
+	c := &swagger2{
+		src:      src,
+		consumes: stringList(mappingValue(src, "consumes")),
+		produces: stringList(mappingValue(src, "produces")),
+	}
+	out := c.document()
+	doc, err := decodeDocument(out, isJSON(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	return doc, c.warnings, nil
+}
+
+// swagger2 converts a Swagger 2.0 document.
+type swagger2 struct {
+	src      *yaml.Node
+	consumes []string
+	produces []string
+	warnings []string
+}
+
+func (c *swagger2) warn(pointer, format string, args ...interface{}) {
+	c.warnings = append(c.warnings, "#"+pointer+": "+fmt.Sprintf(format, args...))
+}
+
+func (c *swagger2) document() *yaml.Node {
+	out := mappingNode()
+	var components *yaml.Node
+	servers := false
+	for i := 0; i+1 < len(c.src.Content); i += 2 {
+		key, value := c.src.Content[i], c.src.Content[i+1]
+		switch key.Value {
+		case "swagger":
+			appendPair(out, renamedKey(key, "openapi"), stringNode("3.0.3"))
+		case "host", "basePath", "schemes":
+			if !servers {
+				servers = true
+				appendPair(out, renamedKey(key, "servers"), c.servers(mappingValue(c.src, "schemes"), ""))
+			}
+		case "consumes", "produces":
+		case "paths":
+			appendPair(out, key, c.paths(value))
+		case "definitions", "parameters", "responses", "securityDefinitions":
+			if components == nil {
+				components = mappingNode()
+				appendPair(out, renamedKey(key, "components"), components)
+			}
+			c.components(components, key, value)
+		case "info", "tags", "externalDocs", "security":
+			appendPair(out, key, value)
+		default:
+			if strings.HasPrefix(key.Value, "x-") {
+				appendPair(out, key, value)
+			} else {
+				c.warn("/"+escapePointer(key.Value), "unknown field dropped")
+			}
+		}
+	}
+	if mappingValue(out, "paths") == nil {
+		appendPair(out, stringNode("paths"), mappingNode())
+	}
+	return out
+}
+
+// servers derives servers from host, basePath and the given schemes.
+func (c *swagger2) servers(schemes *yaml.Node, pointer string) *yaml.Node {
+	var host, basePath string
+	if v := mappingValue(c.src, "host"); v != nil {
+		host = v.Value
+	}
+	if v := mappingValue(c.src, "basePath"); v != nil {
+		basePath = strings.TrimSuffix(v.Value, "/")
+	}
+	out := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	add := func(url string) {
+		if url == "" {
+			url = "/"
+		}
+		server := mappingNode()
+		appendPair(server, stringNode("url"), stringNode(url))
+		out.Content = append(out.Content, server)
+	}
+	list := stringList(schemes)
+	switch {
+	case host == "":
+		if len(list) > 0 {
+			c.warn(pointer+"/schemes", "schemes without host cannot be expressed as server URLs")
+		}
+		add(basePath)
+	case len(list) == 0:
+		add("//" + host + basePath)
+	default:
+		for _, scheme := range list {
+			add(scheme + "://" + host + basePath)
+		}
+	}
+	return out
+}
+
+func (c *swagger2) components(components, key, value *yaml.Node) {
+	switch key.Value {
+	case "definitions":
+		schemas := ensureMapping(components, "schemas")
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			pointer := "/definitions/" + escapePointer(value.Content[i].Value)
+			appendPair(schemas, value.Content[i], c.schema(value.Content[i+1], pointer))
+		}
+	case "parameters":
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			name, p := value.Content[i], value.Content[i+1]
+			pointer := "/parameters/" + escapePointer(name.Value)
+			switch in(p) {
+			case "body":
+				appendPair(ensureMapping(components, "requestBodies"), name, c.requestBody(p, c.consumes, pointer))
+			case "formData":
+				// Form parameters are inlined into the request bodies of
+				// the operations referring to them.
+			default:
+				appendPair(ensureMapping(components, "parameters"), name, c.parameter(p, pointer))
+			}
+		}
+	case "responses":
+		responses := ensureMapping(components, "responses")
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			pointer := "/responses/" + escapePointer(value.Content[i].Value)
+			appendPair(responses, value.Content[i], c.response(value.Content[i+1], c.produces, pointer))
+		}
+	case "securityDefinitions":
+		schemes := ensureMapping(components, "securitySchemes")
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			pointer := "/securityDefinitions/" + escapePointer(value.Content[i].Value)
+			appendPair(schemes, value.Content[i], c.securityScheme(value.Content[i+1], pointer))
+		}
+	}
+}
+
+func (c *swagger2) paths(paths *yaml.Node) *yaml.Node {
+	out := mappingNode()
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		key, item := paths.Content[i], paths.Content[i+1]
+		if strings.HasPrefix(key.Value, "x-") {
+			appendPair(out, key, item)
+			continue
+		}
+		appendPair(out, key, c.pathItem(item, "/paths/"+escapePointer(key.Value)))
+	}
+	return out
+}
+
+func (c *swagger2) pathItem(item *yaml.Node, pointer string) *yaml.Node {
+	out := mappingNode()
+	// Path level body and form parameters apply to each operation.
+	var shared []*yaml.Node
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		key, value := item.Content[i], item.Content[i+1]
+		switch {
+		case key.Value == "parameters":
+			var params []*yaml.Node
+			for _, p := range value.Content {
+				if in := c.parameterIn(p); in == "body" || in == "formData" {
+					shared = append(shared, p)
+				} else {
+					params = append(params, p)
+				}
+			}
+			if len(params) > 0 {
+				list, _ := c.parameters(params, nil, pointer+"/parameters")
+				appendPair(out, key, list)
+			}
+		case isMethod(key.Value):
+			appendPair(out, key, c.operation(value, shared, pointer+"/"+key.Value))
+		default:
+			appendPair(out, key, value)
+		}
+	}
+	return out
+}
+
+func isMethod(s string) bool {
+	for _, m := range Methods {
+		if m == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *swagger2) operation(op *yaml.Node, shared []*yaml.Node, pointer string) *yaml.Node {
+	consumes, produces := c.consumes, c.produces
+	if v := mappingValue(op, "consumes"); v != nil {
+		consumes = stringList(v)
+	}
+	if v := mappingValue(op, "produces"); v != nil {
+		produces = stringList(v)
+	}
+
+	out := mappingNode()
+	params := mappingValue(op, "parameters")
+	if params == nil && len(shared) > 0 {
+		params = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	}
+	for i := 0; i+1 < len(op.Content); i += 2 {
+		key, value := op.Content[i], op.Content[i+1]
+		switch key.Value {
+		case "consumes", "produces":
+		case "parameters":
+		case "responses":
+			if params != nil {
+				c.appendParameters(out, params, shared, consumes, pointer)
+				params = nil
+			}
+			responses := mappingNode()
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				code, r := value.Content[j], value.Content[j+1]
+				if strings.HasPrefix(code.Value, "x-") {
+					appendPair(responses, code, r)
+					continue
+				}
+				appendPair(responses, code, c.response(r, produces, pointer+"/responses/"+escapePointer(code.Value)))
+			}
+			appendPair(out, key, responses)
+		case "schemes":
+			appendPair(out, renamedKey(key, "servers"), c.servers(value, pointer))
+		default:
+			appendPair(out, key, value)
+		}
+	}
+	if params != nil {
+		c.appendParameters(out, params, shared, consumes, pointer)
+	}
+	return out
+}
+
+// appendParameters appends the parameters and request body of an operation to out.
+func (c *swagger2) appendParameters(out, params *yaml.Node, shared []*yaml.Node, consumes []string, pointer string) {
+	list, body := c.parameters(append(append([]*yaml.Node(nil), shared...), params.Content...), consumes, pointer+"/parameters")
+	if len(list.Content) > 0 {
+		appendPair(out, stringNode("parameters"), list)
+	}
+	if body != nil {
+		appendPair(out, stringNode("requestBody"), body)
+	}
+}
+
+// parameters converts a list of parameters. Body and form parameters are
+// returned as request body.
+func (c *swagger2) parameters(params []*yaml.Node, consumes []string, pointer string) (*yaml.Node, *yaml.Node) {
+	out := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	var body *yaml.Node
+	var form []*yaml.Node
+	for i, p := range params {
+		ref := mappingValue(p, "$ref")
+		switch c.parameterIn(p) {
+		case "body":
+			if ref != nil {
+				body = refNode(c.ref(ref.Value, pointer))
+			} else {
+				body = c.requestBody(p, consumes, pointer+"/"+strconv.Itoa(i))
+			}
+		case "formData":
+			if ref != nil {
+				p = c.lookup(ref.Value)
+			}
+			form = append(form, p)
+		default:
+			if ref != nil {
+				out.Content = append(out.Content, refNode(c.ref(ref.Value, pointer)))
+				continue
+			}
+			out.Content = append(out.Content, c.parameter(p, pointer+"/"+strconv.Itoa(i)))
+		}
+	}
+	if len(form) > 0 {
+		body = c.formBody(form, consumes)
+	}
+	return out, body
+}
+
+// parameterIn returns the location of p, following a reference to a global parameter.
+func (c *swagger2) parameterIn(p *yaml.Node) string {
+	if ref := mappingValue(p, "$ref"); ref != nil {
+		p = c.lookup(ref.Value)
+	}
+	return in(p)
+}
+
+func in(p *yaml.Node) string {
+	if v := mappingValue(p, "in"); v != nil {
+		return v.Value
+	}
+	return ""
+}
+
+// lookup returns the node the local reference ref points to, or nil.
+func (c *swagger2) lookup(ref string) *yaml.Node {
+	if !strings.HasPrefix(ref, "#") {
+		return nil
+	}
+	n, err := pointerLookup(c.src, ref[1:])
+	if err != nil {
+		return nil
+	}
+	return n
+}
+
+// ref rewrites a reference to the location of its target in OpenAPI 3.
+func (c *swagger2) ref(ref, pointer string) string {
+	switch {
+	case strings.HasPrefix(ref, "#/definitions/"):
+		return "#/components/schemas/" + strings.TrimPrefix(ref, "#/definitions/")
+	case strings.HasPrefix(ref, "#/parameters/"):
+		if in(c.lookup(ref)) == "body" {
+			return "#/components/requestBodies/" + strings.TrimPrefix(ref, "#/parameters/")
+		}
+		return "#/components/parameters/" + strings.TrimPrefix(ref, "#/parameters/")
+	case strings.HasPrefix(ref, "#/responses/"):
+		return "#/components/responses/" + strings.TrimPrefix(ref, "#/responses/")
+	case !strings.HasPrefix(ref, "#"):
+		c.warn(pointer, "external reference %q is not converted", ref)
+	}
+	return ref
+}
+
+// schema converts a schema, rewriting references and Swagger 2.0 specific keywords.
+func (c *swagger2) schema(s *yaml.Node, pointer string) *yaml.Node {
+	switch s.Kind {
+	case yaml.SequenceNode:
+		out := *s
+		out.Content = make([]*yaml.Node, len(s.Content))
+		for i, item := range s.Content {
+			out.Content[i] = c.schema(item, pointer+"/"+strconv.Itoa(i))
+		}
+		return &out
+	case yaml.MappingNode:
+	default:
+		return s
+	}
+	out := *s
+	out.Content = nil
+	for i := 0; i+1 < len(s.Content); i += 2 {
+		key, value := s.Content[i], s.Content[i+1]
+		switch {
+		case key.Value == "$ref" && value.Kind == yaml.ScalarNode:
+			appendPair(&out, key, stringNode(c.ref(value.Value, pointer)))
+		case key.Value == "x-nullable" && value.Kind == yaml.ScalarNode:
+			appendPair(&out, renamedKey(key, "nullable"), value)
+		case key.Value == "type" && value.Value == "file":
+			appendPair(&out, key, stringNode("string"))
+			appendPair(&out, stringNode("format"), stringNode("binary"))
+		case key.Value == "discriminator" && value.Kind == yaml.ScalarNode:
+			d := mappingNode()
+			appendPair(d, stringNode("propertyName"), value)
+			appendPair(&out, key, d)
+		case key.Value == "properties" && value.Kind == yaml.MappingNode:
+			// Property names are not keywords, so only their schemas are converted.
+			props := *value
+			props.Content = nil
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				name := value.Content[j]
+				appendPair(&props, name, c.schema(value.Content[j+1], pointer+"/properties/"+escapePointer(name.Value)))
+			}
+			appendPair(&out, key, &props)
+		case key.Value == "example" || key.Value == "default" || key.Value == "enum":
+			appendPair(&out, key, value)
+		default:
+			appendPair(&out, key, c.schema(value, pointer+"/"+escapePointer(key.Value)))
+		}
+	}
+	return &out
+}
+
+// simpleSchemaKeys are the keywords of non-body parameters, headers and items
+// that describe the value and move into its schema in OpenAPI 3.
+var simpleSchemaKeys = map[string]bool{
+	"type": true, "format": true, "items": true, "default": true, "maximum": true,
+	"exclusiveMaximum": true, "minimum": true, "exclusiveMinimum": true, "maxLength": true,
+	"minLength": true, "pattern": true, "maxItems": true, "minItems": true,
+	"uniqueItems": true, "enum": true, "multipleOf": true,
+}
+
+// simpleSchema builds a schema from the keywords of a non-body parameter, header or items object.
+func (c *swagger2) simpleSchema(p *yaml.Node, pointer string) *yaml.Node {
+	s := mappingNode()
+	for i := 0; i+1 < len(p.Content); i += 2 {
+		key, value := p.Content[i], p.Content[i+1]
+		switch {
+		case key.Value == "items":
+			appendPair(s, key, c.simpleSchema(value, pointer+"/items"))
+		case key.Value == "type" && value.Value == "file":
+			appendPair(s, key, stringNode("string"))
+			appendPair(s, stringNode("format"), stringNode("binary"))
+		case key.Value == "collectionFormat" && strings.HasSuffix(pointer, "/items"):
+			if value.Value != "csv" {
+				c.warn(pointer, "collectionFormat %s of nested items has no equivalent", value.Value)
+			}
+		case simpleSchemaKeys[key.Value]:
+			appendPair(s, key, value)
+		}
+	}
+	return s
+}
+
+// parameter converts a query, header or path parameter.
+func (c *swagger2) parameter(p *yaml.Node, pointer string) *yaml.Node {
+	out := mappingNode()
+	for i := 0; i+1 < len(p.Content); i += 2 {
+		key, value := p.Content[i], p.Content[i+1]
+		switch {
+		case simpleSchemaKeys[key.Value] || key.Value == "collectionFormat":
+		case key.Value == "x-example":
+			appendPair(out, renamedKey(key, "example"), value)
+		default:
+			appendPair(out, key, value)
+		}
+	}
+	if v := mappingValue(p, "type"); v != nil && v.Value == "array" {
+		c.collectionFormat(out, p, pointer)
+	}
+	appendPair(out, stringNode("schema"), c.simpleSchema(p, pointer))
+	return out
+}
+
+// collectionFormat translates the collectionFormat of an array parameter into style and explode.
+func (c *swagger2) collectionFormat(out, p *yaml.Node, pointer string) {
+	format := "csv"
+	if v := mappingValue(p, "collectionFormat"); v != nil {
+		format = v.Value
+	}
+	query := in(p) == "query"
+	style := func(style string, explode bool) {
+		appendPair(out, stringNode("style"), stringNode(style))
+		appendPair(out, stringNode("explode"), &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(explode)})
+	}
+	switch {
+	case format == "csv" && query:
+		style("form", false)
+	case format == "csv":
+	case format == "multi" && query:
+		style("form", true)
+	case format == "ssv" && query:
+		style("spaceDelimited", false)
+	case format == "pipes" && query:
+		style("pipeDelimited", false)
+	default:
+		c.warn(pointer, "collectionFormat %s has no equivalent for %s parameters", format, in(p))
+	}
+}
+
+// mediaTypes returns types, or application/json if there are none.
+func mediaTypes(types []string) []string {
+	if len(types) == 0 {
+		return []string{"application/json"}
+	}
+	return types
+}
+
+// requestBody converts a body parameter.
+func (c *swagger2) requestBody(p *yaml.Node, consumes []string, pointer string) *yaml.Node {
+	out := mappingNode()
+	if v := mappingValue(p, "description"); v != nil {
+		appendPair(out, stringNode("description"), v)
+	}
+	content := mappingNode()
+	for _, t := range mediaTypes(consumes) {
+		media := mappingNode()
+		if s := mappingValue(p, "schema"); s != nil {
+			appendPair(media, stringNode("schema"), c.schema(s, pointer+"/schema"))
+		}
+		appendPair(content, stringNode(t), media)
+	}
+	appendPair(out, stringNode("content"), content)
+	if v := mappingValue(p, "required"); v != nil {
+		appendPair(out, stringNode("required"), v)
+	}
+	copyExtensions(out, p)
+	return out
+}
+
+// formBody combines form parameters into a request body with an object schema.
+func (c *swagger2) formBody(params []*yaml.Node, consumes []string) *yaml.Node {
+	schema := mappingNode()
+	appendPair(schema, stringNode("type"), stringNode("object"))
+	properties := mappingNode()
+	required := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	file := false
+	for _, p := range params {
+		name := mappingValue(p, "name")
+		if name == nil {
+			continue
+		}
+		if v := mappingValue(p, "type"); v != nil && v.Value == "file" {
+			file = true
+		}
+		prop := c.simpleSchema(p, "")
+		if v := mappingValue(p, "description"); v != nil {
+			appendPair(prop, stringNode("description"), v)
+		}
+		appendPair(properties, stringNode(name.Value), prop)
+		if v := mappingValue(p, "required"); v != nil && v.Value == "true" {
+			required.Content = append(required.Content, stringNode(name.Value))
+		}
+	}
+	appendPair(schema, stringNode("properties"), properties)
+	if len(required.Content) > 0 {
+		appendPair(schema, stringNode("required"), required)
+	}
+
+	var types []string
+	for _, t := range consumes {
+		if t == "multipart/form-data" || t == "application/x-www-form-urlencoded" {
+			types = append(types, t)
+		}
+	}
+	if len(types) == 0 {
+		if file {
+			types = []string{"multipart/form-data"}
+		} else {
+			types = []string{"application/x-www-form-urlencoded"}
+		}
+	}
+	content := mappingNode()
+	for _, t := range types {
+		media := mappingNode()
+		appendPair(media, stringNode("schema"), schema)
+		appendPair(content, stringNode(t), media)
+	}
+	out := mappingNode()
+	appendPair(out, stringNode("content"), content)
+	return out
+}
+
+// response converts a response, offering its schema for each produced media type.
+func (c *swagger2) response(r *yaml.Node, produces []string, pointer string) *yaml.Node {
+	if ref := mappingValue(r, "$ref"); ref != nil {
+		return refNode(c.ref(ref.Value, pointer))
+	}
+	out := mappingNode()
+	var content *yaml.Node
+	for i := 0; i+1 < len(r.Content); i += 2 {
+		key, value := r.Content[i], r.Content[i+1]
+		switch key.Value {
+		case "schema":
+			if content == nil {
+				content = mappingNode()
+			}
+			for _, t := range mediaTypes(produces) {
+				media := ensureMapping(content, t)
+				setMappingValue(media, "schema", c.schema(value, pointer+"/schema"))
+			}
+		case "examples":
+			if content == nil {
+				content = mappingNode()
+			}
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				media := ensureMapping(content, value.Content[j].Value)
+				setMappingValue(media, "example", value.Content[j+1])
+			}
+		case "headers":
+			headers := mappingNode()
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				name, h := value.Content[j], value.Content[j+1]
+				appendPair(headers, name, c.header(h, pointer+"/headers/"+escapePointer(name.Value)))
+			}
+			appendPair(out, key, headers)
+		default:
+			appendPair(out, key, value)
+		}
+	}
+	if mappingValue(out, "description") == nil {
+		appendPair(out, stringNode("description"), stringNode(""))
+	}
+	if content != nil {
+		appendPair(out, stringNode("content"), content)
+	}
+	return out
+}
+
+func (c *swagger2) header(h *yaml.Node, pointer string) *yaml.Node {
+	out := mappingNode()
+	for i := 0; i+1 < len(h.Content); i += 2 {
+		key, value := h.Content[i], h.Content[i+1]
+		switch {
+		case key.Value == "collectionFormat":
+			if value.Value != "csv" {
+				c.warn(pointer, "collectionFormat %s has no equivalent for headers", value.Value)
+			}
+		case simpleSchemaKeys[key.Value]:
+		default:
+			appendPair(out, key, value)
+		}
+	}
+	appendPair(out, stringNode("schema"), c.simpleSchema(h, pointer))
+	return out
+}
+
+// oauth2Flows maps the OAuth2 flows of Swagger 2.0 to their OpenAPI 3 names.
+var oauth2Flows = map[string]string{
+	"implicit":    "implicit",
+	"password":    "password",
+	"application": "clientCredentials",
+	"accessCode":  "authorizationCode",
+}
+
+func (c *swagger2) securityScheme(s *yaml.Node, pointer string) *yaml.Node {
+	out := mappingNode()
+	typ := mappingValue(s, "type")
+	if typ == nil {
+		c.warn(pointer, "security definition without type")
+		return out
+	}
+	switch typ.Value {
+	case "basic":
+		appendPair(out, stringNode("type"), stringNode("http"))
+		appendPair(out, stringNode("scheme"), stringNode("basic"))
+	case "apiKey":
+		appendPair(out, stringNode("type"), typ)
+		for _, key := range []string{"name", "in"} {
+			if v := mappingValue(s, key); v != nil {
+				appendPair(out, stringNode(key), v)
+			}
+		}
+	case "oauth2":
+		appendPair(out, stringNode("type"), typ)
+		flow := mappingNode()
+		for _, key := range []string{"authorizationUrl", "tokenUrl"} {
+			if v := mappingValue(s, key); v != nil {
+				appendPair(flow, stringNode(key), v)
+			}
+		}
+		scopes := mappingValue(s, "scopes")
+		if scopes == nil {
+			scopes = mappingNode()
+		}
+		appendPair(flow, stringNode("scopes"), scopes)
+		flows := mappingNode()
+		name := "implicit"
+		if v := mappingValue(s, "flow"); v != nil && oauth2Flows[v.Value] != "" {
+			name = oauth2Flows[v.Value]
+		} else {
+			c.warn(pointer, "unknown OAuth2 flow, assuming implicit")
+		}
+		appendPair(flows, stringNode(name), flow)
+		appendPair(out, stringNode("flows"), flows)
+	default:
+		c.warn(pointer, "unknown security scheme type %q", typ.Value)
+		appendPair(out, stringNode("type"), typ)
+	}
+	if v := mappingValue(s, "description"); v != nil {
+		appendPair(out, stringNode("description"), v)
+	}
+	copyExtensions(out, s)
+	return out
+}
+
+// copyExtensions appends the specification extensions of src to dst.
+func copyExtensions(dst, src *yaml.Node) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		if strings.HasPrefix(src.Content[i].Value, "x-") {
+			appendPair(dst, src.Content[i], src.Content[i+1])
+		}
+	}
+}
+
+// stringList returns the values of a sequence of strings.
+func stringList(n *yaml.Node) []string {
+	if n == nil || n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	list := make([]string, 0, len(n.Content))
+	for _, item := range n.Content {
+		list = append(list, item.Value)
+	}
+	return list
+}
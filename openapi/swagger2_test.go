@@ -0,0 +1,130 @@
+/*
+ *  swagger2_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package openapi
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type Swagger2Suite struct {
+	suite.Suite
+	doc      *Document
+	warnings []string
+}
+
+func (suite *Swagger2Suite) SetupTest() {
+	data, err := os.ReadFile("testdata/swagger2.yaml")
+	require.NoError(suite.T(), err)
+	require.True(suite.T(), IsSwagger2(data))
+	suite.doc, suite.warnings, err = ConvertSwagger2(data)
+	require.NoError(suite.T(), err)
+}
+
+func (suite *Swagger2Suite) TestDocument() {
+	assert.Equal(suite.T(), "3.0.3", suite.doc.OpenAPI)
+	assert.Equal(suite.T(), "Petstore", suite.doc.Info.Title)
+	require.Len(suite.T(), suite.doc.Servers, 1)
+	assert.Equal(suite.T(), "https://petstore.example.com/v1", suite.doc.Servers[0].URL)
+	assert.Equal(suite.T(), "public", suite.doc.Extensions["x-audience"])
+
+	out, err := suite.doc.YAML()
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(out), "# The production host.")
+	assert.NotContains(suite.T(), string(out), "#/definitions/")
+}
+
+func (suite *Swagger2Suite) TestComponents() {
+	c := suite.doc.Components
+	require.NotNil(suite.T(), c)
+	pet := c.Schemas["Pet"]
+	require.NotNil(suite.T(), pet)
+	require.NotNil(suite.T(), pet.Discriminator)
+	assert.Equal(suite.T(), "kind", pet.Discriminator.PropertyName)
+	assert.Equal(suite.T(), "#/components/schemas/Owner", pet.Properties["owner"].Ref)
+	assert.True(suite.T(), pet.Properties["nickname"].Nullable)
+
+	assert.Equal(suite.T(), TypeOf("string"), c.Parameters["Tenant"].Schema.Type)
+	require.Contains(suite.T(), c.RequestBodies, "PetBody")
+	assert.True(suite.T(), c.RequestBodies["PetBody"].Required)
+	assert.Equal(suite.T(), "#/components/schemas/Pet", c.RequestBodies["PetBody"].Content["application/json"].Schema.Ref)
+	assert.Equal(suite.T(), "#/components/schemas/Error", c.Responses["Error"].Content["application/xml"].Schema.Ref)
+
+	assert.Equal(suite.T(), "http", c.SecuritySchemes["basic"].Type)
+	assert.Equal(suite.T(), "basic", c.SecuritySchemes["basic"].Scheme)
+	assert.Equal(suite.T(), "X-API-Key", c.SecuritySchemes["key"].Name)
+	flow := c.SecuritySchemes["oauth"].Flows.AuthorizationCode
+	require.NotNil(suite.T(), flow)
+	assert.Equal(suite.T(), "https://auth.example.com/token", flow.TokenURL)
+	assert.Equal(suite.T(), "Read access", flow.Scopes["read"])
+}
+
+func (suite *Swagger2Suite) TestOperations() {
+	pets := suite.doc.Paths["/pets"]
+	require.NotNil(suite.T(), pets)
+	assert.Equal(suite.T(), "#/components/parameters/Tenant", pets.Parameters[0].Ref)
+
+	list := pets.Get
+	require.Len(suite.T(), list.Parameters, 2)
+	tags := list.Parameters[0]
+	assert.Equal(suite.T(), "form", tags.Style)
+	require.NotNil(suite.T(), tags.Explode)
+	assert.True(suite.T(), *tags.Explode)
+	assert.Equal(suite.T(), TypeOf("array"), tags.Schema.Type)
+
+	ok := list.Responses["200"]
+	assert.Equal(suite.T(), "#/components/schemas/Pet", ok.Content["application/json"].Schema.Items.Ref)
+	assert.NotNil(suite.T(), ok.Content["application/xml"].Schema)
+	assert.NotNil(suite.T(), ok.Content["application/json"].Example)
+	assert.Equal(suite.T(), TypeOf("integer"), ok.Headers["X-Total"].Schema.Type)
+	assert.Equal(suite.T(), "#/components/responses/Error", list.Responses["default"].Ref)
+
+	create := pets.Post
+	assert.Empty(suite.T(), create.Parameters)
+	require.NotNil(suite.T(), create.RequestBody)
+	assert.Equal(suite.T(), "#/components/requestBodies/PetBody", create.RequestBody.Ref)
+
+	upload := suite.doc.Paths["/pets/{id}/photo"].Post
+	require.Len(suite.T(), upload.Parameters, 1)
+	assert.Equal(suite.T(), "int64", upload.Parameters[0].Schema.Format)
+	form := upload.RequestBody.Content["multipart/form-data"]
+	require.NotNil(suite.T(), form)
+	assert.Equal(suite.T(), "binary", form.Schema.Properties["photo"].Format)
+	assert.Equal(suite.T(), []string{"photo"}, form.Schema.Required)
+}
+
+func (suite *Swagger2Suite) TestWarnings() {
+	require.Len(suite.T(), suite.warnings, 1)
+	assert.Contains(suite.T(), suite.warnings[0], "#/paths/~1pets/get/parameters/1")
+	assert.Contains(suite.T(), suite.warnings[0], "tsv")
+}
+
+func (suite *Swagger2Suite) TestNotSwagger2() {
+	assert.False(suite.T(), IsSwagger2([]byte(commentedSpec)))
+	_, _, err := ConvertSwagger2([]byte(commentedSpec))
+	assert.Error(suite.T(), err)
+}
+
+func TestSwagger2(t *testing.T) {
+	suite.Run(t, new(Swagger2Suite))
+}
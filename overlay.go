@@ -0,0 +1,53 @@
+/*
+ *  overlay.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"fmt"
+
+	"github.com/mwmahlberg/swagger-ui/openapi"
+)
+
+// Overlays applies OpenAPI Overlay 1.0 documents to the spec before it is
+// served, for example to tailor it to an environment or audience. The
+// overlays and their actions are applied in order. Setup fails if an action's
+// target matches nothing.
+func Overlays(overlays ...[]byte) HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.overlays = append(suh.overlays, overlays...)
+	}
+}
+
+func (ui *SwaggerUi) applyOverlays() error {
+	doc, err := openapi.Parse(ui.specContent)
+	if err != nil {
+		return err
+	}
+	for i, data := range ui.overlays {
+		o, err := openapi.ParseOverlay(data)
+		if err != nil {
+			return fmt.Errorf("overlay %d: %w", i, err)
+		}
+		if doc, err = o.Apply(doc); err != nil {
+			return fmt.Errorf("overlay %d: %w", i, err)
+		}
+	}
+	ui.specContent, err = encodeDocument(doc, ui.specFilename)
+	return err
+}
@@ -0,0 +1,71 @@
+/*
+ *  overlay_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type OverlaySuite struct {
+	suite.Suite
+}
+
+func (suite *OverlaySuite) TestOverlays() {
+	servers := []byte(`overlay: 1.0.0
+info: {title: Staging, version: "1"}
+actions:
+  - target: $
+    update:
+      servers:
+        - url: https://staging.example.com
+`)
+	title := []byte(`overlay: 1.0.0
+info: {title: Internal, version: "1"}
+actions:
+  - target: $.info
+    update:
+      title: Internal pets
+`)
+	ui, err := New(Spec("pets.yaml", []byte(refSpec)), Overlays(servers), Overlays(title))
+	require.NoError(suite.T(), err)
+
+	doc, err := ui.Document()
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Internal pets", doc.Info.Title)
+	require.Len(suite.T(), doc.Servers, 1)
+	assert.Equal(suite.T(), "https://staging.example.com", doc.Servers[0].URL)
+}
+
+func (suite *OverlaySuite) TestUnmatchedTarget() {
+	_, err := New(Spec("pets.yaml", []byte(refSpec)), Overlays([]byte(`overlay: 1.0.0
+info: {title: Broken, version: "1"}
+actions:
+  - target: $.webhooks
+    remove: true
+`)))
+	assert.ErrorAs(suite.T(), err, &SetupError{})
+}
+
+func TestOverlay(t *testing.T) {
+	suite.Run(t, new(OverlaySuite))
+}
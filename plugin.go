@@ -0,0 +1,138 @@
+/*
+ *  plugin.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// pluginDir is the directory plugins are served from.
+const pluginDir = "plugins/"
+
+//go:embed plugins/*.js
+var bundledPlugins embed.FS
+
+// BundledPlugins holds plugins bundled with this package, for use with PluginFS:
+//
+//   - CaseInsensitiveFilterPlugin.js makes the tag filter ignore case.
+//   - DisableTryItOutPlugin.js removes the "Try it out" button from all operations.
+//   - HideTopbarPlugin.js removes the top bar with the spec selector.
+var BundledPlugins fs.FS
+
+func init() {
+	BundledPlugins, _ = fs.Sub(bundledPlugins, strings.TrimSuffix(pluginDir, "/"))
+}
+
+// jsIdentifier matches the names of plugins.
+var jsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// plugin is a swagger-ui plugin registered with Plugin or PluginFS.
+type plugin struct {
+	name   string
+	source []byte
+}
+
+// Plugin registers a swagger-ui plugin. Its source must define a global
+// function called name, which is passed to SwaggerUIBundle as a plugin:
+//
+//	function HelloPlugin(system) {
+//	  return {components: {...}};
+//	}
+//
+// The source is served from "plugins/<name>.js". The generated initializer
+// loads the plugins before swagger-ui starts and lists them after the
+// built-in plugins, in the order they were registered. A plugin failing to
+// load is logged to the browser console and left out. An initializer passed
+// to InitializerContent has to load and list them itself.
+func Plugin(name string, source []byte) HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.addPlugin(name, source)
+	}
+}
+
+// PluginFS registers the plugins in the files of fsys matching pattern, as
+// in "*.js", in lexical order. Each file defines a function named like the
+// file without its extension, see Plugin.
+func PluginFS(fsys fs.FS, pattern string) HandlerOption {
+	return func(suh *SwaggerUi) {
+		names, err := fs.Glob(fsys, pattern)
+		if err == nil && len(names) == 0 {
+			err = fmt.Errorf("no plugin matches %s", pattern)
+		}
+		if err != nil {
+			suh.optionErrs = append(suh.optionErrs, err)
+			return
+		}
+		for _, name := range names {
+			source, err := fs.ReadFile(fsys, name)
+			if err != nil {
+				suh.optionErrs = append(suh.optionErrs, err)
+				continue
+			}
+			base := path.Base(name)
+			suh.addPlugin(strings.TrimSuffix(base, path.Ext(base)), source)
+		}
+	}
+}
+
+func (ui *SwaggerUi) addPlugin(name string, source []byte) {
+	if !jsIdentifier.MatchString(name) {
+		ui.optionErrs = append(ui.optionErrs, fmt.Errorf("plugin name %q is not a JavaScript identifier", name))
+		return
+	}
+	for _, p := range ui.plugins {
+		if p.name == name {
+			ui.optionErrs = append(ui.optionErrs, fmt.Errorf("plugin %s is registered twice", name))
+			return
+		}
+	}
+	ui.plugins = append(ui.plugins, plugin{name: name, source: source})
+}
+
+// setupPlugins serves the sources of the plugins.
+func (ui *SwaggerUi) setupPlugins() {
+	for _, p := range ui.plugins {
+		ui.serveFile(pluginFilename(p.name), p.source)
+	}
+}
+
+func pluginFilename(name string) string {
+	return pluginDir + name + ".js"
+}
+
+// pluginRef is the entry of a plugin in the initializer.
+type pluginRef struct {
+	// Name is the function defined by the plugin.
+	Name string
+	URL  string
+}
+
+// pluginRefs returns the initializer entries of the plugins.
+func (ui *SwaggerUi) pluginRefs() []pluginRef {
+	var refs []pluginRef
+	for _, p := range ui.plugins {
+		refs = append(refs, pluginRef{Name: p.name, URL: pluginFilename(p.name)})
+	}
+	return refs
+}
@@ -0,0 +1,97 @@
+/*
+ *  plugin_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"net/http"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PluginSuite struct {
+	suite.Suite
+}
+
+const helloPlugin = `function HelloPlugin() {
+  return {};
+}
+`
+
+func (suite *PluginSuite) TestPlugins() {
+	ui, err := New(Spec("pets.yaml", versionedSpec("1.0", "")),
+		Plugin("HelloPlugin", []byte(helloPlugin)),
+		PluginFS(fstest.MapFS{
+			"b/World.js":  {Data: []byte("function World() {}")},
+			"a/Answer.js": {Data: []byte("function Answer() {}")},
+		}, "*/*.js"))
+	require.NoError(suite.T(), err)
+
+	code, body := get(ui, "/plugins/HelloPlugin.js")
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Equal(suite.T(), helloPlugin, body)
+	code, _ = get(ui, "/plugins/World.js")
+	assert.Equal(suite.T(), http.StatusOK, code)
+
+	_, body = get(ui, "/"+InitializerFilename)
+	assert.Contains(suite.T(), body, `loadPlugins([
+    ["HelloPlugin", "./plugins/HelloPlugin.js"],
+    ["Answer", "./plugins/Answer.js"],
+    ["World", "./plugins/World.js"]
+  ]).then(initialize);`)
+	assert.Contains(suite.T(), body, `SwaggerUIBundle.plugins.DownloadUrl
+    ].concat(plugins),`)
+	assert.Contains(suite.T(), body, "script.onerror = function () {\n          console.error(",
+		"plugins failing to load are logged and left out")
+}
+
+func (suite *PluginSuite) TestBundled() {
+	ui, err := New(Spec("pets.yaml", versionedSpec("1.0", "")), PluginFS(BundledPlugins, "HideTopbarPlugin.js"))
+	require.NoError(suite.T(), err)
+	code, body := get(ui, "/plugins/HideTopbarPlugin.js")
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Contains(suite.T(), body, "function HideTopbarPlugin()")
+}
+
+func (suite *PluginSuite) TestWithoutPlugins() {
+	ui, err := New(Spec("pets.yaml", versionedSpec("1.0", "")))
+	require.NoError(suite.T(), err)
+	_, body := get(ui, "/"+InitializerFilename)
+	assert.NotContains(suite.T(), body, "loadPlugins")
+	assert.Contains(suite.T(), body, "window.onload = function () {\n  //<editor-fold")
+}
+
+func (suite *PluginSuite) TestInvalid() {
+	for name, opt := range map[string]HandlerOption{
+		"name":     Plugin("hello-plugin", []byte(helloPlugin)),
+		"twice":    func(ui *SwaggerUi) { Plugin("A", nil)(ui); Plugin("A", nil)(ui) },
+		"no match": PluginFS(BundledPlugins, "Missing*.js"),
+		"bad glob": PluginFS(BundledPlugins, "["),
+	} {
+		_, err := New(Spec("pets.yaml", versionedSpec("1.0", "")), opt)
+		assert.Error(suite.T(), err, name)
+	}
+}
+
+func TestPlugin(t *testing.T) {
+	suite.Run(t, new(PluginSuite))
+}
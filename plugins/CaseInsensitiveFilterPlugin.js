@@ -0,0 +1,13 @@
+// CaseInsensitiveFilterPlugin makes the tag filter ignore case.
+function CaseInsensitiveFilterPlugin() {
+  return {
+    fn: {
+      opsFilter: function (taggedOps, phrase) {
+        phrase = phrase.toLowerCase();
+        return taggedOps.filter(function (tagObj, tag) {
+          return tag.toLowerCase().indexOf(phrase) !== -1;
+        });
+      }
+    }
+  };
+}
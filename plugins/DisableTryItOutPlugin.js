@@ -0,0 +1,16 @@
+// DisableTryItOutPlugin removes the "Try it out" button from all operations.
+function DisableTryItOutPlugin() {
+  return {
+    statePlugins: {
+      spec: {
+        wrapSelectors: {
+          allowTryItOutFor: function () {
+            return function () {
+              return false;
+            };
+          }
+        }
+      }
+    }
+  };
+}
@@ -0,0 +1,10 @@
+// HideTopbarPlugin removes the top bar with the spec selector of the standalone layout.
+function HideTopbarPlugin() {
+  return {
+    components: {
+      Topbar: function () {
+        return null;
+      }
+    }
+  };
+}
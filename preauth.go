@@ -0,0 +1,142 @@
+/*
+ *  preauth.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"strings"
+)
+
+// preauthorization is a credential entered into the Authorize dialog on startup.
+type preauthorization struct {
+	scheme   string
+	apiKey   string
+	username string
+	password string
+	basic    bool
+}
+
+// call renders the call of the swagger-ui method doing the preauthorization.
+func (p preauthorization) call() template.HTML {
+	if p.basic {
+		return template.HTML(fmt.Sprintf("preauthorizeBasic(%s, %s, %s)", jsString(p.scheme), jsString(p.username), jsString(p.password)))
+	}
+	return template.HTML(fmt.Sprintf("preauthorizeApiKey(%s, %s)", jsString(p.scheme), jsString(p.apiKey)))
+}
+
+// PreauthorizeAPIKey enters key into the Authorize dialog for the security
+// scheme named scheme once the spec is loaded. This works for API keys as
+// well as for bearer tokens of HTTP schemes.
+//
+// Since the key is served to everyone who can load swagger-ui, this is
+// available in development mode only: New fails unless DevMode is set, and
+// DevMode cannot be combined with Production.
+func PreauthorizeAPIKey(scheme, key string) HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.preauthorizations = append(suh.preauthorizations, preauthorization{scheme: scheme, apiKey: key})
+	}
+}
+
+// PreauthorizeBasic enters username and password into the Authorize dialog
+// for the HTTP basic scheme named scheme once the spec is loaded. Like
+// PreauthorizeAPIKey, it is available in development mode only.
+func PreauthorizeBasic(scheme, username, password string) HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.preauthorizations = append(suh.preauthorizations, preauthorization{scheme: scheme, username: username, password: password, basic: true})
+	}
+}
+
+// PreauthorizeFromEnv reads preauthorizations from the environment, so that
+// developers can keep credentials out of the code:
+//
+//	PREAUTHORIZE_API_KEY="api_key=abc123,bearer=eyJhbGciOi..."
+//	PREAUTHORIZE_BASIC="basic=alice:secret"
+//
+// Entries are separated by commas and name the security scheme before the
+// equals sign. See PreauthorizeAPIKey and PreauthorizeBasic.
+func PreauthorizeFromEnv() HandlerOption {
+	return func(suh *SwaggerUi) {
+		for _, entry := range envEntries("PREAUTHORIZE_API_KEY") {
+			scheme, key, ok := strings.Cut(entry, "=")
+			if !ok || scheme == "" {
+				suh.optionErrs = append(suh.optionErrs, fmt.Errorf("PREAUTHORIZE_API_KEY: %q is not scheme=key", entry))
+				continue
+			}
+			PreauthorizeAPIKey(scheme, key)(suh)
+		}
+		for _, entry := range envEntries("PREAUTHORIZE_BASIC") {
+			scheme, credentials, ok := strings.Cut(entry, "=")
+			username, password, hasPassword := strings.Cut(credentials, ":")
+			if !ok || scheme == "" || !hasPassword {
+				// The entry is not quoted, since it holds a password.
+				suh.optionErrs = append(suh.optionErrs, errors.New("PREAUTHORIZE_BASIC: entries must be scheme=username:password"))
+				continue
+			}
+			PreauthorizeBasic(scheme, username, password)(suh)
+		}
+	}
+}
+
+// envEntries returns the comma-separated entries of an environment variable.
+func envEntries(name string) []string {
+	var entries []string
+	for _, entry := range strings.Split(os.Getenv(name), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// checkModes rejects features meant for development only outside of
+// development mode and in production.
+func (ui *SwaggerUi) checkModes() error {
+	switch {
+	case ui.production && ui.devMode:
+		return errors.New("DevMode cannot be used in production")
+	case len(ui.preauthorizations) > 0 && !ui.devMode:
+		return errors.New("preauthorization requires DevMode")
+	}
+	return nil
+}
+
+// checkPreauthorizations warns about preauthorizations of security schemes the spec does not define.
+func (ui *SwaggerUi) checkPreauthorizations() {
+	doc, err := parseSpec(ui.specContent)
+	if err != nil {
+		return
+	}
+	for _, p := range ui.preauthorizations {
+		if doc.Components == nil || doc.Components.SecuritySchemes[p.scheme] == nil {
+			ui.warnings = append(ui.warnings, fmt.Sprintf("preauthorized security scheme %s is not defined", p.scheme))
+		}
+	}
+}
+
+// preauthorizeCalls returns the calls of the preauthorizations for the initializer.
+func (ui *SwaggerUi) preauthorizeCalls() []template.HTML {
+	var calls []template.HTML
+	for _, p := range ui.preauthorizations {
+		calls = append(calls, p.call())
+	}
+	return calls
+}
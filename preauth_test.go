@@ -0,0 +1,99 @@
+/*
+ *  preauth_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// securedSpec is refSpec with an API key and a basic security scheme.
+func securedSpec() []byte {
+	return []byte(strings.Replace(string(versionedSpec("1.0", "")), "components:\n", `components:
+  securitySchemes:
+    api_key:
+      type: apiKey
+      name: X-API-Key
+      in: header
+    basic:
+      type: http
+      scheme: basic
+`, 1))
+}
+
+type PreauthSuite struct {
+	suite.Suite
+}
+
+func (suite *PreauthSuite) TestPreauthorize() {
+	ui, err := New(Spec("pets.yaml", securedSpec()), DevMode(),
+		PreauthorizeAPIKey("api_key", "abc123"),
+		PreauthorizeBasic("basic", "alice", `se"cret`))
+	require.NoError(suite.T(), err)
+	_, body := get(ui, "/"+InitializerFilename)
+	assert.Contains(suite.T(), body, `    onComplete: function () {
+      window.ui.preauthorizeApiKey("api_key", "abc123");
+      window.ui.preauthorizeBasic("basic", "alice", "se\"cret");
+    },`)
+	assert.Empty(suite.T(), ui.Warnings())
+}
+
+func (suite *PreauthSuite) TestUndefinedScheme() {
+	ui, err := New(Spec("pets.yaml", securedSpec()), DevMode(), PreauthorizeAPIKey("oauth", "abc123"))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"preauthorized security scheme oauth is not defined"}, ui.Warnings())
+}
+
+func (suite *PreauthSuite) TestFromEnv() {
+	suite.T().Setenv("PREAUTHORIZE_API_KEY", "api_key=abc123, bearer=eyJhbGciOi")
+	suite.T().Setenv("PREAUTHORIZE_BASIC", "basic=alice:se:cret")
+	ui, err := New(Spec("pets.yaml", securedSpec()), DevMode(), PreauthorizeFromEnv())
+	require.NoError(suite.T(), err)
+	_, body := get(ui, "/"+InitializerFilename)
+	assert.Contains(suite.T(), body, `window.ui.preauthorizeApiKey("api_key", "abc123");`)
+	assert.Contains(suite.T(), body, `window.ui.preauthorizeApiKey("bearer", "eyJhbGciOi");`)
+	assert.Contains(suite.T(), body, `window.ui.preauthorizeBasic("basic", "alice", "se:cret");`)
+
+	suite.T().Setenv("PREAUTHORIZE_BASIC", "basic=alice")
+	_, err = New(Spec("pets.yaml", securedSpec()), DevMode(), PreauthorizeFromEnv())
+	require.Error(suite.T(), err)
+	assert.NotContains(suite.T(), err.Error(), "alice")
+}
+
+func (suite *PreauthSuite) TestGuard() {
+	_, err := New(Spec("pets.yaml", securedSpec()), PreauthorizeAPIKey("api_key", "abc123"))
+	assert.ErrorContains(suite.T(), err, "requires DevMode")
+
+	_, err = New(Spec("pets.yaml", securedSpec()), DevMode(), Production(), PreauthorizeAPIKey("api_key", "abc123"))
+	assert.ErrorContains(suite.T(), err, "cannot be used in production")
+
+	// Without credentials in the environment, nothing is preauthorized.
+	ui, err := New(Spec("pets.yaml", securedSpec()), Production(), PreauthorizeFromEnv())
+	require.NoError(suite.T(), err)
+	_, body := get(ui, "/"+InitializerFilename)
+	assert.NotContains(suite.T(), body, "preauthorize")
+}
+
+func TestPreauth(t *testing.T) {
+	suite.Run(t, new(PreauthSuite))
+}
@@ -0,0 +1,122 @@
+/*
+ *  reload.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ReloadPath is the path of the server-sent events announcing changes of the
+// spec in development mode, relative to the handler.
+const ReloadPath = "live-reload"
+
+// reloadKeepAlive is the interval in which comments are sent to keep idle event streams open.
+const reloadKeepAlive = 30 * time.Second
+
+// DevMode enables features meant for development only. Open browser tabs
+// reload the spec in place whenever it changes on the server, for example
+// when it is published through the admin API, fetched from upstream services
+// or put into the store. The plugin doing so is added to the generated
+// initializer only, not to one passed to InitializerContent. DevMode is also
+// required by PreauthorizeAPIKey and PreauthorizeBasic.
+func DevMode() HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.devMode = true
+	}
+}
+
+// Production marks the handler as serving a production deployment. New fails
+// if features meant for development only are enabled as well, so that they
+// cannot be switched on by accident, for example through the environment.
+func Production() HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.production = true
+	}
+}
+
+// reloads notifies the event streams of open browser tabs of changes of the spec.
+type reloads struct {
+	mu        sync.Mutex
+	listeners map[chan struct{}]bool
+}
+
+func newReloads() *reloads {
+	return &reloads{listeners: make(map[chan struct{}]bool)}
+}
+
+// subscribe returns a channel receiving a value after each change of the spec.
+// Changes in quick succession are combined.
+func (r *reloads) subscribe() chan struct{} {
+	c := make(chan struct{}, 1)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners[c] = true
+	return c
+}
+
+func (r *reloads) unsubscribe(c chan struct{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.listeners, c)
+}
+
+func (r *reloads) notify() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for c := range r.listeners {
+		select {
+		case c <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// serveReloads streams a "reload" event whenever the spec changes, until the client disconnects.
+func (ui *SwaggerUi) serveReloads(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	changes := ui.reloads.subscribe()
+	defer ui.reloads.unsubscribe(changes)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 1000\n\n")
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(reloadKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-changes:
+			fmt.Fprint(w, "event: reload\ndata: {}\n\n")
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,78 @@
+/*
+ *  reload_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReloadSuite struct {
+	suite.Suite
+}
+
+func (suite *ReloadSuite) TestInitializer() {
+	ui, err := New(Spec("pets.yaml", versionedSpec("1.0", "")), DevMode())
+	require.NoError(suite.T(), err)
+	_, body := get(ui, "/"+InitializerFilename)
+	assert.Contains(suite.T(), body, `new EventSource("./live-reload")`)
+	assert.Contains(suite.T(), body, "parseDeepLinkHash(hash)")
+	assert.Contains(suite.T(), body, "SwaggerUIBundle.plugins.DownloadUrl,\n      LiveReloadPlugin\n")
+
+	ui, err = New(Spec("pets.yaml", versionedSpec("1.0", "")))
+	require.NoError(suite.T(), err)
+	_, body = get(ui, "/"+InitializerFilename)
+	assert.NotContains(suite.T(), body, "LiveReloadPlugin")
+	code, _ := get(ui, "/"+ReloadPath)
+	assert.Equal(suite.T(), http.StatusNotFound, code, "live reload is available in development mode only")
+}
+
+func (suite *ReloadSuite) TestEvents() {
+	ui, err := New(Spec("pets.yaml", versionedSpec("1.0", "")), DevMode())
+	require.NoError(suite.T(), err)
+	srv := httptest.NewServer(ui)
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL + "/" + ReloadPath)
+	require.NoError(suite.T(), err)
+	defer res.Body.Close()
+	assert.Equal(suite.T(), "text/event-stream", res.Header.Get("Content-Type"))
+	events := bufio.NewReader(res.Body)
+	line, err := events.ReadString('\n')
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), strings.HasPrefix(line, "retry:"))
+
+	_, err = ui.Publish("alice", versionedSpec("2.0", ""))
+	require.NoError(suite.T(), err)
+	for line != "event: reload\n" {
+		line, err = events.ReadString('\n')
+		require.NoError(suite.T(), err)
+	}
+}
+
+func TestReload(t *testing.T) {
+	suite.Run(t, new(ReloadSuite))
+}
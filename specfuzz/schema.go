@@ -0,0 +1,462 @@
+/*
+ *  schema.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package specfuzz
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxDepth limits the recursion into nested and recursive schemas.
+const maxDepth = 8
+
+type invalid struct {
+	value  interface{}
+	reason string
+}
+
+// schemaType returns the type of s, inferring it from other keywords if it is missing.
+// For OpenAPI 3.1 type arrays the first non-null type is used.
+func schemaType(s map[string]interface{}) string {
+	switch t := s["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, v := range t {
+			if str, ok := v.(string); ok && str != "null" {
+				return str
+			}
+		}
+	}
+	switch {
+	case s["properties"] != nil:
+		return "object"
+	case s["items"] != nil:
+		return "array"
+	}
+	return ""
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func integer(v interface{}) (int, bool) {
+	n, ok := number(v)
+	return int(n), ok
+}
+
+// valid returns a value that satisfies s.
+func (g *Generator) valid(s map[string]interface{}, depth int) interface{} {
+	if s == nil {
+		return nil
+	}
+	s, _ = g.resolve(s).(map[string]interface{})
+	if s == nil {
+		return nil
+	}
+	for _, key := range []string{"const", "default", "example"} {
+		if v, ok := s[key]; ok {
+			return v
+		}
+	}
+	if enum, ok := s["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+	if all, ok := s["allOf"].([]interface{}); ok {
+		merged := make(map[string]interface{})
+		for _, part := range all {
+			if obj, ok := g.valid(asSchema(part), depth+1).(map[string]interface{}); ok {
+				for k, v := range obj {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if alts, ok := s[key].([]interface{}); ok && len(alts) > 0 {
+			return g.valid(asSchema(alts[0]), depth+1)
+		}
+	}
+
+	switch schemaType(s) {
+	case "integer":
+		return int(validNumber(s, 1))
+	case "number":
+		return validNumber(s, 0)
+	case "boolean":
+		return true
+	case "array":
+		n, _ := integer(s["minItems"])
+		if n == 0 {
+			n = 1
+		}
+		items := make([]interface{}, 0, n)
+		if depth < maxDepth {
+			for i := 0; i < n; i++ {
+				items = append(items, g.valid(asSchema(s["items"]), depth+1))
+			}
+		}
+		return items
+	case "object":
+		obj := make(map[string]interface{})
+		if depth >= maxDepth {
+			return obj
+		}
+		props, _ := s["properties"].(map[string]interface{})
+		for _, name := range sortedKeys(props) {
+			obj[name] = g.valid(asSchema(props[name]), depth+1)
+		}
+		return obj
+	default:
+		return validString(s)
+	}
+}
+
+// validNumber returns a number within the bounds of s, preferring one next to
+// the lower bound. step is 1 for integers and 0 for numbers, which keep a
+// distance of 0.5 to their bounds where possible.
+func validNumber(s map[string]interface{}, step float64) float64 {
+	lower := numericBound(s, "minimum", "exclusiveMinimum")
+	upper := numericBound(s, "maximum", "exclusiveMaximum")
+	candidates := []float64{1.5}
+	if step != 0 {
+		candidates[0] = 1
+	}
+	if lower.set {
+		v := lower.value + 0.5
+		if step != 0 {
+			v, _ = lower.closest(step)
+			v = math.Ceil(v)
+		}
+		candidates = append([]float64{v}, candidates...)
+	}
+	if upper.set {
+		v := upper.value - 0.5
+		if step != 0 {
+			v, _ = upper.closest(-step)
+			v = math.Floor(v)
+		}
+		candidates = append(candidates, v)
+	}
+	if lower.set && upper.set && step == 0 {
+		// Narrow ranges only fit the middle.
+		candidates = append(candidates, (lower.value+upper.value)/2)
+	}
+	for _, v := range candidates {
+		if lower.allows(v, -1) && upper.allows(v, 1) {
+			return v
+		}
+	}
+	return candidates[0]
+}
+
+func validString(s map[string]interface{}) string {
+	var v string
+	switch s["format"] {
+	case "date-time":
+		v = "2023-01-02T15:04:05Z"
+	case "date":
+		v = "2023-01-02"
+	case "uuid":
+		v = "123e4567-e89b-12d3-a456-426614174000"
+	case "email":
+		v = "user@example.com"
+	case "uri", "url":
+		v = "https://example.com/"
+	case "ipv4":
+		v = "192.0.2.1"
+	case "byte":
+		v = "Zm9v"
+	default:
+		v = "test"
+	}
+	if min, ok := integer(s["minLength"]); ok && len(v) < min {
+		v += strings.Repeat("x", min-len(v))
+	}
+	if max, ok := integer(s["maxLength"]); ok && len(v) > max {
+		v = v[:max]
+	}
+	return v
+}
+
+// boundaries returns valid values at the edges of what s allows.
+func (g *Generator) boundaries(s map[string]interface{}) []interface{} {
+	s, _ = g.resolve(s).(map[string]interface{})
+	if s == nil {
+		return nil
+	}
+	var values []interface{}
+	if enum, ok := s["enum"].([]interface{}); ok {
+		if len(enum) > 1 {
+			values = append(values, enum[1:]...)
+		}
+		return values
+	}
+	switch schemaType(s) {
+	case "integer", "number":
+		lower := numericBound(s, "minimum", "exclusiveMinimum")
+		upper := numericBound(s, "maximum", "exclusiveMaximum")
+		step := 0.0
+		if schemaType(s) == "integer" {
+			step = 1
+		}
+		// Exclusive bounds of numbers have no closest valid value, so only
+		// integers get a boundary value next to them.
+		if v, ok := lower.closest(step); ok && upper.allows(v, 1) {
+			values = append(values, v)
+		}
+		if v, ok := upper.closest(-step); ok && lower.allows(v, -1) {
+			values = append(values, v)
+		}
+		if !lower.set && upper.allows(0, 1) {
+			values = append(values, 0)
+		}
+	case "string":
+		if _, hasEnum := s["enum"]; hasEnum || s["format"] != nil || s["pattern"] != nil {
+			break
+		}
+		if min, ok := integer(s["minLength"]); ok {
+			values = append(values, strings.Repeat("a", min))
+		} else {
+			values = append(values, "")
+		}
+		if max, ok := integer(s["maxLength"]); ok {
+			values = append(values, strings.Repeat("z", max))
+		}
+	case "array":
+		if min, _ := integer(s["minItems"]); min == 0 {
+			values = append(values, []interface{}{})
+		}
+		if max, ok := integer(s["maxItems"]); ok && max <= 64 {
+			items := make([]interface{}, max)
+			for i := range items {
+				items[i] = g.valid(asSchema(s["items"]), 1)
+			}
+			values = append(values, items)
+		}
+	case "object":
+		// Only the required properties are the minimal valid object.
+		required := stringSlice(s["required"])
+		props, _ := s["properties"].(map[string]interface{})
+		if len(props) > len(required) {
+			obj := make(map[string]interface{})
+			for _, name := range required {
+				obj[name] = g.valid(asSchema(props[name]), 1)
+			}
+			values = append(values, obj)
+		}
+	}
+	return values
+}
+
+// invalids returns values violating s, each with a description of the violation.
+func (g *Generator) invalids(s map[string]interface{}) []invalid {
+	s, _ = g.resolve(s).(map[string]interface{})
+	if s == nil {
+		return nil
+	}
+	var values []invalid
+	if enum, ok := s["enum"].([]interface{}); ok && len(enum) > 0 {
+		values = append(values, invalid{"not-in-enum-" + strconv.Itoa(len(enum)), "value not in enum"})
+	}
+	switch schemaType(s) {
+	case "integer":
+		values = append(values,
+			invalid{"not-a-number", "wrong type"},
+			invalid{1.5, "fraction for integer"})
+	case "number":
+		values = append(values, invalid{"not-a-number", "wrong type"})
+	case "boolean":
+		values = append(values, invalid{"not-a-bool", "wrong type"})
+	case "string":
+		values = append(values, invalid{map[string]interface{}{"not": "a string"}, "wrong type"})
+		if min, ok := integer(s["minLength"]); ok && min > 0 {
+			values = append(values, invalid{strings.Repeat("a", min-1), "shorter than minLength"})
+		}
+		if max, ok := integer(s["maxLength"]); ok {
+			values = append(values, invalid{strings.Repeat("z", max+1), "longer than maxLength"})
+		}
+		if s["format"] != nil {
+			values = append(values, invalid{"%not-" + fmt.Sprint(s["format"]), "malformed " + fmt.Sprint(s["format"])})
+		}
+	case "array":
+		values = append(values, invalid{"not-an-array", "wrong type"})
+		if min, ok := integer(s["minItems"]); ok && min > 0 {
+			values = append(values, invalid{[]interface{}{}, "fewer than minItems"})
+		}
+	case "object":
+		values = append(values, invalid{[]interface{}{"not", "an", "object"}, "wrong type"})
+		props, _ := s["properties"].(map[string]interface{})
+		full, _ := g.valid(s, 0).(map[string]interface{})
+		for _, name := range stringSlice(s["required"]) {
+			obj := make(map[string]interface{}, len(full))
+			for k, v := range full {
+				if k != name {
+					obj[k] = v
+				}
+			}
+			values = append(values, invalid{obj, fmt.Sprintf("required field %q missing", name)})
+		}
+		for _, name := range sortedKeys(props) {
+			for _, inv := range g.invalids(asSchema(props[name])) {
+				if _, isObject := inv.value.(map[string]interface{}); isObject {
+					// Nested objects are covered by the wrong type case of their parent.
+					continue
+				}
+				obj := make(map[string]interface{}, len(full))
+				for k, v := range full {
+					obj[k] = v
+				}
+				obj[name] = inv.value
+				values = append(values, invalid{obj, fmt.Sprintf("field %q: %s", name, inv.reason)})
+			}
+		}
+	}
+	// Numeric bounds are checked independent of the type to cover both integers and numbers.
+	// An exclusive bound itself is the closest invalid value.
+	switch lower := numericBound(s, "minimum", "exclusiveMinimum"); {
+	case lower.exclusive:
+		values = append(values, invalid{lower.value, "not above exclusiveMinimum"})
+	case lower.set:
+		values = append(values, invalid{lower.value - 1, "below minimum"})
+	}
+	switch upper := numericBound(s, "maximum", "exclusiveMaximum"); {
+	case upper.exclusive:
+		values = append(values, invalid{upper.value, "not below exclusiveMaximum"})
+	case upper.set:
+		values = append(values, invalid{upper.value + 1, "above maximum"})
+	}
+	return values
+}
+
+// bound is a lower or upper limit of a numeric schema.
+type bound struct {
+	value     float64
+	set       bool
+	exclusive bool
+}
+
+// numericBound returns the bound given by key, which is made exclusive by the
+// boolean exclusive of OpenAPI 3.0 or replaced by the numeric one of 3.1.
+func numericBound(s map[string]interface{}, key, exclusive string) bound {
+	if v, ok := number(s[exclusive]); ok {
+		return bound{value: v, set: true, exclusive: true}
+	}
+	if v, ok := number(s[key]); ok {
+		return bound{value: v, set: true, exclusive: s[exclusive] == true}
+	}
+	return bound{}
+}
+
+// closest returns the valid value closest to b, stepping away from an
+// exclusive bound by step. It reports false if there is no such value.
+func (b bound) closest(step float64) (float64, bool) {
+	switch {
+	case !b.set:
+		return 0, false
+	case !b.exclusive:
+		return b.value, true
+	case step == 0:
+		return 0, false
+	}
+	return b.value + step, true
+}
+
+// allows reports whether v is within b, with sign 1 for an upper bound and -1 for a lower one.
+func (b bound) allows(v, sign float64) bool {
+	if !b.set {
+		return true
+	}
+	if b.exclusive {
+		return sign*v < sign*b.value
+	}
+	return sign*v <= sign*b.value
+}
+
+func asSchema(v interface{}) map[string]interface{} {
+	s, _ := v.(map[string]interface{})
+	return s
+}
+
+func stringSlice(v interface{}) []string {
+	list, _ := v.([]interface{})
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// stringify renders v the way it appears in a path, query, header or cookie.
+func stringify(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case []interface{}:
+		parts := make([]string, len(t))
+		for i, item := range t {
+			parts[i] = stringify(item)
+		}
+		return strings.Join(parts, ",")
+	case map[string]interface{}:
+		return string(encode(t))
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+func encode(v interface{}) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		// Values stem from YAML and may contain maps with non-string keys.
+		return []byte(fmt.Sprintf("%q", fmt.Sprint(v)))
+	}
+	return b
+}
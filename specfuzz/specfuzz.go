@@ -0,0 +1,498 @@
+/*
+ *  specfuzz.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// Package specfuzz generates valid and deliberately invalid requests from the
+// parameter and body schemas of a spec and feeds them to an http.Handler.
+//
+// It integrates with Go's native fuzzing: Seed adds the generated requests as
+// corpus entries and Fuzz runs the handler for every input, failing on panics
+// and 5xx responses so that the offending input is stored as a reproducible
+// corpus entry under testdata/fuzz.
+package specfuzz
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// methods lists the HTTP methods of a path item in the order they are generated.
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Case is a single request generated from the spec.
+type Case struct {
+	// OperationID is the operationId of the operation, or "METHOD path" if none is given.
+	OperationID string
+	Method      string
+	// Target is the request URI, that is the expanded path including the query.
+	Target      string
+	Header      http.Header
+	ContentType string
+	Body        []byte
+	// Valid is true if the request is expected to conform to the spec.
+	Valid bool
+	// Reason describes how an invalid request violates the spec.
+	Reason string
+}
+
+// Request returns an *http.Request for c suitable for passing to a handler directly.
+func (c Case) Request() *http.Request {
+	r := httptest.NewRequest(c.Method, c.Target, bytes.NewReader(c.Body))
+	for k, v := range c.Header {
+		r.Header[k] = append([]string(nil), v...)
+	}
+	if c.ContentType != "" {
+		r.Header.Set("Content-Type", c.ContentType)
+	}
+	return r
+}
+
+func (c Case) String() string {
+	validity := "valid"
+	if !c.Valid {
+		validity = "invalid: " + c.Reason
+	}
+	return fmt.Sprintf("%s %s %s (%s)", c.OperationID, c.Method, c.Target, validity)
+}
+
+// Generator derives request cases from a spec.
+type Generator struct {
+	root       map[string]interface{}
+	operations []operation
+}
+
+type operation struct {
+	id         string
+	method     string
+	path       string
+	parameters []map[string]interface{}
+	body       map[string]interface{}
+	mediaType  string
+	required   bool
+}
+
+// New parses spec, which may be YAML or JSON, and returns a Generator for it.
+// Both OpenAPI 3.x and Swagger 2.0 body parameters are understood.
+func New(spec []byte) (*Generator, error) {
+	var root = make(map[string]interface{})
+	if err := yaml.Unmarshal(spec, &root); err != nil {
+		return nil, fmt.Errorf("parsing spec: %w", err)
+	}
+	paths, _ := root["paths"].(map[string]interface{})
+	if len(paths) == 0 {
+		return nil, errors.New("spec contains no paths")
+	}
+
+	g := &Generator{root: root}
+
+	keys := make([]string, 0, len(paths))
+	for k := range paths {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, p := range keys {
+		item, _ := g.resolve(paths[p]).(map[string]interface{})
+		if item == nil {
+			continue
+		}
+		shared := g.parameters(item["parameters"])
+		for _, m := range methods {
+			raw, ok := item[m].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			g.operations = append(g.operations, g.operation(p, m, raw, shared))
+		}
+	}
+	return g, nil
+}
+
+func (g *Generator) operation(path, method string, raw map[string]interface{}, shared []map[string]interface{}) operation {
+	op := operation{
+		method: strings.ToUpper(method),
+		path:   path,
+	}
+	if id, ok := raw["operationId"].(string); ok && id != "" {
+		op.id = id
+	} else {
+		op.id = op.method + " " + path
+	}
+
+	// Operation level parameters override path level ones with the same name and location.
+	own := g.parameters(raw["parameters"])
+	seen := make(map[string]bool)
+	for _, p := range own {
+		seen[paramKey(p)] = true
+	}
+	for _, p := range shared {
+		if !seen[paramKey(p)] {
+			op.parameters = append(op.parameters, p)
+		}
+	}
+	op.parameters = append(op.parameters, own...)
+
+	// Swagger 2.0 passes the body as a parameter.
+	for i, p := range op.parameters {
+		if p["in"] == "body" {
+			op.body, _ = g.resolve(p["schema"]).(map[string]interface{})
+			op.required, _ = p["required"].(bool)
+			op.mediaType = "application/json"
+			op.parameters = append(op.parameters[:i:i], op.parameters[i+1:]...)
+			break
+		}
+	}
+
+	if rb, ok := g.resolve(raw["requestBody"]).(map[string]interface{}); ok {
+		op.required, _ = rb["required"].(bool)
+		content, _ := rb["content"].(map[string]interface{})
+		op.mediaType, op.body = pickMediaType(content)
+		op.body, _ = g.resolve(op.body).(map[string]interface{})
+	}
+	return op
+}
+
+// pickMediaType prefers JSON media types, since only those can be generated meaningfully.
+func pickMediaType(content map[string]interface{}) (string, map[string]interface{}) {
+	types := make([]string, 0, len(content))
+	for k := range content {
+		types = append(types, k)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		if strings.Contains(t, "json") {
+			mt, _ := content[t].(map[string]interface{})
+			schema, _ := mt["schema"].(map[string]interface{})
+			return t, schema
+		}
+	}
+	if len(types) > 0 {
+		mt, _ := content[types[0]].(map[string]interface{})
+		schema, _ := mt["schema"].(map[string]interface{})
+		return types[0], schema
+	}
+	return "", nil
+}
+
+func paramKey(p map[string]interface{}) string {
+	return fmt.Sprintf("%v:%v", p["in"], p["name"])
+}
+
+func (g *Generator) parameters(raw interface{}) []map[string]interface{} {
+	list, _ := raw.([]interface{})
+	var params []map[string]interface{}
+	for _, item := range list {
+		if p, ok := g.resolve(item).(map[string]interface{}); ok {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// resolve follows local references until a value without $ref is reached.
+// Unresolvable or cyclic references yield nil.
+func (g *Generator) resolve(v interface{}) interface{} {
+	for depth := 0; depth < 32; depth++ {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		ref, ok := m["$ref"].(string)
+		if !ok {
+			return v
+		}
+		v = g.lookup(ref)
+	}
+	return nil
+}
+
+func (g *Generator) lookup(ref string) interface{} {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil
+	}
+	var cur interface{} = g.root
+	for _, part := range strings.Split(ref[2:], "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		if unescaped, err := url.PathUnescape(part); err == nil {
+			part = unescaped
+		}
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		cur = m[part]
+	}
+	return cur
+}
+
+// Operations returns the identifiers of all operations found in the spec.
+func (g *Generator) Operations() []string {
+	ids := make([]string, len(g.operations))
+	for i, op := range g.operations {
+		ids[i] = op.id
+	}
+	return ids
+}
+
+// Cases returns all generated cases: for every operation one valid request,
+// valid requests using boundary values, and invalid requests with wrong types,
+// out of range values and missing required parameters or fields.
+// The result is deterministic for a given spec.
+func (g *Generator) Cases() []Case {
+	var cases []Case
+	for _, op := range g.operations {
+		cases = append(cases, g.casesFor(op)...)
+	}
+	return cases
+}
+
+func (g *Generator) casesFor(op operation) []Case {
+	base := make(map[string]interface{}, len(op.parameters))
+	for _, p := range op.parameters {
+		base[paramKey(p)] = g.valid(g.paramSchema(p), 0)
+	}
+	var body interface{}
+	if op.body != nil {
+		body = g.valid(op.body, 0)
+	}
+
+	cases := []Case{g.build(op, base, body, true, "")}
+
+	for _, p := range op.parameters {
+		schema := g.paramSchema(p)
+		name, _ := p["name"].(string)
+		for _, v := range g.boundaries(schema) {
+			cases = append(cases, g.build(op, with(base, paramKey(p), v), body, true, ""))
+		}
+		for _, inv := range g.invalids(schema) {
+			cases = append(cases, g.build(op, with(base, paramKey(p), inv.value), body, false,
+				fmt.Sprintf("parameter %q: %s", name, inv.reason)))
+		}
+		if required, _ := p["required"].(bool); required || p["in"] == "path" {
+			if p["in"] == "path" {
+				cases = append(cases, g.build(op, with(base, paramKey(p), ""), body, false,
+					fmt.Sprintf("path parameter %q empty", name)))
+				continue
+			}
+			cases = append(cases, g.build(op, without(base, paramKey(p)), body, false,
+				fmt.Sprintf("required parameter %q missing", name)))
+		}
+	}
+
+	if op.body != nil {
+		for _, v := range g.boundaries(op.body) {
+			cases = append(cases, g.build(op, base, v, true, ""))
+		}
+		for _, inv := range g.invalids(op.body) {
+			cases = append(cases, g.build(op, base, inv.value, false, "body: "+inv.reason))
+		}
+		if op.required {
+			c := g.build(op, base, nil, false, "required body missing")
+			c.Body = nil
+			cases = append(cases, c)
+		}
+		c := g.build(op, base, nil, false, "malformed body")
+		c.Body = []byte("{")
+		cases = append(cases, c)
+	}
+	return cases
+}
+
+func (g *Generator) paramSchema(p map[string]interface{}) map[string]interface{} {
+	if s, ok := g.resolve(p["schema"]).(map[string]interface{}); ok {
+		return s
+	}
+	// Swagger 2.0 declares the type on the parameter itself.
+	return p
+}
+
+func with(m map[string]interface{}, k string, v interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(m))
+	for key, val := range m {
+		c[key] = val
+	}
+	c[k] = v
+	return c
+}
+
+func without(m map[string]interface{}, k string) map[string]interface{} {
+	c := with(m, k, nil)
+	delete(c, k)
+	return c
+}
+
+func (g *Generator) build(op operation, values map[string]interface{}, body interface{}, valid bool, reason string) Case {
+	c := Case{
+		OperationID: op.id,
+		Method:      op.method,
+		Header:      make(http.Header),
+		Valid:       valid,
+		Reason:      reason,
+	}
+
+	path := op.path
+	query := url.Values{}
+	var cookies []string
+	for _, p := range op.parameters {
+		v, present := values[paramKey(p)]
+		if !present {
+			continue
+		}
+		name, _ := p["name"].(string)
+		s := stringify(v)
+		switch p["in"] {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+name+"}", url.PathEscape(s))
+		case "query":
+			if list, ok := v.([]interface{}); ok {
+				for _, item := range list {
+					query.Add(name, stringify(item))
+				}
+			} else {
+				query.Set(name, s)
+			}
+		case "header":
+			c.Header.Set(name, s)
+		case "cookie":
+			cookies = append(cookies, name+"="+url.QueryEscape(s))
+		}
+	}
+	if len(cookies) > 0 {
+		c.Header.Set("Cookie", strings.Join(cookies, "; "))
+	}
+	c.Target = path
+	if len(query) > 0 {
+		c.Target += "?" + query.Encode()
+	}
+
+	if op.body != nil {
+		c.ContentType = op.mediaType
+		if body != nil {
+			c.Body = encode(body)
+		}
+	}
+	return c
+}
+
+// Seed adds all cases as corpus entries to f.
+// The entries match the arguments of the fuzz target used by Fuzz, with the
+// header and cookie parameters serialized as a header block.
+func Seed(f *testing.F, cases []Case) {
+	for _, c := range cases {
+		f.Add(c.Method, c.Target, encodeHeader(c.Header), c.ContentType, c.Body)
+	}
+}
+
+// encodeHeader serializes h as a header block, as in "X-Tenant: a\r\n".
+func encodeHeader(h http.Header) string {
+	var b strings.Builder
+	h.Write(&b)
+	return b.String()
+}
+
+// Fuzz runs h for every fuzz input. Inputs whose request cannot be constructed
+// are skipped. A panic in h or a response status of 500 or above fails the
+// input, which makes the fuzzing engine record it as a corpus entry.
+func Fuzz(f *testing.F, h http.Handler) {
+	f.Fuzz(func(t *testing.T, method, target, header, contentType string, body []byte) {
+		r, err := newRequest(method, target, header, contentType, body)
+		if err != nil {
+			t.Skip(err)
+		}
+		if err := serve(h, r); err != nil {
+			t.Fatalf("%s %s: %s", method, target, err)
+		}
+	})
+}
+
+// Check serves every case with h and reports a test failure for each case
+// that panics or results in a server error. It is meant for property style
+// tests that do not use the fuzzing engine.
+func Check(t testing.TB, h http.Handler, cases []Case) {
+	t.Helper()
+	for _, c := range cases {
+		if err := serve(h, c.Request()); err != nil {
+			t.Errorf("%s: %s", c, err)
+		}
+	}
+}
+
+// newRequest constructs the request of a fuzz input. header is a header block
+// as written by encodeHeader.
+func newRequest(method, target, header, contentType string, body []byte) (r *http.Request, err error) {
+	if !validMethod(method) {
+		return nil, fmt.Errorf("invalid method %q", method)
+	}
+	if !strings.HasPrefix(target, "/") {
+		return nil, fmt.Errorf("invalid target %q", target)
+	}
+	fields, err := textproto.NewReader(bufio.NewReader(strings.NewReader(header + "\r\n"))).ReadMIMEHeader()
+	if err != nil {
+		return nil, fmt.Errorf("invalid header: %w", err)
+	}
+	// httptest.NewRequest panics on malformed input instead of returning an error.
+	defer func() {
+		if p := recover(); p != nil {
+			r, err = nil, fmt.Errorf("invalid request: %v", p)
+		}
+	}()
+	r = httptest.NewRequest(method, target, bytes.NewReader(body))
+	for k, v := range fields {
+		r.Header[k] = v
+	}
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	return r, nil
+}
+
+func validMethod(m string) bool {
+	if m == "" {
+		return false
+	}
+	for _, c := range m {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func serve(h http.Handler, r *http.Request) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("handler panicked: %v", p)
+		}
+	}()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code >= http.StatusInternalServerError {
+		return fmt.Errorf("server error %d: %s", w.Code, strings.TrimSpace(w.Body.String()))
+	}
+	return nil
+}
@@ -0,0 +1,330 @@
+/*
+ *  specfuzz_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package specfuzz
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const petstore = `
+openapi: 3.0.3
+info:
+  title: Petstore
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - name: limit
+          in: query
+          required: true
+          schema:
+            type: integer
+            minimum: 1
+            maximum: 100
+        - name: X-Tenant
+          in: header
+          schema:
+            type: string
+            enum: [a, b]
+    post:
+      operationId: createPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        schema:
+          type: string
+          format: uuid
+    get:
+      operationId: showPet
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+          minLength: 1
+          maxLength: 10
+        tag:
+          type: string
+        friends:
+          type: array
+          items:
+            $ref: '#/components/schemas/Pet'
+`
+
+// strictHandler validates the petstore requests by hand and never fails with a 5xx.
+func strictHandler(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/pets":
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil || limit < 1 || limit > 100 {
+			http.Error(w, "bad limit", http.StatusBadRequest)
+			return
+		}
+	case r.Method == http.MethodPost && r.URL.Path == "/pets":
+		var pet struct {
+			Name string `json:"name"`
+		}
+		b, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(b, &pet); err != nil || pet.Name == "" {
+			http.Error(w, "bad pet", http.StatusBadRequest)
+			return
+		}
+	case strings.HasPrefix(r.URL.Path, "/pets/"):
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+type SpecFuzzSuite struct {
+	suite.Suite
+	gen *Generator
+}
+
+func (suite *SpecFuzzSuite) SetupTest() {
+	g, err := New([]byte(petstore))
+	require.NoError(suite.T(), err)
+	suite.gen = g
+}
+
+func (suite *SpecFuzzSuite) TestOperations() {
+	assert.Equal(suite.T(), []string{"listPets", "createPet", "showPet"}, suite.gen.Operations())
+}
+
+func (suite *SpecFuzzSuite) TestInvalidSpec() {
+	_, err := New([]byte("openapi: 3.0.0\n"))
+	assert.Error(suite.T(), err)
+	_, err = New([]byte("\t:"))
+	assert.Error(suite.T(), err)
+}
+
+func (suite *SpecFuzzSuite) TestCasesAreDeterministic() {
+	assert.Equal(suite.T(), suite.gen.Cases(), suite.gen.Cases())
+}
+
+func (suite *SpecFuzzSuite) TestCases() {
+	var valid, invalid int
+	reasons := make(map[string]bool)
+	for _, c := range suite.gen.Cases() {
+		if c.Valid {
+			valid++
+		} else {
+			invalid++
+			reasons[c.OperationID+": "+c.Reason] = true
+		}
+	}
+	assert.NotZero(suite.T(), valid)
+	assert.NotZero(suite.T(), invalid)
+
+	for _, expected := range []string{
+		`listPets: required parameter "limit" missing`,
+		`listPets: parameter "limit": below minimum`,
+		`listPets: parameter "limit": above maximum`,
+		`listPets: parameter "limit": wrong type`,
+		`listPets: parameter "X-Tenant": value not in enum`,
+		`createPet: body: required field "name" missing`,
+		`createPet: body: field "name": longer than maxLength`,
+		`createPet: required body missing`,
+		`showPet: path parameter "petId" empty`,
+	} {
+		assert.Truef(suite.T(), reasons[expected], "missing case %s", expected)
+	}
+}
+
+func (suite *SpecFuzzSuite) TestValidCase() {
+	c := suite.gen.Cases()[0]
+	assert.Equal(suite.T(), "listPets", c.OperationID)
+	assert.True(suite.T(), c.Valid)
+	assert.Equal(suite.T(), "/pets?limit=1", c.Target)
+	assert.Equal(suite.T(), "a", c.Header.Get("X-Tenant"))
+}
+
+func (suite *SpecFuzzSuite) TestValidBody() {
+	for _, c := range suite.gen.Cases() {
+		if c.OperationID == "createPet" && c.Valid {
+			var pet map[string]interface{}
+			require.NoError(suite.T(), json.Unmarshal(c.Body, &pet))
+			assert.NotEmpty(suite.T(), pet["name"])
+			assert.Equal(suite.T(), "application/json", c.ContentType)
+		}
+	}
+}
+
+func (suite *SpecFuzzSuite) TestStrictHandler() {
+	h := http.HandlerFunc(strictHandler)
+	Check(suite.T(), h, suite.gen.Cases())
+
+	for _, c := range suite.gen.Cases() {
+		if c.OperationID != "listPets" {
+			continue
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, c.Request())
+		if c.Valid {
+			assert.Equalf(suite.T(), http.StatusOK, w.Code, "%s", c)
+		}
+	}
+}
+
+func (suite *SpecFuzzSuite) TestServeDetectsFailures() {
+	panicking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { panic("boom") })
+	failing := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "oops", http.StatusInternalServerError)
+	})
+	c := suite.gen.Cases()[0]
+	assert.ErrorContains(suite.T(), serve(panicking, c.Request()), "panicked")
+	assert.ErrorContains(suite.T(), serve(failing, c.Request()), "500")
+}
+
+func (suite *SpecFuzzSuite) TestNewRequest() {
+	_, err := newRequest("get", "/", "", "", nil)
+	assert.Error(suite.T(), err)
+	_, err = newRequest("GET", "pets", "", "", nil)
+	assert.Error(suite.T(), err)
+	_, err = newRequest("GET", "/pets", "no colon\r\n", "", nil)
+	assert.Error(suite.T(), err)
+	r, err := newRequest("POST", "/pets", "", "application/json", []byte("{}"))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "application/json", r.Header.Get("Content-Type"))
+}
+
+func (suite *SpecFuzzSuite) TestHeaderRoundTrip() {
+	for _, c := range suite.gen.Cases() {
+		r, err := newRequest(c.Method, c.Target, encodeHeader(c.Header), c.ContentType, c.Body)
+		require.NoError(suite.T(), err, c.String())
+		for name := range c.Header {
+			assert.Equal(suite.T(), c.Header.Values(name), r.Header.Values(name), c.String())
+		}
+	}
+}
+
+func (suite *SpecFuzzSuite) TestNumericBoundaries() {
+	for _, tC := range []struct {
+		desc     string
+		schema   map[string]interface{}
+		expected []interface{}
+	}{
+		{"unbounded", map[string]interface{}{"type": "integer"}, []interface{}{0}},
+		{"inclusive", map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 10}, []interface{}{1.0, 10.0}},
+		{"negative maximum", map[string]interface{}{"type": "integer", "maximum": -5}, []interface{}{-5.0}},
+		{"exclusive 3.0", map[string]interface{}{"type": "integer", "minimum": 0, "exclusiveMinimum": true}, []interface{}{1.0}},
+		{"exclusive 3.1", map[string]interface{}{"type": "integer", "exclusiveMinimum": 0, "exclusiveMaximum": 5}, []interface{}{1.0, 4.0}},
+		{"exclusive number", map[string]interface{}{"type": "number", "exclusiveMinimum": 0}, nil},
+		{"exclusive maximum", map[string]interface{}{"type": "number", "exclusiveMaximum": 0}, nil},
+	} {
+		assert.Equal(suite.T(), tC.expected, suite.gen.boundaries(tC.schema), tC.desc)
+	}
+}
+
+func (suite *SpecFuzzSuite) TestNumericValues() {
+	for _, tC := range []struct {
+		desc     string
+		schema   map[string]interface{}
+		expected interface{}
+	}{
+		{"unbounded integer", map[string]interface{}{"type": "integer"}, 1},
+		{"unbounded number", map[string]interface{}{"type": "number"}, 1.5},
+		{"integer minimum", map[string]interface{}{"type": "integer", "minimum": 5}, 5},
+		{"integer exclusive 3.0", map[string]interface{}{"type": "integer", "minimum": 5, "exclusiveMinimum": true}, 6},
+		{"integer exclusive maximum", map[string]interface{}{"type": "integer", "exclusiveMaximum": 0}, -1},
+		{"number narrow range", map[string]interface{}{"type": "number", "minimum": 0, "maximum": 0.1}, 0.05},
+		{"number exclusive minimum", map[string]interface{}{"type": "number", "exclusiveMinimum": 10}, 10.5},
+		{"number exclusive maximum", map[string]interface{}{"type": "number", "exclusiveMaximum": -3}, -3.5},
+	} {
+		assert.Equal(suite.T(), tC.expected, suite.gen.valid(tC.schema, 0), tC.desc)
+	}
+}
+
+func (suite *SpecFuzzSuite) TestNumericInvalids() {
+	for _, tC := range []struct {
+		desc     string
+		schema   map[string]interface{}
+		expected []invalid
+	}{
+		{"inclusive", map[string]interface{}{"type": "number", "minimum": 0, "maximum": 0.1}, []invalid{
+			{"not-a-number", "wrong type"}, {-1.0, "below minimum"}, {1.1, "above maximum"}}},
+		{"exclusive 3.0", map[string]interface{}{"type": "number", "minimum": 0, "exclusiveMinimum": true}, []invalid{
+			{"not-a-number", "wrong type"}, {0.0, "not above exclusiveMinimum"}}},
+		{"exclusive 3.1", map[string]interface{}{"type": "number", "exclusiveMinimum": 10, "exclusiveMaximum": 20}, []invalid{
+			{"not-a-number", "wrong type"}, {10.0, "not above exclusiveMinimum"}, {20.0, "not below exclusiveMaximum"}}},
+	} {
+		assert.Equal(suite.T(), tC.expected, suite.gen.invalids(tC.schema), tC.desc)
+	}
+}
+
+func (suite *SpecFuzzSuite) TestSwagger2Body() {
+	g, err := New([]byte(`
+swagger: "2.0"
+paths:
+  /pets:
+    post:
+      parameters:
+        - name: pet
+          in: body
+          required: true
+          schema:
+            type: object
+            required: [name]
+            properties:
+              name:
+                type: string
+`))
+	require.NoError(suite.T(), err)
+	cases := g.Cases()
+	require.NotEmpty(suite.T(), cases)
+	assert.Equal(suite.T(), "POST /pets", cases[0].OperationID)
+	assert.JSONEq(suite.T(), `{"name":"test"}`, string(cases[0].Body))
+}
+
+func TestSpecFuzz(t *testing.T) {
+	suite.Run(t, new(SpecFuzzSuite))
+}
+
+func FuzzPetstore(f *testing.F) {
+	g, err := New([]byte(petstore))
+	if err != nil {
+		f.Fatal(err)
+	}
+	Seed(f, g.Cases())
+	Fuzz(f, http.HandlerFunc(strictHandler))
+}
@@ -0,0 +1,97 @@
+/*
+ *  split.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/mwmahlberg/swagger-ui/openapi"
+)
+
+// partDir is the directory the parts of a split spec are served from.
+const partDir = "parts/"
+
+// SplitByTag serves the spec as one document per tag, listed in the spec
+// selector in the top bar of swagger-ui instead of the whole spec, so that
+// each section of a large spec loads quickly. Each document carries only the
+// components it references. The whole spec is still served under its name.
+// See openapi.SplitByTag for how operations are assigned.
+func SplitByTag() HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.split = openapi.SplitByTag
+	}
+}
+
+// SplitByPathPrefix is like SplitByTag, but splits the spec by the given path
+// prefixes, as in "/pets" or "/orders". See openapi.SplitByPathPrefix.
+func SplitByPathPrefix(prefixes ...string) HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.split = func(d *openapi.Document) ([]openapi.Part, error) {
+			return openapi.SplitByPathPrefix(d, prefixes...)
+		}
+	}
+}
+
+func (ui *SwaggerUi) setupSplit() error {
+	doc, err := openapi.Parse(ui.specContent)
+	if err != nil {
+		return err
+	}
+	parts, err := ui.split(doc)
+	if err != nil {
+		return err
+	}
+	used := make(map[string]bool)
+	for _, p := range parts {
+		name := partFilename(p.Name, path.Ext(ui.specFilename), used)
+		data, err := encodeDocument(p.Document, name)
+		if err != nil {
+			return err
+		}
+		ui.serveFile(name, data)
+		ref := url.URL{Path: name}
+		ui.parts = append(ui.parts, specURL{Name: p.Name, URL: ref.String()})
+	}
+	return nil
+}
+
+// partFilename returns a unique file name for the part called name.
+func partFilename(name, ext string, used map[string]bool) string {
+	slug := strings.Trim(strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return '-'
+	}, name), "-")
+	if slug == "" {
+		slug = "part"
+	}
+	unique := slug
+	for i := 2; used[unique]; i++ {
+		unique = slug + "-" + strconv.Itoa(i)
+	}
+	used[unique] = true
+	return partDir + unique + ext
+}
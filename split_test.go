@@ -0,0 +1,106 @@
+/*
+ *  split_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const taggedSpec = `openapi: 3.0.3
+info:
+  title: Shop
+  version: "1.0"
+paths:
+  /pets:
+    get:
+      tags: [Pet Store]
+      responses:
+        "200":
+          description: The pets
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+  /orders:
+    get:
+      tags: [orders]
+      responses:
+        "200":
+          description: The orders
+components:
+  schemas:
+    Pet:
+      type: object
+`
+
+type SplitSuite struct {
+	suite.Suite
+}
+
+func (suite *SplitSuite) TestSplitByTag() {
+	ui, err := New(Spec("shop.json", []byte(taggedSpec)), SplitByTag())
+	require.NoError(suite.T(), err)
+
+	b, err := fs.ReadFile(ui.Merged, "parts/pet-store.json")
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(b), `"Pet": {`)
+	assert.NotContains(suite.T(), string(b), "/orders")
+
+	b, err = fs.ReadFile(ui.Merged, "parts/orders.json")
+	require.NoError(suite.T(), err)
+	assert.NotContains(suite.T(), string(b), "components")
+
+	b, err = fs.ReadFile(ui.Merged, InitializerFilename)
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(b), `"./parts/pet-store.json"`)
+	assert.Contains(suite.T(), string(b), `"./parts/orders.json"`)
+	assert.NotContains(suite.T(), string(b), `"./shop.json"`)
+
+	_, err = fs.ReadFile(ui.Merged, "shop.json")
+	assert.NoError(suite.T(), err, "the whole spec is still served")
+}
+
+func (suite *SplitSuite) TestSplitByPathPrefix() {
+	ui, err := New(Spec("shop.yaml", []byte(taggedSpec)), SplitByPathPrefix("/pets"))
+	require.NoError(suite.T(), err)
+
+	b, err := fs.ReadFile(ui.Merged, "parts/pets.yaml")
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(b), "/pets:")
+
+	b, err = fs.ReadFile(ui.Merged, "parts/default.yaml")
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(b), "/orders:")
+}
+
+func (suite *SplitSuite) TestPartFilename() {
+	used := make(map[string]bool)
+	assert.Equal(suite.T(), "parts/pets.yaml", partFilename("/pets/", ".yaml", used))
+	assert.Equal(suite.T(), "parts/pets-2.yaml", partFilename("Pets", ".yaml", used))
+	assert.Equal(suite.T(), "parts/part.yaml", partFilename("/", ".yaml", used))
+}
+
+func TestSplit(t *testing.T) {
+	suite.Run(t, new(SplitSuite))
+}
@@ -0,0 +1,201 @@
+/*
+ *  store.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrSpecNotFound is returned by a SpecStore if it holds no spec of the requested revision.
+var ErrSpecNotFound = errors.New("spec not found")
+
+// StoredSpec is a revision of a spec kept in a SpecStore.
+type StoredSpec struct {
+	// Revision numbers the specs of a store in the order they were put, starting at 1.
+	Revision int
+	Spec     []byte
+	Time     time.Time
+}
+
+// SpecStore keeps the revisions of a spec, so that it survives restarts and
+// can be shared by several handlers.
+type SpecStore interface {
+	// Get returns the spec of the given revision, or the latest one if revision is 0.
+	Get(ctx context.Context, revision int) (StoredSpec, error)
+	// Put adds spec as the latest revision.
+	Put(ctx context.Context, spec []byte) (StoredSpec, error)
+	// Revisions lists the revisions of the store in ascending order.
+	Revisions(ctx context.Context) ([]int, error)
+	// Watch returns a channel receiving the latest spec whenever one is put
+	// into the store, until ctx is done. A slow receiver may miss intermediate
+	// revisions, but always receives the latest one.
+	Watch(ctx context.Context) (<-chan StoredSpec, error)
+}
+
+// MemoryStore is a SpecStore holding its specs in memory, for example in tests.
+type MemoryStore struct {
+	mu       sync.Mutex
+	specs    []StoredSpec
+	watchers map[chan StoredSpec]bool
+}
+
+// NewMemoryStore returns a MemoryStore holding the given specs as its first revisions.
+func NewMemoryStore(specs ...[]byte) *MemoryStore {
+	s := &MemoryStore{watchers: make(map[chan StoredSpec]bool)}
+	for _, spec := range specs {
+		s.Put(context.Background(), spec)
+	}
+	return s
+}
+
+// Get implements SpecStore.
+func (s *MemoryStore) Get(_ context.Context, revision int) (StoredSpec, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if revision == 0 {
+		revision = len(s.specs)
+	}
+	if revision < 1 || revision > len(s.specs) {
+		return StoredSpec{}, ErrSpecNotFound
+	}
+	return copySpec(s.specs[revision-1]), nil
+}
+
+// Put implements SpecStore.
+func (s *MemoryStore) Put(_ context.Context, spec []byte) (StoredSpec, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := copySpec(StoredSpec{Revision: len(s.specs) + 1, Spec: spec, Time: time.Now()})
+	s.specs = append(s.specs, stored)
+	for w := range s.watchers {
+		notify(w, copySpec(stored))
+	}
+	return copySpec(stored), nil
+}
+
+// Revisions implements SpecStore.
+func (s *MemoryStore) Revisions(context.Context) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	revisions := make([]int, len(s.specs))
+	for i := range s.specs {
+		revisions[i] = i + 1
+	}
+	return revisions, nil
+}
+
+// Watch implements SpecStore.
+func (s *MemoryStore) Watch(ctx context.Context) (<-chan StoredSpec, error) {
+	w := make(chan StoredSpec, 1)
+	s.mu.Lock()
+	s.watchers[w] = true
+	s.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.watchers, w)
+		close(w)
+	}()
+	return w, nil
+}
+
+// notify replaces a spec not yet received from w with spec. Only the sender
+// may fill w, which has a buffer of one.
+func notify(w chan StoredSpec, spec StoredSpec) {
+	select {
+	case <-w:
+	default:
+	}
+	w <- spec
+}
+
+func copySpec(s StoredSpec) StoredSpec {
+	s.Spec = append([]byte(nil), s.Spec...)
+	return s
+}
+
+// Store makes s the source of the spec. The latest spec of s replaces the one
+// given by the other options, which is put into s if it is empty. Specs
+// published through the admin API are put into s, too, and WatchStore
+// follows the specs put into s by other handlers.
+func Store(s SpecStore) HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.store = s
+	}
+}
+
+// loadStore replaces the spec with the latest one of the store, or seeds an empty store with it.
+func (ui *SwaggerUi) loadStore() error {
+	ctx := context.Background()
+	latest, err := ui.store.Get(ctx, 0)
+	switch {
+	case errors.Is(err, ErrSpecNotFound) && len(ui.specContent) > 0:
+		latest, err = ui.store.Put(ctx, ui.specContent)
+		if err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	default:
+		ui.specContent = latest.Spec
+		ui.document = nil
+	}
+	ui.revision = latest.Revision
+	return nil
+}
+
+// WatchStore serves the specs put into the store set with Store until ctx is
+// done. Specs failing the checks of Publish are skipped, so that the last
+// good spec is kept.
+func (ui *SwaggerUi) WatchStore(ctx context.Context) error {
+	if ui.store == nil {
+		return errors.New("no store")
+	}
+	specs, err := ui.store.Watch(ctx)
+	if err != nil {
+		return err
+	}
+	for s := range specs {
+		ui.follow(s)
+	}
+	return nil
+}
+
+// follow serves s, unless it is not newer than the spec currently served.
+func (ui *SwaggerUi) follow(s StoredSpec) {
+	p := ui.publisher
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if s.Revision <= ui.state().revision {
+		return
+	}
+	next, err := ui.prepareSuccessor(s.Spec)
+	if err != nil {
+		return
+	}
+	next.revision = s.Revision
+	p.previous = p.active.Swap(next)
+	next.addToHistory()
+	ui.refresh()
+}
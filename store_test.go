@@ -0,0 +1,136 @@
+/*
+ *  store_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type StoreSuite struct {
+	suite.Suite
+}
+
+func (suite *StoreSuite) TestMemoryStore() {
+	s := NewMemoryStore(versionedSpec("1.0", ""))
+	ctx := context.Background()
+
+	stored, err := s.Put(ctx, versionedSpec("1.1", ""))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, stored.Revision)
+
+	revisions, err := s.Revisions(ctx)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []int{1, 2}, revisions)
+
+	latest, err := s.Get(ctx, 0)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, latest.Revision)
+	first, err := s.Get(ctx, 1)
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(first.Spec), `version: "1.0"`)
+	_, err = s.Get(ctx, 3)
+	assert.ErrorIs(suite.T(), err, ErrSpecNotFound)
+	_, err = NewMemoryStore().Get(ctx, 0)
+	assert.ErrorIs(suite.T(), err, ErrSpecNotFound)
+}
+
+func (suite *StoreSuite) TestMemoryStoreWatch() {
+	s := NewMemoryStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	specs, err := s.Watch(ctx)
+	require.NoError(suite.T(), err)
+
+	s.Put(ctx, versionedSpec("1.0", ""))
+	s.Put(ctx, versionedSpec("1.1", ""))
+	latest := <-specs
+	assert.Equal(suite.T(), 2, latest.Revision, "only the latest spec is received")
+
+	cancel()
+	_, open := <-specs
+	assert.False(suite.T(), open)
+}
+
+func (suite *StoreSuite) TestSeed() {
+	s := NewMemoryStore()
+	ui, err := New(Spec("pets.yaml", versionedSpec("1.0", "")), Store(s))
+	require.NoError(suite.T(), err)
+	stored, err := s.Get(context.Background(), 0)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), ui.specContent, stored.Spec, "an empty store is seeded with the spec")
+
+	_, err = New(Store(NewMemoryStore()))
+	assert.Error(suite.T(), err)
+}
+
+func (suite *StoreSuite) TestLoad() {
+	s := NewMemoryStore(versionedSpec("2.0", "/toys"))
+	ui, err := New(Spec("pets.yaml", versionedSpec("1.0", "")), Store(s))
+	require.NoError(suite.T(), err)
+	_, body := get(ui, "/pets.yaml")
+	assert.Contains(suite.T(), body, "/toys", "the stored spec takes precedence")
+}
+
+func (suite *StoreSuite) TestShared() {
+	s := NewMemoryStore(versionedSpec("1.0", ""))
+	publisher, err := New(Spec("pets.yaml", nil), Store(s))
+	require.NoError(suite.T(), err)
+	follower, err := New(Spec("pets.yaml", nil), Store(s))
+	require.NoError(suite.T(), err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- follower.WatchStore(ctx) }()
+	// Wait for the follower to watch the store.
+	require.Eventually(suite.T(), func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.watchers) == 1
+	}, time.Second, time.Millisecond)
+
+	_, err = publisher.Publish("alice", versionedSpec("2.0", "/toys"))
+	require.NoError(suite.T(), err)
+	assert.Eventually(suite.T(), func() bool {
+		_, body := get(follower, "/pets.yaml")
+		return strings.Contains(body, "/toys")
+	}, time.Second, time.Millisecond)
+
+	s.Put(ctx, []byte("not a spec"))
+	_, err = publisher.Rollback("alice")
+	require.NoError(suite.T(), err)
+	revisions, _ := s.Revisions(ctx)
+	assert.Equal(suite.T(), []int{1, 2, 3, 4}, revisions, "rollbacks are stored as new revisions")
+	assert.Eventually(suite.T(), func() bool {
+		_, body := get(follower, "/pets.yaml")
+		return !strings.Contains(body, "/toys")
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	assert.NoError(suite.T(), <-done)
+}
+
+func TestStore(t *testing.T) {
+	suite.Run(t, new(StoreSuite))
+}
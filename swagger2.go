@@ -0,0 +1,45 @@
+/*
+ *  swagger2.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"github.com/mwmahlberg/swagger-ui/openapi"
+)
+
+// ConvertSwagger2 converts a Swagger 2.0 spec passed to Spec into OpenAPI 3.0.3
+// before it is served. Specs in other versions are served unchanged.
+// Constructs that could not be translated are reported by Warnings.
+func ConvertSwagger2() HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.convertSwagger2 = true
+	}
+}
+
+func (ui *SwaggerUi) convertSpec() error {
+	if !openapi.IsSwagger2(ui.specContent) {
+		return nil
+	}
+	doc, warnings, err := openapi.ConvertSwagger2(ui.specContent)
+	if err != nil {
+		return err
+	}
+	ui.warnings = append(ui.warnings, warnings...)
+	ui.specContent, err = encodeDocument(doc, ui.specFilename)
+	return err
+}
@@ -0,0 +1,82 @@
+/*
+ *  swagger2_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type Swagger2Suite struct {
+	suite.Suite
+	spec []byte
+}
+
+func (suite *Swagger2Suite) SetupSuite() {
+	var err error
+	suite.spec, err = os.ReadFile("openapi/testdata/swagger2.yaml")
+	require.NoError(suite.T(), err)
+}
+
+func (suite *Swagger2Suite) TestConvert() {
+	ui, err := New(Spec("swagger.yaml", suite.spec), ConvertSwagger2())
+	require.NoError(suite.T(), err)
+
+	doc, err := ui.Document()
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "3.0.3", doc.OpenAPI)
+	assert.Len(suite.T(), ui.Warnings(), 1)
+}
+
+func (suite *Swagger2Suite) TestConvertToJSON() {
+	ui, err := New(Spec("swagger.json", suite.spec), ConvertSwagger2())
+	require.NoError(suite.T(), err)
+
+	b, err := fs.ReadFile(ui.Merged, "swagger.json")
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(b), `"openapi": "3.0.3"`)
+}
+
+func (suite *Swagger2Suite) TestOpenAPI3Unchanged() {
+	ui, err := New(Spec("pets.yaml", []byte(refSpec)), ConvertSwagger2())
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), ui.Warnings())
+
+	b, err := fs.ReadFile(ui.Merged, "pets.yaml")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), refSpec, string(b))
+}
+
+func (suite *Swagger2Suite) TestNotConvertedByDefault() {
+	ui, err := New(Spec("swagger.yaml", suite.spec))
+	require.NoError(suite.T(), err)
+
+	b, err := fs.ReadFile(ui.Merged, "swagger.yaml")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), suite.spec, b)
+}
+
+func TestSwagger2(t *testing.T) {
+	suite.Run(t, new(Swagger2Suite))
+}
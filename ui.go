@@ -42,11 +42,16 @@ import (
 	"errors"
 	"fmt"
 	"html/template"
+	"io"
 	"io/fs"
 	"net/http"
+	"path"
+	"strings"
 
 	"github.com/asaskevich/govalidator"
 	"github.com/mwmahlberg/memfs"
+	"github.com/mwmahlberg/swagger-ui/lint"
+	"github.com/mwmahlberg/swagger-ui/openapi"
 	"github.com/yalue/merged_fs"
 )
 
@@ -56,8 +61,10 @@ const (
 	// InitializerFilename is the default name of the initializer file.
 	InitializerFilename string = "swagger-initializer.js"
 
-	// This is the template for the initializer.js file, which is used to initialize the swagger-ui.
-	// Alternatively, you can provide your own initializer by using the InitializerContent option.
+	// This is the basic template for the initializer.js file, which is used to initialize the swagger-ui.
+	// It is executed with a struct holding the fields Prefix and Filename. The handler renders an extended
+	// version supporting its options. Alternatively, you can provide your own initializer by using the
+	// InitializerContent option.
 	InitializerTemplate string = `
 window.onload = function () {
   //<editor-fold desc="Changeable Configuration Block">
@@ -79,6 +86,172 @@ window.onload = function () {
 
   //</editor-fold>
 };
+`
+
+	// initializerTemplate extends InitializerTemplate with the spec selector, settings, plugins and hooks
+	// configured by the options of the handler. It is executed with initializerData. Its code must not
+	// compare with "<", which html/template escapes, and values are rendered through initializerData.JS.
+	initializerTemplate string = `
+window.onload = function () {
+  {{- if .Plugins}}
+  loadPlugins([
+    {{- range $i, $p := .Plugins}}{{if $i}},{{end}}
+    [{{$.JS $p.Name}}, {{$.JS ($.URL $p.URL)}}]
+    {{- end}}
+  ]).then(initialize);
+};
+
+function initialize(plugins) {
+  {{- end}}
+  //<editor-fold desc="Changeable Configuration Block">
+
+  // the following lines will be replaced by docker/configurator, when it runs in a docker-container
+  window.ui = SwaggerUIBundle({
+    {{- if .Has "url" "urls" "spec"}}
+    {{- else if .URLs}}
+    urls: [
+      {{- range $i, $u := .URLs}}{{if $i}},{{end}}
+      {url: {{$.JS ($.URL $u.URL)}}, name: {{$.JS $u.Name}}}
+      {{- end}}
+    ],
+    {{- if .Primary}}
+    "urls.primaryName": {{.JS .Primary}},
+    {{- end}}
+    {{- else}}
+    url: {{.JS (.URL .Filename)}},
+    {{- end}}
+    {{- range .Settings}}
+    {{.Key}}: {{.Value}},
+    {{- end}}
+    {{- if .RequestHeaders}}
+    requestInterceptor: function (request) {
+      {{- range .RequestHeaders}}
+      addHeader(request, {{.Name}}, {{.Value}}, {{.Origins}});
+      {{- end}}
+      return request;
+    },
+    {{- end}}
+    {{- if .Preauthorize}}
+    onComplete: function () {
+      {{- range .Preauthorize}}
+      window.ui.{{.}};
+      {{- end}}
+    },
+    {{- end}}
+    {{- if .ResponseHook}}
+    responseInterceptor: function (response) {
+      ({{.ResponseHook}})(response);
+      return response;
+    },
+    {{- end}}
+    {{- if not (.Has "dom_id")}}
+    dom_id: '#swagger-ui',
+    {{- end}}
+    {{- if not (.Has "deepLinking")}}
+    deepLinking: true,
+    {{- end}}
+    presets: [
+      SwaggerUIBundle.presets.apis,
+      SwaggerUIStandalonePreset
+    ],
+    plugins: [
+      SwaggerUIBundle.plugins.DownloadUrl
+      {{- if .LiveReload}},
+      LiveReloadPlugin
+      {{- end}}
+    ]{{if .Plugins}}.concat(plugins){{end}}
+    {{- if not (.Has "layout")}},
+    layout: "StandaloneLayout"
+    {{- end}}
+  });
+  {{- if .OAuth}}
+
+  window.ui.initOAuth({
+    {{- range $i, $s := .OAuth}}{{if $i}},{{end}}
+    {{$s.Key}}: {{$s.Value}}
+    {{- end}}
+  });
+  {{- end}}
+
+  //</editor-fold>
+};
+{{- if .Plugins}}
+
+// loadPlugins loads the scripts of the plugins, given as pairs of name and
+// source, one after another, so that they are evaluated in order. It resolves
+// to the plugins that were loaded. A plugin failing to load is logged and
+// left out, so that Swagger UI is still initialized.
+function loadPlugins(plugins) {
+  return plugins.reduce(function (loaded, plugin) {
+    return loaded.then(function (found) {
+      return new Promise(function (resolve) {
+        const script = document.createElement("script");
+        script.src = plugin[1];
+        script.onload = function () {
+          if (typeof window[plugin[0]] !== "function") {
+            console.error("plugin " + plugin[0] + " is not defined by " + plugin[1]);
+            resolve(found);
+            return;
+          }
+          resolve(found.concat(window[plugin[0]]));
+        };
+        script.onerror = function () {
+          console.error("loading plugin " + plugin[0] + " from " + plugin[1] + " failed");
+          resolve(found);
+        };
+        document.head.appendChild(script);
+      });
+    });
+  }, Promise.resolve([]));
+}
+{{- end}}
+{{- if .RequestHeaders}}
+
+// addHeader sets a header of request, if value is set and the request goes
+// to the origin of the page or one of origins.
+function addHeader(request, name, value, origins) {
+  const origin = new URL(request.url, window.location.href).origin;
+  if (value && (origin === window.location.origin || origins.indexOf(origin) >= 0 || origins.indexOf("*") >= 0)) {
+    request.headers[name] = value;
+  }
+}
+
+// readCookie returns the value of a cookie, or an empty string if it is not set.
+function readCookie(name) {
+  const cookies = document.cookie ? document.cookie.split("; ") : [];
+  for (const cookie of cookies) {
+    const eq = cookie.indexOf("=");
+    if (decodeURIComponent(cookie.slice(0, eq)) === name) {
+      return decodeURIComponent(cookie.slice(eq + 1));
+    }
+  }
+  return "";
+}
+
+// readMeta returns the content of a meta tag rendered by the server, or an
+// empty string if there is none.
+function readMeta(name) {
+  const meta = document.getElementsByName(name)[0];
+  return meta ? meta.content : "";
+}
+{{- end}}
+{{- if .LiveReload}}
+
+// LiveReloadPlugin reloads the spec whenever it changes on the server and
+// restores the operation expanded through the deep link.
+function LiveReloadPlugin(system) {
+  const events = new EventSource({{.JS (.URL .ReloadPath)}});
+  events.addEventListener("reload", function () {
+    const hash = window.location.hash;
+    Promise.resolve(system.specActions.download(system.specSelectors.url())).then(function () {
+      if (hash) {
+        system.layoutActions.parseDeepLinkHash(hash);
+      }
+    });
+  });
+  return {};
+}
+{{- end}}
 `
 	embedPrefix string = "swagger-ui/dist"
 )
@@ -103,42 +276,251 @@ type SwaggerUi struct {
 
 	initializerContent []byte `valid:"length(249|16384)~Initializer too small"` // The min length is the length of a minified version of a swagger-initializer
 
+	document *openapi.Document `valid:"-"` // The document rendered into specContent during setup
+
+	specFS   fs.FS  `valid:"-"` // The file system holding a multi-file spec
+	specRoot string `valid:"-"` // The root document of the multi-file spec
+	specTree bool   `valid:"-"` // Whether all files of specFS are served instead of a bundle
+
+	deref             bool   `valid:"-"` // Whether a dereferenced variant of the spec is served
+	derefFilename     string `valid:"-"` // The name of the dereferenced spec
+	downgrade         bool   `valid:"-"` // Whether an OpenAPI 3.0 variant of the spec is served
+	downgradeFilename string `valid:"-"` // The name of the OpenAPI 3.0 variant
+
+	files map[string][]byte `valid:"-"` // Additional files served from the overlay, such as variants of the spec
+
+	mergeInfo    *openapi.Info         `valid:"-"` // The info of the merged spec
+	mergeSources []openapi.MergeSource `valid:"-"` // The documents merged into the spec
+
+	overlays   [][]byte     `valid:"-"` // The overlay documents applied to the spec
+	lintConfig *lint.Config `valid:"-"` // The configuration the spec is linted with, if any
+
+	split func(*openapi.Document) ([]openapi.Part, error) `valid:"-"` // Partitions the spec for the spec selector
+	parts []specURL                                       `valid:"-"` // The selector entries of the parts of the spec
+
+	convertSwagger2 bool     `valid:"-"` // Whether Swagger 2.0 specs are converted to OpenAPI 3
+	warnings        []string `valid:"-"` // Problems found while preparing the spec
+
+	aggregator     *Aggregator   `valid:"-"` // The source of upstream specs
+	mergeUpstreams bool          `valid:"-"` // Whether upstream specs are merged into the spec
+	upstreamInfo   *openapi.Info `valid:"-"` // The info of the merged upstream specs
+
+	history *History `valid:"-"` // The versions of the spec served with a changelog
+
+	generatedInitializer bool       `valid:"-"` // Whether the initializer was rendered from initializerTemplate
+	live                 *liveFiles `valid:"-"` // Files replaced while the handler is running
+	publisher            *publisher `valid:"-"` // The state serving requests, replaced when a spec is published
+
+	adminAuth Authenticator `valid:"-"` // Authenticates callers of the admin API, which is disabled if nil
+	auditLog  io.Writer     `valid:"-"` // Receives a record of every publication
+
+	store    SpecStore `valid:"-"` // The source of the spec, if any
+	revision int       `valid:"-"` // The revision of the spec in the store
+
+	devMode    bool     `valid:"-"` // Whether features meant for development only are enabled
+	production bool     `valid:"-"` // Whether features meant for development only are rejected
+	reloads    *reloads `valid:"-"` // Notifies open browser tabs of changes of the spec
+
+	basePath      string    `valid:"-"` // The path the handler is mounted at, prefixing the URLs of the specs
+	settings      []setting `valid:"-"` // Added to the configuration of SwaggerUIBundle
+	oauthSettings []setting `valid:"-"` // Passed to initOAuth
+	optionErrs    []error   `valid:"-"` // Errors of options, which make New fail
+
+	uiConfig *uiConfig `valid:"-"` // The source of the swagger-config document, if any
+	plugins  []plugin  `valid:"-"` // The plugins registered in addition to the built-in ones
+
+	requestHeaders []RequestHeader `valid:"-"` // Added to the requests of swagger-ui
+	responseHook   string          `valid:"-"` // JavaScript called with the responses of swagger-ui
+
+	preauthorizations []preauthorization `valid:"-"` // Credentials entered into the Authorize dialog in development mode
 }
 
 // ServeHTTP implements the http.Handler interface.
 // It serves the swagger-ui, the spec file and the initializer by using the merged fs via http.FileServer.
+// Files that change at runtime, such as specs fetched from upstream services, take precedence.
 func (ui *SwaggerUi) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch p := strings.TrimPrefix(r.URL.Path, "/"); {
+	case ui.adminAuth != nil && p == AdminPath:
+		ui.serveAdmin(w, r)
+		return
+	case ui.devMode && p == ReloadPath:
+		ui.serveReloads(w, r)
+		return
+	case ui.uiConfig != nil && p == ConfigFilename:
+		ui.serveConfig(w, r)
+		return
+	case (p == "" || p == "index.html") && ui.hasMetaHeaders():
+		ui.state().serveIndex(w, r)
+		return
+	}
+	ui.state().serveFiles(w, r)
+}
+
+func (ui *SwaggerUi) serveFiles(w http.ResponseWriter, r *http.Request) {
+	if f, ok := ui.live.get(strings.TrimPrefix(r.URL.Path, "/")); ok {
+		http.ServeContent(w, r, f.name, f.modTime, bytes.NewReader(f.data))
+		return
+	}
 	ui.fileServer.ServeHTTP(w, r)
 }
 
 // New returns a new SwaggerUi handler.
 func New(opts ...HandlerOption) (*SwaggerUi, error) {
 	var ui = &SwaggerUi{
-		specFilename: DefaultSpecfileName}
+		specFilename: DefaultSpecfileName,
+		live:         newLiveFiles(),
+		reloads:      newReloads(),
+		publisher:    &publisher{}}
 
 	for _, opt := range opts {
 		opt(ui)
 	}
 
-	if len(ui.initializerContent) == 0 {
-		ui.initializerContent = getInitializer(ui.specFilename, "")
+	if err := errors.Join(ui.optionErrs...); err != nil {
+		return nil, SetupError{Cause: errors.New("invalid options: " + err.Error())}
+	}
+
+	if err := ui.checkModes(); err != nil {
+		return nil, SetupError{Cause: errors.New("invalid options: " + err.Error())}
+	}
+
+	if ui.uiConfig != nil {
+		if _, _, err := ui.uiConfig.load(); err != nil {
+			return nil, SetupError{Cause: errors.New("error loading swagger-config: " + err.Error())}
+		}
+	}
+
+	if ui.specFS != nil {
+		if err := ui.loadSpecFS(); err != nil {
+			return nil, SetupError{Cause: errors.New("error bundling spec: " + err.Error())}
+		}
+	}
+
+	if ui.aggregator != nil {
+		ui.setupUpstreams()
+	}
+
+	if ui.mergeInfo != nil {
+		if err := ui.mergeSpecs(); err != nil {
+			return nil, SetupError{Cause: errors.New("error merging specs: " + err.Error())}
+		}
+	}
+
+	if ui.document != nil {
+		if err := ui.renderDocument(); err != nil {
+			return nil, SetupError{Cause: errors.New("error rendering document: " + err.Error())}
+		}
+	}
+
+	if ui.store != nil {
+		if err := ui.loadStore(); err != nil {
+			return nil, SetupError{Cause: errors.New("error loading spec from store: " + err.Error())}
+		}
+	}
+
+	if err := ui.prepareSpec(); err != nil {
+		return nil, err
+	}
+
+	if err := ui.setupFiles(); err != nil {
+		return nil, err
+	}
+
+	ui.publisher.active.Store(ui)
+
+	if err := ui.addToHistory(); err != nil {
+		return nil, SetupError{Cause: errors.New("error adding spec to history: " + err.Error())}
+	}
+
+	if ui.mergeUpstreams {
+		ui.aggregator.subscribe(ui.followUpstreams)
+	} else if ui.aggregator != nil {
+		ui.syncUpstreams()
+		ui.aggregator.subscribe(ui.refresh)
+	}
+	if ui.history != nil {
+		ui.syncHistory()
+		ui.history.subscribe(ui.syncHistory)
+	}
+	return ui, nil
+}
+
+// prepareSpec checks the spec and derives the files served along with it.
+// It is also used for specs published through the admin API.
+func (ui *SwaggerUi) prepareSpec() error {
+	if ui.convertSwagger2 {
+		if err := ui.convertSpec(); err != nil {
+			return SetupError{Cause: errors.New("error converting spec: " + err.Error())}
+		}
+	}
+
+	if len(ui.overlays) > 0 {
+		if err := ui.applyOverlays(); err != nil {
+			return SetupError{Cause: errors.New("error applying overlays: " + err.Error())}
+		}
+	}
+
+	if ui.lintConfig != nil {
+		if err := ui.lintSpec(); err != nil {
+			return SetupError{Cause: errors.New("spec has lint errors: " + err.Error())}
+		}
+	}
+
+	if ui.split != nil {
+		if err := ui.setupSplit(); err != nil {
+			return SetupError{Cause: errors.New("error splitting spec: " + err.Error())}
+		}
+	}
+
+	ui.setupPlugins()
+
+	if len(ui.preauthorizations) > 0 {
+		ui.checkPreauthorizations()
+	}
+
+	if len(ui.initializerContent) == 0 || ui.generatedInitializer {
+		ui.generatedInitializer = true
+		ui.initializerContent = ui.renderInitializer()
 	}
 
 	if isValid, err := govalidator.ValidateStruct(ui); !isValid {
 		fmt.Println(err)
-		return nil, SetupError{Cause: errors.New("invalid options: " + err.Error())}
+		return SetupError{Cause: errors.New("invalid options: " + err.Error())}
 	}
 
+	if ui.deref {
+		if err := ui.setupDeref(); err != nil {
+			return SetupError{Cause: errors.New("error dereferencing spec: " + err.Error())}
+		}
+	}
+
+	if ui.downgrade {
+		if err := ui.setupDowngrade(); err != nil {
+			return SetupError{Cause: errors.New("error downgrading spec: " + err.Error())}
+		}
+	}
+
+	if ui.history != nil {
+		if err := ui.checkHistory(); err != nil {
+			return SetupError{Cause: errors.New("error adding spec to history: " + err.Error())}
+		}
+	}
+
+	return nil
+}
+
+// setupFiles sets up the file systems serving the spec, the derived files and swagger-ui.
+func (ui *SwaggerUi) setupFiles() error {
 	if err := ui.setupOverlay(); err != nil {
-		return nil, SetupError{Cause: errors.New("error setting up overlay: " + err.Error())}
+		return SetupError{Cause: errors.New("error setting up overlay: " + err.Error())}
 	}
 
 	if err := ui.setupStatic(); err != nil {
-		return nil, SetupError{Cause: errors.New("error setting up static: " + err.Error())}
+		return SetupError{Cause: errors.New("error setting up static: " + err.Error())}
 	}
 	ui.Merged = merged_fs.NewMergedFS(fs.FS(ui.Overlay), *ui.Static)
 	ui.fileServer = http.FileServer(http.FS(ui.Merged))
-	return ui, nil
+	return nil
 }
 
 // Sets the name under which the data will be served as a spec file.
@@ -161,13 +543,50 @@ func (ui *SwaggerUi) SpecFilename() string {
 	return ui.specFilename
 }
 
+// Warnings returns the problems found while preparing the spec that did not
+// prevent serving it, such as constructs lost in a conversion.
+func (ui *SwaggerUi) Warnings() []string {
+	return ui.state().warnings
+}
+
+// serveFile adds a file to be served from the overlay.
+func (ui *SwaggerUi) serveFile(name string, data []byte) {
+	if ui.files == nil {
+		ui.files = make(map[string][]byte)
+	}
+	ui.files[name] = data
+}
+
+// variantFilename inserts suffix before the extension of the spec file name.
+func (ui *SwaggerUi) variantFilename(suffix string) string {
+	ext := path.Ext(ui.specFilename)
+	return strings.TrimSuffix(ui.specFilename, ext) + suffix + ext
+}
+
 func (ui *SwaggerUi) setupOverlay() error {
 	o := memfs.New()
 
+	if ui.specTree {
+		if err := ui.writeSpecTree(o); err != nil {
+			return errors.New("error writing spec tree: " + err.Error())
+		}
+	}
+
 	if err := o.WriteFile(ui.specFilename, ui.specContent, 0644); err != nil {
 		return errors.New("error writing specfile: " + err.Error())
 	}
 
+	for name, data := range ui.files {
+		if dir := path.Dir(name); dir != "." {
+			if err := o.MkdirAll(dir, 0755); err != nil {
+				return errors.New("error creating " + dir + ": " + err.Error())
+			}
+		}
+		if err := o.WriteFile(name, data, 0644); err != nil {
+			return errors.New("error writing " + name + ": " + err.Error())
+		}
+	}
+
 	if err := o.WriteFile(InitializerFilename, ui.initializerContent, 0644); err != nil {
 		return errors.New("error writing initializer: " + err.Error())
 	}
@@ -185,13 +604,112 @@ func (ui *SwaggerUi) setupStatic() (err error) {
 	return nil
 }
 
+// specURL is an entry of the spec selector in the top bar of swagger-ui.
+type specURL struct {
+	Name string
+	URL  string
+}
+
+// initializerData is passed to initializerTemplate.
+type initializerData struct {
+	Prefix   string
+	Filename string
+	// URLs lists the specs of the selector, which replaces the single spec if set.
+	URLs []specURL
+	// Primary is the name of the spec selected initially.
+	Primary string
+	// LiveReload adds a plugin reloading the spec from the events served under ReloadPath.
+	LiveReload bool
+	ReloadPath string
+	// Settings are added to the configuration of SwaggerUIBundle, replacing
+	// the defaults of the same keys. OAuth is passed to initOAuth.
+	Settings []setting
+	OAuth    []setting
+	// Plugins are loaded before swagger-ui starts and listed after the built-in plugins.
+	Plugins []pluginRef
+	// RequestHeaders are added to requests by a requestInterceptor, while
+	// ResponseHook is called by a responseInterceptor.
+	RequestHeaders []headerRef
+	ResponseHook   template.HTML
+	// Preauthorize lists the calls entering credentials once the spec is loaded.
+	Preauthorize []template.HTML
+}
+
+// JS renders s as JavaScript string literal. Strings are rendered through it,
+// since html/template would escape them for HTML.
+func (d initializerData) JS(s string) template.HTML {
+	return template.HTML(jsString(s))
+}
+
+// URL returns the URL of a file served by the handler, which is relative to
+// the initializer unless Prefix is set.
+func (d initializerData) URL(name string) string {
+	if d.Prefix == "" {
+		return "./" + name
+	}
+	return d.Prefix + "/" + name
+}
+
+// Has reports whether one of the keys is configured by the settings.
+func (d initializerData) Has(keys ...string) bool {
+	return hasSetting(d.Settings, keys...)
+}
+
 func getInitializer(filename string, prefix string) []byte {
-	tmpl, _ := template.New(InitializerFilename).Parse(InitializerTemplate)
+	return executeInitializer(initializerData{Prefix: prefix, Filename: filename})
+}
+
+func executeInitializer(data initializerData) []byte {
+	tmpl, _ := template.New(InitializerFilename).Parse(initializerTemplate)
 	var rendered bytes.Buffer
-	tmpl.Execute(&rendered, struct {
-		Prefix   string
-		Filename string
-	}{Prefix: prefix, Filename: filename})
+	tmpl.Execute(&rendered, data)
 
 	return rendered.Bytes()
 }
+
+// renderInitializer renders the initializer for the current state of the handler.
+func (ui *SwaggerUi) renderInitializer() []byte {
+	return executeInitializer(initializerData{
+		Prefix:     ui.basePath,
+		Filename:   ui.specFilename,
+		URLs:       ui.specURLs(),
+		LiveReload: ui.devMode,
+		ReloadPath: ReloadPath,
+		Settings:   ui.initializerSettings(),
+		OAuth:      ui.oauthSettings,
+		Plugins:    ui.pluginRefs(),
+
+		RequestHeaders: ui.headerRefs(),
+		ResponseHook:   template.HTML(ui.responseHook),
+		Preauthorize:   ui.preauthorizeCalls(),
+	})
+}
+
+// initializerSettings returns the settings of the generated initializer.
+func (ui *SwaggerUi) initializerSettings() []setting {
+	settings := ui.settings
+	if ui.uiConfig != nil && !hasSetting(settings, "configUrl") {
+		configURL := "./" + ConfigFilename
+		if ui.basePath != "" {
+			configURL = ui.basePath + "/" + ConfigFilename
+		}
+		settings = append(settings[:len(settings):len(settings)], newSetting("configUrl", configURL))
+	}
+	return settings
+}
+
+// specURLs returns the entries of the spec selector, or nil if a single spec is served.
+func (ui *SwaggerUi) specURLs() []specURL {
+	var urls []specURL
+	upstreams := ui.aggregator != nil && !ui.mergeUpstreams
+	switch {
+	case len(ui.parts) > 0:
+		urls = append(urls, ui.parts...)
+	case upstreams && len(ui.specContent) > 0:
+		urls = append(urls, specURL{Name: ui.specFilename, URL: ui.specFilename})
+	}
+	if upstreams {
+		urls = append(urls, ui.upstreamURLs()...)
+	}
+	return urls
+}
@@ -0,0 +1,356 @@
+/*
+ *  upstream.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mwmahlberg/swagger-ui/openapi"
+)
+
+const (
+	// DefaultUpstreamInterval is the default interval in which upstream specs are fetched.
+	DefaultUpstreamInterval = time.Minute
+	// upstreamDir is the directory upstream specs are served from.
+	upstreamDir = "upstreams/"
+	// maxSpecSize limits the size of specs fetched from upstream services.
+	maxSpecSize = 16 << 20
+)
+
+// Upstream is a service whose spec is fetched at runtime.
+type Upstream struct {
+	// Name identifies the service in the spec selector and names the served file.
+	Name string
+	// URL is the location of the spec, as in "http://pets/api-docs/swagger.yaml".
+	URL string
+	// PathPrefix and TagPrefix are applied in the merged view, see openapi.MergeSource.
+	PathPrefix string
+	TagPrefix  string
+}
+
+// Aggregator fetches the specs of upstream services. It keeps the last good
+// copy of each spec, so that failing services do not disappear from the docs.
+type Aggregator struct {
+	// Client is used for fetching specs. It defaults to http.DefaultClient.
+	Client *http.Client
+	// Interval is the time between two refreshes in Run. It defaults to DefaultUpstreamInterval.
+	Interval time.Duration
+
+	upstreams []Upstream
+
+	mu        sync.RWMutex
+	specs     map[string]upstreamSpec
+	errs      map[string]error
+	listeners []func()
+}
+
+type upstreamSpec struct {
+	data []byte
+	etag string
+}
+
+// NewAggregator returns an Aggregator for the given upstream services.
+// Use Refresh to fetch the specs once or Run to keep them up to date.
+func NewAggregator(upstreams ...Upstream) *Aggregator {
+	return &Aggregator{
+		upstreams: upstreams,
+		specs:     make(map[string]upstreamSpec),
+		errs:      make(map[string]error),
+	}
+}
+
+// Run refreshes the specs immediately and then in the configured interval until ctx is done.
+func (a *Aggregator) Run(ctx context.Context) {
+	interval := a.Interval
+	if interval <= 0 {
+		interval = DefaultUpstreamInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		a.Refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh fetches all specs once. Specs that did not change since the last
+// fetch, as reported by their ETag, are not transferred again. The returned
+// error joins the errors of all failing services, whose last good copy is kept.
+func (a *Aggregator) Refresh(ctx context.Context) error {
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		errs    []error
+		changed bool
+	)
+	for _, u := range a.upstreams {
+		wg.Add(1)
+		go func(u Upstream) {
+			defer wg.Done()
+			updated, err := a.fetch(ctx, u)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", u.Name, err))
+			}
+			changed = changed || updated
+		}(u)
+	}
+	wg.Wait()
+
+	if changed {
+		a.mu.RLock()
+		listeners := append([]func(){}, a.listeners...)
+		a.mu.RUnlock()
+		for _, l := range listeners {
+			l()
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fetch fetches the spec of u and reports whether it changed.
+func (a *Aggregator) fetch(ctx context.Context, u Upstream) (bool, error) {
+	a.mu.RLock()
+	prev, hasPrev := a.specs[u.Name]
+	a.mu.RUnlock()
+
+	data, etag, err := a.get(ctx, u.URL, prev.etag)
+	switch {
+	case err != nil:
+	case data == nil:
+		// Not modified.
+	case !isSpec(data):
+		err = errors.New("response is not an OpenAPI or Swagger document")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.errs[u.Name] = err
+	if err != nil || data == nil {
+		return false, err
+	}
+	a.specs[u.Name] = upstreamSpec{data: data, etag: etag}
+	return !hasPrev || string(prev.data) != string(data), nil
+}
+
+// get fetches url, returning nil data if it was not modified since etag.
+func (a *Aggregator) get(ctx context.Context, url, etag string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	client := a.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && etag != "":
+		return nil, etag, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSpecSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) > maxSpecSize {
+		return nil, "", errors.New("spec too large")
+	}
+	return data, resp.Header.Get("ETag"), nil
+}
+
+func isSpec(data []byte) bool {
+	if openapi.IsSwagger2(data) {
+		return true
+	}
+	_, err := openapi.Parse(data)
+	return err == nil
+}
+
+// Spec returns the last good copy of the spec of the upstream service name.
+func (a *Aggregator) Spec(name string) ([]byte, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	s, ok := a.specs[name]
+	return s.data, ok
+}
+
+// Err returns the error of the last attempt to fetch the spec of name, if any.
+func (a *Aggregator) Err(name string) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.errs[name]
+}
+
+// Merged merges the specs fetched so far into a single document with the given info.
+func (a *Aggregator) Merged(info *openapi.Info) (*openapi.Document, error) {
+	var sources []openapi.MergeSource
+	for _, u := range a.upstreams {
+		data, ok := a.Spec(u.Name)
+		if !ok {
+			continue
+		}
+		doc, err := openapi.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", u.Name, err)
+		}
+		sources = append(sources, openapi.MergeSource{
+			Name: u.Name, Document: doc, PathPrefix: u.PathPrefix, TagPrefix: u.TagPrefix})
+	}
+	if len(sources) == 0 {
+		return nil, errors.New("no upstream spec available")
+	}
+	return openapi.Merge(info, sources...)
+}
+
+// subscribe registers f to be called whenever a spec changed.
+func (a *Aggregator) subscribe(f func()) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.listeners = append(a.listeners, f)
+}
+
+// Upstreams adds the specs of the aggregator's services to the spec selector
+// in the top bar of swagger-ui. They are served from "upstreams/" and
+// updated whenever the aggregator fetches a new version. The spec passed to
+// Spec, if any, is listed first.
+func Upstreams(a *Aggregator) HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.aggregator = a
+		suh.mergeUpstreams = false
+	}
+}
+
+// MergedUpstreams serves the merged specs of the aggregator's services as the
+// spec, updated whenever the aggregator fetches a new version. The merged spec
+// is processed with the same options as a published one, see Publish. If
+// merging or processing fails, the previous version is kept.
+func MergedUpstreams(a *Aggregator, info *openapi.Info) HandlerOption {
+	return func(suh *SwaggerUi) {
+		suh.aggregator = a
+		suh.mergeUpstreams = true
+		suh.upstreamInfo = info
+	}
+}
+
+// upstreamFilename returns the name under which the spec of an upstream service is served.
+func upstreamFilename(name string, data []byte) string {
+	return upstreamDir + strings.ReplaceAll(name, "/", "_") + specExt(data)
+}
+
+// specExt returns the file extension matching the format of a spec.
+func specExt(data []byte) string {
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		return ".json"
+	}
+	return ".yaml"
+}
+
+// upstreamURLs returns the selector entries of the upstream specs fetched so far.
+func (ui *SwaggerUi) upstreamURLs() []specURL {
+	var urls []specURL
+	for _, u := range ui.aggregator.upstreams {
+		data, ok := ui.aggregator.Spec(u.Name)
+		if !ok {
+			continue
+		}
+		ref := url.URL{Path: upstreamFilename(u.Name, data)}
+		urls = append(urls, specURL{Name: u.Name, URL: ref.String()})
+	}
+	return urls
+}
+
+// setupUpstreams prepares the merged spec, if requested, before validation.
+func (ui *SwaggerUi) setupUpstreams() {
+	if !ui.mergeUpstreams {
+		return
+	}
+	if doc, err := ui.aggregator.Merged(ui.upstreamInfo); err == nil {
+		ui.document = doc
+	}
+}
+
+// followUpstreams serves the merged upstream specs. They are prepared like a
+// published spec, so that the files derived from the spec are updated along
+// with it. If merging or preparing fails, the current spec is kept.
+func (ui *SwaggerUi) followUpstreams() {
+	doc, err := ui.aggregator.Merged(ui.upstreamInfo)
+	if err != nil {
+		return
+	}
+	data, err := encodeDocument(doc, ui.specFilename)
+	if err != nil {
+		return
+	}
+
+	p := ui.publisher
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	next, err := ui.prepareSuccessor(data)
+	if err != nil {
+		return
+	}
+	p.previous = p.active.Swap(next)
+	next.addToHistory()
+	ui.refresh()
+}
+
+// syncUpstreams publishes the current upstream specs of the spec selector.
+func (ui *SwaggerUi) syncUpstreams() {
+	for _, u := range ui.aggregator.upstreams {
+		data, ok := ui.aggregator.Spec(u.Name)
+		if !ok {
+			continue
+		}
+		name := upstreamFilename(u.Name, data)
+		ui.live.set(name, data)
+		// Drop the file of the other format, in case the service switched formats.
+		for _, ext := range []string{".yaml", ".json"} {
+			if stale := strings.TrimSuffix(name, specExt(data)) + ext; stale != name {
+				ui.live.remove(stale)
+			}
+		}
+	}
+	if ui.generatedInitializer {
+		ui.live.set(InitializerFilename, ui.state().renderInitializer())
+	}
+}
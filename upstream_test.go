@@ -0,0 +1,244 @@
+/*
+ *  upstream_test.go is part of github.com/mwmahlberg/swagger-ui project.
+ *
+ *  Copyright 2023 Markus W Mahlberg
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package swaggerui
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mwmahlberg/swagger-ui/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const storeSpec = `openapi: 3.0.3
+info:
+  title: Store
+  version: "1.0"
+paths:
+  /orders:
+    get:
+      responses:
+        "200":
+          description: The orders
+`
+
+// upstreamServer serves a spec with an ETag and counts full responses.
+type upstreamServer struct {
+	spec   atomic.Value
+	fail   atomic.Bool
+	served atomic.Int32
+	server *httptest.Server
+}
+
+func newUpstreamServer(spec string) *upstreamServer {
+	u := &upstreamServer{}
+	u.spec.Store(spec)
+	u.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if u.fail.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		spec := u.spec.Load().(string)
+		etag := `"` + string(rune('a'+len(spec)%26)) + `"`
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		u.served.Add(1)
+		io.WriteString(w, spec)
+	}))
+	return u
+}
+
+type UpstreamSuite struct {
+	suite.Suite
+	pets, store *upstreamServer
+	aggregator  *Aggregator
+}
+
+func (suite *UpstreamSuite) SetupTest() {
+	suite.pets = newUpstreamServer(refSpec)
+	suite.store = newUpstreamServer(storeSpec)
+	suite.aggregator = NewAggregator(
+		Upstream{Name: "pets", URL: suite.pets.server.URL, PathPrefix: "/pets-service"},
+		Upstream{Name: "store", URL: suite.store.server.URL, PathPrefix: "/store"})
+}
+
+func (suite *UpstreamSuite) TearDownTest() {
+	suite.pets.server.Close()
+	suite.store.server.Close()
+}
+
+func get(h http.Handler, path string) (int, string) {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec.Code, rec.Body.String()
+}
+
+func (suite *UpstreamSuite) TestETag() {
+	require.NoError(suite.T(), suite.aggregator.Refresh(context.Background()))
+	require.NoError(suite.T(), suite.aggregator.Refresh(context.Background()))
+	assert.EqualValues(suite.T(), 1, suite.pets.served.Load())
+
+	suite.pets.spec.Store(refSpec + "tags: []\n")
+	require.NoError(suite.T(), suite.aggregator.Refresh(context.Background()))
+	assert.EqualValues(suite.T(), 2, suite.pets.served.Load())
+	data, _ := suite.aggregator.Spec("pets")
+	assert.Contains(suite.T(), string(data), "tags: []")
+}
+
+func (suite *UpstreamSuite) TestKeepsLastGoodCopy() {
+	require.NoError(suite.T(), suite.aggregator.Refresh(context.Background()))
+
+	suite.store.fail.Store(true)
+	err := suite.aggregator.Refresh(context.Background())
+	assert.ErrorContains(suite.T(), err, "store")
+	assert.Error(suite.T(), suite.aggregator.Err("store"))
+	assert.NoError(suite.T(), suite.aggregator.Err("pets"))
+
+	data, ok := suite.aggregator.Spec("store")
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), storeSpec, string(data))
+
+	suite.store.fail.Store(false)
+	suite.store.spec.Store("not a spec")
+	assert.Error(suite.T(), suite.aggregator.Refresh(context.Background()))
+	data, _ = suite.aggregator.Spec("store")
+	assert.Equal(suite.T(), storeSpec, string(data))
+}
+
+func (suite *UpstreamSuite) TestSelector() {
+	suite.store.fail.Store(true)
+	_ = suite.aggregator.Refresh(context.Background())
+
+	ui, err := New(Upstreams(suite.aggregator))
+	require.NoError(suite.T(), err)
+
+	code, body := get(ui, "/upstreams/pets.yaml")
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Equal(suite.T(), refSpec, body)
+	code, _ = get(ui, "/upstreams/store.yaml")
+	assert.Equal(suite.T(), http.StatusNotFound, code)
+
+	_, initializer := get(ui, "/"+InitializerFilename)
+	assert.Contains(suite.T(), initializer, `{url: "./upstreams/pets.yaml", name: "pets"}`)
+	assert.NotContains(suite.T(), initializer, "store")
+
+	suite.store.fail.Store(false)
+	require.NoError(suite.T(), suite.aggregator.Refresh(context.Background()))
+
+	code, body = get(ui, "/upstreams/store.yaml")
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Equal(suite.T(), storeSpec, body)
+	_, initializer = get(ui, "/"+InitializerFilename)
+	assert.Contains(suite.T(), initializer, `{url: "./upstreams/store.yaml", name: "store"}`)
+
+	suite.store.spec.Store(`{"openapi": "3.0.3", "info": {"title": "Store", "version": "2.0"}, "paths": {}}`)
+	require.NoError(suite.T(), suite.aggregator.Refresh(context.Background()))
+	code, _ = get(ui, "/upstreams/store.json")
+	assert.Equal(suite.T(), http.StatusOK, code)
+	code, _ = get(ui, "/upstreams/store.yaml")
+	assert.Equal(suite.T(), http.StatusNotFound, code, "the file of the previous format is removed")
+}
+
+func (suite *UpstreamSuite) TestMerged() {
+	suite.store.fail.Store(true)
+	_ = suite.aggregator.Refresh(context.Background())
+
+	ui, err := New(MergedUpstreams(suite.aggregator, &openapi.Info{Title: "Portal", Version: "1.0"}))
+	require.NoError(suite.T(), err)
+
+	_, body := get(ui, "/"+DefaultSpecfileName)
+	assert.Contains(suite.T(), body, "/pets-service/pets")
+	assert.NotContains(suite.T(), body, "/store/orders")
+
+	suite.store.fail.Store(false)
+	require.NoError(suite.T(), suite.aggregator.Refresh(context.Background()))
+	_, body = get(ui, "/"+DefaultSpecfileName)
+	assert.Contains(suite.T(), body, "/store/orders")
+	assert.True(suite.T(), strings.HasPrefix(body, "openapi: 3.0.3"))
+}
+
+func (suite *UpstreamSuite) TestMergedProcessed() {
+	suite.store.fail.Store(true)
+	_ = suite.aggregator.Refresh(context.Background())
+
+	h, err := NewHistory()
+	require.NoError(suite.T(), err)
+	ui, err := New(MergedUpstreams(suite.aggregator, &openapi.Info{Title: "Portal", Version: "1.0"}),
+		Overlays([]byte(`overlay: 1.0.0
+info: {title: Internal, version: "1"}
+actions:
+  - target: $.info
+    update:
+      title: Internal portal
+`)), DereferencedSpec(""), SpecHistory(h))
+	require.NoError(suite.T(), err)
+
+	// check asserts that the spec and everything derived from it is overlaid and contains path.
+	check := func(path string) {
+		for _, name := range []string{DefaultSpecfileName, "swagger.deref.yaml"} {
+			_, body := get(ui, "/"+name)
+			assert.Contains(suite.T(), body, "Internal portal", name)
+			assert.Contains(suite.T(), body, path, name)
+		}
+		doc, err := ui.Document()
+		require.NoError(suite.T(), err)
+		assert.Equal(suite.T(), "Internal portal", doc.Info.Title)
+		assert.Contains(suite.T(), doc.Paths, path)
+		v, ok := h.Get("1.0")
+		require.True(suite.T(), ok)
+		assert.Contains(suite.T(), string(v.Spec), path)
+	}
+	check("/pets-service/pets")
+	_, body := get(ui, "/"+DefaultSpecfileName)
+	assert.NotContains(suite.T(), body, "/store/orders")
+
+	suite.store.fail.Store(false)
+	require.NoError(suite.T(), suite.aggregator.Refresh(context.Background()))
+	check("/store/orders")
+}
+
+func (suite *UpstreamSuite) TestRun() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		suite.aggregator.Run(ctx)
+		close(done)
+	}()
+	assert.Eventually(suite.T(), func() bool {
+		_, ok := suite.aggregator.Spec("store")
+		return ok
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+}
+
+func TestUpstream(t *testing.T) {
+	suite.Run(t, new(UpstreamSuite))
+}